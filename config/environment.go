@@ -11,9 +11,68 @@ var Env = struct {
 	DBName     string `mapstructure:"DB_NAME" validate:"required"`
 	Cors       string `mapstructure:"CORS"`
 	JWT_SECRET string `mapstructure:"JWT_SECRET"`
+	BlobDir    string `mapstructure:"BLOB_DIR"`
+	BlobURL    string `mapstructure:"BLOB_URL"`
+	// DeleteGraceDays is how long a deleted account can still be restored, 0 deletes immediately.
+	DeleteGraceDays int `mapstructure:"DELETE_GRACE_DAYS"`
+	// RevisionVotes is "keep" or "reset", reset clears the votes of a quote whose
+	// text changes more than RevisionResetSimilarity allows.
+	RevisionVotes           string  `mapstructure:"REVISION_VOTES"`
+	RevisionResetSimilarity float64 `mapstructure:"REVISION_RESET_SIMILARITY"`
+	// TrashRetentionDays is how long deleted quotes stay restorable before they are purged.
+	TrashRetentionDays int `mapstructure:"TRASH_RETENTION_DAYS"`
+	// DuplicateSimilarity is the estimated Jaccard similarity from which a new quote counts as a duplicate.
+	DuplicateSimilarity float64 `mapstructure:"DUPLICATE_SIMILARITY"`
+	// ContentFilterPath is the JSON file with the content filter rules, reloaded by POST /admin/content-filter/reload.
+	ContentFilterPath string `mapstructure:"CONTENT_FILTER_PATH"`
+	// ReportHideThreshold is the number of open reports that hides a quote until a moderator reviews it, 0 never hides.
+	ReportHideThreshold int `mapstructure:"REPORT_HIDE_THRESHOLD"`
+	// ReactionEmojis is the comma separated set of emoji users can react with.
+	ReactionEmojis string `mapstructure:"REACTION_EMOJIS"`
+	// DailyQuoteTimezone decides when the quote of the day changes.
+	DailyQuoteTimezone string `mapstructure:"DAILY_QUOTE_TIMEZONE"`
+	// DailyQuoteCooldownDays keeps a featured quote out of the draw for that many days.
+	DailyQuoteCooldownDays int `mapstructure:"DAILY_QUOTE_COOLDOWN_DAYS"`
+	// DailyQuoteCandidates is how many of the best voted quotes take part in the draw.
+	DailyQuoteCandidates int `mapstructure:"DAILY_QUOTE_CANDIDATES"`
+	// RandomSeenLimit is how many of the quotes served to a user GET /quote/random?track=true keeps out.
+	RandomSeenLimit int `mapstructure:"RANDOM_SEEN_LIMIT"`
+	// CardTemplatePath is the JSON file with the quote card templates, reloaded by POST /admin/card-templates/reload.
+	CardTemplatePath string `mapstructure:"CARD_TEMPLATE_PATH"`
+	// CardCacheSize is how many rendered quote cards are kept in memory, 0 disables the cache.
+	CardCacheSize int `mapstructure:"CARD_CACHE_SIZE"`
+	// FeedTitle names the site in the quote feeds.
+	FeedTitle string `mapstructure:"FEED_TITLE"`
+	// FeedSiteURL is where feed entries link to, empty links to this API.
+	FeedSiteURL string `mapstructure:"FEED_SITE_URL"`
+	// BodyLimit is the largest request body in bytes. POST /admin/quotes/import is streamed
+	// and takes files of any size, like `go run . import`.
+	BodyLimit int `mapstructure:"BODY_LIMIT"`
+	// MailIngestToken is the bearer token the mail server posts to POST /mail/inbound with, empty disables the endpoint.
+	MailIngestToken string `mapstructure:"MAIL_INGEST_TOKEN"`
+	// MaildirPath is a Maildir checked every minute for quotes sent by mail, empty disables the watcher.
+	MaildirPath string `mapstructure:"MAILDIR_PATH"`
 }{
-	Cors:       "*",
-	JWT_SECRET: "secret",
+	Cors:                    "*",
+	JWT_SECRET:              "secret",
+	BlobDir:                 "./uploads",
+	BlobURL:                 "/uploads",
+	DeleteGraceDays:         14,
+	RevisionVotes:           "keep",
+	RevisionResetSimilarity: 0.5,
+	TrashRetentionDays:      30,
+	DuplicateSimilarity:     0.8,
+	ContentFilterPath:       "./content_filter.json",
+	ReportHideThreshold:     5,
+	ReactionEmojis:          "👍,❤️,😂,😮,😢,🙏",
+	DailyQuoteTimezone:      "Asia/Bangkok",
+	DailyQuoteCooldownDays:  30,
+	DailyQuoteCandidates:    200,
+	RandomSeenLimit:         1000,
+	CardTemplatePath:        "./card_templates.json",
+	CardCacheSize:           500,
+	FeedTitle:               "Quotes",
+	BodyLimit:               4 * 1024 * 1024,
 }
 
 func NewAppInitEnvironment() {
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"backend/core/models"
+	"backend/core/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type adminHand struct {
+	adminService services.AdminService
+}
+
+func NewAdminHandler(adminService services.AdminService) adminHand {
+	return adminHand{
+		adminService: adminService,
+	}
+}
+
+func (h adminHand) GetUsers(c *fiber.Ctx) error {
+	result := h.adminService.GetUsers(c.Query("q"), c.QueryInt("page", 1), c.QueryInt("limit", 0))
+	return c.Status(result.Code).JSON(result)
+}
+
+func (h adminHand) GetUser(c *fiber.Ctx) error {
+	result := h.adminService.GetUser(c.Params("id"))
+	return c.Status(result.Code).JSON(result)
+}
+
+func (h adminHand) SuspendUser(c *fiber.Ctx) error {
+	body := models.HandSuspendUserBodyModel{}
+	c.BodyParser(&body)
+
+	result := h.adminService.SuspendUser(currentUserID(c), c.Params("id"), body.Reason)
+	return c.Status(result.Code).JSON(result)
+}
+
+func (h adminHand) UnsuspendUser(c *fiber.Ctx) error {
+	result := h.adminService.UnsuspendUser(currentUserID(c), c.Params("id"))
+	return c.Status(result.Code).JSON(result)
+}
+
+func (h adminHand) UpdateRole(c *fiber.Ctx) error {
+	body := models.HandUpdateRoleBodyModel{}
+	c.BodyParser(&body)
+
+	result := h.adminService.UpdateRole(currentUserID(c), c.Params("id"), body.Role)
+	return c.Status(result.Code).JSON(result)
+}
+
+func (h adminHand) SetTrusted(c *fiber.Ctx) error {
+	body := models.HandSetTrustedBodyModel{}
+	c.BodyParser(&body)
+
+	result := h.adminService.SetTrusted(currentUserID(c), c.Params("id"), body.Trusted)
+	return c.Status(result.Code).JSON(result)
+}
+
+func (h adminHand) ForcePasswordReset(c *fiber.Ctx) error {
+	result := h.adminService.ForcePasswordReset(currentUserID(c), c.Params("id"))
+	return c.Status(result.Code).JSON(result)
+}
+
+func (h adminHand) DeleteUser(c *fiber.Ctx) error {
+	result := h.adminService.DeleteUser(currentUserID(c), c.Params("id"))
+	return c.Status(result.Code).JSON(result)
+}
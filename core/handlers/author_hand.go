@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"backend/core/models"
+	"backend/core/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type authorHand struct {
+	authorService services.AuthorService
+}
+
+func NewAuthorHandler(authorService services.AuthorService) authorHand {
+	return authorHand{
+		authorService: authorService,
+	}
+}
+
+func (h authorHand) GetAuthors(c *fiber.Ctx) error {
+	result := h.authorService.GetAuthors(c.Query("q"), c.QueryInt("page", 1), c.QueryInt("limit", 0))
+	return c.Status(result.Code).JSON(result)
+}
+
+func (h authorHand) GetAuthor(c *fiber.Ctx) error {
+	result := h.authorService.GetAuthor(c.Params("id"))
+	return c.Status(result.Code).JSON(result)
+}
+
+func (h authorHand) UpdateAuthor(c *fiber.Ctx) error {
+	body := models.HandUpdateAuthorBodyModel{}
+	c.BodyParser(&body)
+
+	result := h.authorService.UpdateAuthor(c.Params("id"), body.Name, body.Bio)
+	return c.Status(result.Code).JSON(result)
+}
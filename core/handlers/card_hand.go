@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"backend/core/models"
+	"backend/core/services"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type cardHand struct {
+	cardService services.CardService
+}
+
+func NewCardHandler(cardService services.CardService) cardHand {
+	return cardHand{
+		cardService: cardService,
+	}
+}
+
+func (h cardHand) GetCardPNG(c *fiber.Ctx) error {
+	return h.sendCard(c, models.CardFormatPNG)
+}
+
+func (h cardHand) GetCardSVG(c *fiber.Ctx) error {
+	return h.sendCard(c, models.CardFormatSVG)
+}
+
+// sendCard answers with the image itself, clients revalidate with the ETag and
+// get 304 until the quote or its template changes.
+func (h cardHand) sendCard(c *fiber.Ctx, format string) error {
+	result := h.cardService.GetCard(c.Params("id"), format, c.Query("template"))
+	card, ok := result.Result.(models.CardModel)
+	if !ok {
+		return c.Status(result.Code).JSON(result)
+	}
+
+	c.Set(fiber.HeaderETag, card.ETag)
+	c.Set(fiber.HeaderLastModified, card.UpdateDate.UTC().Format(http.TimeFormat))
+	c.Set(fiber.HeaderCacheControl, "private, no-cache")
+	if notModified(c, card.ETag, card.UpdateDate) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+	c.Set(fiber.HeaderContentType, card.ContentType)
+	return c.Status(result.Code).Send(card.Body)
+}
+
+func (h cardHand) GetCardTemplates(c *fiber.Ctx) error {
+	result := h.cardService.GetCardTemplates()
+	return c.Status(result.Code).JSON(result)
+}
+
+func (h cardHand) ReloadCardTemplates(c *fiber.Ctx) error {
+	result := h.cardService.ReloadCardTemplates()
+	return c.Status(result.Code).JSON(result)
+}
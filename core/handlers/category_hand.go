@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"backend/core/models"
+	"backend/core/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type categoryHand struct {
+	categoryService services.CategoryService
+}
+
+func NewCategoryHandler(categoryService services.CategoryService) categoryHand {
+	return categoryHand{
+		categoryService: categoryService,
+	}
+}
+
+func (h categoryHand) GetCategories(c *fiber.Ctx) error {
+	result := h.categoryService.GetCategories()
+	return c.Status(result.Code).JSON(result)
+}
+
+func (h categoryHand) CreateCategory(c *fiber.Ctx) error {
+	body := models.HandCategoryBodyModel{}
+	c.BodyParser(&body)
+
+	parentID := ""
+	if body.ParentID != nil {
+		parentID = *body.ParentID
+	}
+	result := h.categoryService.CreateCategory(body.Name, parentID)
+	return c.Status(result.Code).JSON(result)
+}
+
+func (h categoryHand) UpdateCategory(c *fiber.Ctx) error {
+	body := models.HandCategoryBodyModel{}
+	c.BodyParser(&body)
+
+	result := h.categoryService.UpdateCategory(c.Params("id"), body.Name, body.ParentID)
+	return c.Status(result.Code).JSON(result)
+}
+
+func (h categoryHand) DeleteCategory(c *fiber.Ctx) error {
+	result := h.categoryService.DeleteCategory(c.Params("id"))
+	return c.Status(result.Code).JSON(result)
+}
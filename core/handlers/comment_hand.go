@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"backend/core/models"
+	"backend/core/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type commentHand struct {
+	commentService services.CommentService
+}
+
+func NewCommentHandler(commentService services.CommentService) commentHand {
+	return commentHand{
+		commentService: commentService,
+	}
+}
+
+func (h commentHand) GetComments(c *fiber.Ctx) error {
+	result := h.commentService.GetComments(c.Params("id"), c.QueryInt("page", 1), c.QueryInt("limit", 0))
+	return c.Status(result.Code).JSON(result)
+}
+
+func (h commentHand) CreateComment(c *fiber.Ctx) error {
+	body := models.HandCreateCommentBodyModel{}
+	c.BodyParser(&body)
+
+	result := h.commentService.CreateComment(currentUserID(c), c.Params("id"), body)
+	return c.Status(result.Code).JSON(result)
+}
+
+func (h commentHand) UpdateComment(c *fiber.Ctx) error {
+	body := models.HandUpdateCommentBodyModel{}
+	c.BodyParser(&body)
+
+	result := h.commentService.UpdateComment(currentUserID(c), c.Params("id"), body)
+	return c.Status(result.Code).JSON(result)
+}
+
+func (h commentHand) DeleteComment(c *fiber.Ctx) error {
+	result := h.commentService.DeleteComment(currentUserID(c), currentRole(c), c.Params("id"))
+	return c.Status(result.Code).JSON(result)
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"backend/core/models"
+	"backend/core/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type dailyQuoteHand struct {
+	dailyQuoteService services.DailyQuoteService
+}
+
+func NewDailyQuoteHandler(dailyQuoteService services.DailyQuoteService) dailyQuoteHand {
+	return dailyQuoteHand{
+		dailyQuoteService: dailyQuoteService,
+	}
+}
+
+func (h dailyQuoteHand) GetDailyQuote(c *fiber.Ctx) error {
+	result := h.dailyQuoteService.GetDailyQuote()
+	return c.Status(result.Code).JSON(result)
+}
+
+func (h dailyQuoteHand) SetDailyQuote(c *fiber.Ctx) error {
+	body := models.HandSetDailyQuoteBodyModel{}
+	c.BodyParser(&body)
+
+	result := h.dailyQuoteService.SetDailyQuote(currentUserID(c), body)
+	return c.Status(result.Code).JSON(result)
+}
+
+func (h dailyQuoteHand) GetDailyHistory(c *fiber.Ctx) error {
+	result := h.dailyQuoteService.GetDailyHistory(c.QueryInt("page", 1), c.QueryInt("limit", 0))
+	return c.Status(result.Code).JSON(result)
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"backend/core/models"
+	"backend/core/services"
+	"bufio"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type exportHand struct {
+	exportService services.ExportService
+}
+
+func NewExportHandler(exportService services.ExportService) exportHand {
+	return exportHand{
+		exportService: exportService,
+	}
+}
+
+// sendExport streams the export as a download. The status is sent before the
+// rows, so an error half way can only be logged and ends the file early.
+func sendExport(c *fiber.Ctx, result models.ResponseModel) error {
+	export, ok := result.Result.(models.ExportModel)
+	if !ok {
+		return c.Status(result.Code).JSON(result)
+	}
+
+	c.Set(fiber.HeaderContentType, export.ContentType)
+	c.Attachment(export.Filename)
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+	c.Status(result.Code).Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := export.Write(w); err != nil {
+			log.Println("export " + export.Kind + ": " + err.Error())
+		}
+		w.Flush()
+	})
+	return nil
+}
+
+func (h exportHand) ExportQuotes(c *fiber.Ctx) error {
+	query := models.HandExportQueryModel{}
+	c.QueryParser(&query)
+
+	return sendExport(c, h.exportService.ExportQuotes(currentRole(c), query))
+}
+
+func (h exportHand) ExportResults(c *fiber.Ctx) error {
+	query := models.HandExportQueryModel{}
+	c.QueryParser(&query)
+
+	return sendExport(c, h.exportService.ExportResults(currentRole(c), query))
+}
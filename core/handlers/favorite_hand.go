@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"backend/core/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type favoriteHand struct {
+	favoriteService services.FavoriteService
+}
+
+func NewFavoriteHandler(favoriteService services.FavoriteService) favoriteHand {
+	return favoriteHand{
+		favoriteService: favoriteService,
+	}
+}
+
+func (h favoriteHand) AddFavorite(c *fiber.Ctx) error {
+	result := h.favoriteService.AddFavorite(currentUserID(c), c.Params("id"))
+	return c.Status(result.Code).JSON(result)
+}
+
+func (h favoriteHand) RemoveFavorite(c *fiber.Ctx) error {
+	result := h.favoriteService.RemoveFavorite(currentUserID(c), c.Params("id"))
+	return c.Status(result.Code).JSON(result)
+}
+
+func (h favoriteHand) GetFavorites(c *fiber.Ctx) error {
+	result := h.favoriteService.GetFavorites(currentUserID(c), c.QueryInt("page", 1), c.QueryInt("limit", 0))
+	return c.Status(result.Code).JSON(result)
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"backend/core/models"
+	"backend/core/services"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type feedHand struct {
+	feedService services.FeedService
+}
+
+func NewFeedHandler(feedService services.FeedService) feedHand {
+	return feedHand{
+		feedService: feedService,
+	}
+}
+
+// notModified reports whether the client already has this version of the
+// feed, If-None-Match wins over If-Modified-Since as in RFC 9110.
+func notModified(c *fiber.Ctx, etag string, updated time.Time) bool {
+	if match := c.Get(fiber.HeaderIfNoneMatch); match != "" {
+		return etagMatch(match, etag)
+	}
+	since, err := http.ParseTime(c.Get(fiber.HeaderIfModifiedSince))
+	return err == nil && !updated.Truncate(time.Second).After(since)
+}
+
+// etagMatch reports whether an If-None-Match list names etag. The list is
+// compared weakly as RFC 9110 asks, so W/"x" matches "x".
+func etagMatch(list string, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, tag := range strings.Split(list, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+func (h feedHand) GetFeed(c *fiber.Ctx) error {
+	query := models.HandFeedQueryModel{}
+	c.QueryParser(&query)
+	query.BaseURL = c.BaseURL()
+	query.SelfURL = c.BaseURL() + c.OriginalURL()
+
+	result := h.feedService.GetFeed(c.Params("kind"), c.Params("format"), query)
+	feed, ok := result.Result.(models.FeedModel)
+	if !ok {
+		return c.Status(result.Code).JSON(result)
+	}
+
+	c.Set(fiber.HeaderETag, feed.ETag)
+	c.Set(fiber.HeaderLastModified, feed.Updated.UTC().Format(http.TimeFormat))
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	if notModified(c, feed.ETag, feed.Updated) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+	c.Set(fiber.HeaderContentType, feed.ContentType)
+	return c.Status(result.Code).Send(feed.Body)
+}
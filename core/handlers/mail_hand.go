@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"backend/core/services"
+	"bytes"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type mailHand struct {
+	mailService services.MailService
+}
+
+func NewMailHandler(mailService services.MailService) mailHand {
+	return mailHand{
+		mailService: mailService,
+	}
+}
+
+// IngestMail takes the raw message as the body, message/rfc822 as piped by
+// the mail server.
+func (h mailHand) IngestMail(c *fiber.Ctx) error {
+	result := h.mailService.IngestMail(bytes.NewReader(c.Body()))
+	return c.Status(result.Code).JSON(result)
+}
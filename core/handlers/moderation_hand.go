@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"backend/core/models"
+	"backend/core/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type moderationHand struct {
+	moderationService services.ModerationService
+}
+
+func NewModerationHandler(moderationService services.ModerationService) moderationHand {
+	return moderationHand{
+		moderationService: moderationService,
+	}
+}
+
+func (h moderationHand) GetQueue(c *fiber.Ctx) error {
+	result := h.moderationService.GetQueue(c.QueryInt("page", 1), c.QueryInt("limit", 0))
+	return c.Status(result.Code).JSON(result)
+}
+
+func (h moderationHand) ApproveQuote(c *fiber.Ctx) error {
+	result := h.moderationService.ApproveQuote(currentUserID(c), c.Params("id"))
+	return c.Status(result.Code).JSON(result)
+}
+
+func (h moderationHand) RejectQuote(c *fiber.Ctx) error {
+	body := models.HandRejectQuoteBodyModel{}
+	c.BodyParser(&body)
+
+	result := h.moderationService.RejectQuote(currentUserID(c), c.Params("id"), body.Reason)
+	return c.Status(result.Code).JSON(result)
+}
+
+func (h moderationHand) ReportQuote(c *fiber.Ctx) error {
+	body := models.HandReportQuoteBodyModel{}
+	c.BodyParser(&body)
+
+	result := h.moderationService.ReportQuote(currentUserID(c), c.Params("id"), body)
+	return c.Status(result.Code).JSON(result)
+}
+
+func (h moderationHand) GetReports(c *fiber.Ctx) error {
+	result := h.moderationService.GetReports(c.QueryInt("page", 1), c.QueryInt("limit", 0))
+	return c.Status(result.Code).JSON(result)
+}
+
+func (h moderationHand) DismissReports(c *fiber.Ctx) error {
+	result := h.moderationService.DismissReports(currentUserID(c), c.Params("id"))
+	return c.Status(result.Code).JSON(result)
+}
+
+func (h moderationHand) ActOnReports(c *fiber.Ctx) error {
+	body := models.HandActOnReportsBodyModel{}
+	c.BodyParser(&body)
+
+	result := h.moderationService.ActOnReports(currentUserID(c), c.Params("id"), body)
+	return c.Status(result.Code).JSON(result)
+}
@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"backend/core/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type notificationHand struct {
+	notificationService services.NotificationService
+}
+
+func NewNotificationHandler(notificationService services.NotificationService) notificationHand {
+	return notificationHand{
+		notificationService: notificationService,
+	}
+}
+
+func (h notificationHand) GetNotifications(c *fiber.Ctx) error {
+	result := h.notificationService.GetNotifications(currentUserID(c), c.QueryInt("page", 1), c.QueryInt("limit", 0))
+	return c.Status(result.Code).JSON(result)
+}
+
+func (h notificationHand) MarkNotificationsRead(c *fiber.Ctx) error {
+	result := h.notificationService.MarkNotificationsRead(currentUserID(c))
+	return c.Status(result.Code).JSON(result)
+}
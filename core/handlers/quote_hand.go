@@ -3,6 +3,11 @@ package handlers
 import (
 	"backend/core/models"
 	"backend/core/services"
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -18,7 +23,11 @@ func NewQuoteHandler(quoteService services.QuoteService) quoteHand {
 }
 
 func (h quoteHand) GetQuotes(c *fiber.Ctx) error {
-	result := h.quoteService.GetQuotes()
+	query := models.HandGetQuotesQueryModel{}
+	c.QueryParser(&query)
+	query.AcceptLanguage = c.Get(fiber.HeaderAcceptLanguage)
+
+	result := h.quoteService.GetQuotes(currentUserID(c), query)
 	return c.Status(result.Code).JSON(result)
 }
 
@@ -26,7 +35,7 @@ func (h quoteHand) CreateQuote(c *fiber.Ctx) error {
 	body := models.HandCreateQuoteBodyModel{}
 	c.BodyParser(&body)
 
-	result := h.quoteService.CreateQuote(body.Quote)
+	result := h.quoteService.CreateQuote(currentUserID(c), currentTrusted(c), body)
 	return c.Status(result.Code).JSON(result)
 }
 
@@ -34,12 +43,151 @@ func (h quoteHand) UpdateQuote(c *fiber.Ctx) error {
 	body := models.HandUpdateQuoteBodyModel{}
 	c.BodyParser(&body)
 
-	result := h.quoteService.UpdateQuote(c.Params("id"), body.Quote, body.Vote)
+	result := h.quoteService.UpdateQuote(currentUserID(c), currentRole(c), currentTrusted(c), c.Params("id"), body)
+	return c.Status(result.Code).JSON(result)
+}
+
+func (h quoteHand) GetRevisions(c *fiber.Ctx) error {
+	result := h.quoteService.GetRevisions(c.Params("id"), c.QueryInt("from", 0), c.QueryInt("to", 0))
+	return c.Status(result.Code).JSON(result)
+}
+
+func (h quoteHand) RevertQuote(c *fiber.Ctx) error {
+	rev, err := c.ParamsInt("rev")
+	if err != nil {
+		return c.Status(400).JSON(models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "rev invalid",
+			Result:  nil,
+		})
+	}
+	result := h.quoteService.RevertQuote(currentUserID(c), currentRole(c), currentTrusted(c), c.Params("id"), rev)
 	return c.Status(result.Code).JSON(result)
 }
 
 func (h quoteHand) DeleteQuote(c *fiber.Ctx) error {
-	result := h.quoteService.DeleteQuote(c.Params("id"))
+	result := h.quoteService.DeleteQuote(currentUserID(c), currentRole(c), c.Params("id"))
+
+	return c.Status(result.Code).JSON(result)
+}
+
+func (h quoteHand) GetTrash(c *fiber.Ctx) error {
+	result := h.quoteService.GetTrash(c.QueryInt("page", 1), c.QueryInt("limit", 0))
+	return c.Status(result.Code).JSON(result)
+}
+
+func (h quoteHand) RestoreQuote(c *fiber.Ctx) error {
+	result := h.quoteService.RestoreQuote(c.Params("id"))
+	return c.Status(result.Code).JSON(result)
+}
+
+func (h quoteHand) SearchQuotes(c *fiber.Ctx) error {
+	result := h.quoteService.SearchQuotes(c.Query("q"), c.QueryInt("limit", 0))
+	return c.Status(result.Code).JSON(result)
+}
+
+func (h quoteHand) GetDuplicates(c *fiber.Ctx) error {
+	result := h.quoteService.GetDuplicates()
+	return c.Status(result.Code).JSON(result)
+}
+
+func (h quoteHand) MergeQuotes(c *fiber.Ctx) error {
+	body := models.HandMergeQuotesBodyModel{}
+	c.BodyParser(&body)
+
+	result := h.quoteService.MergeQuotes(currentUserID(c), body.TargetID, body.SourceIDs)
+	return c.Status(result.Code).JSON(result)
+}
+
+// ImportQuotes takes the file as the "file" field of a multipart form or as
+// the raw request body. The body is read as a stream so BodyLimit does not cap
+// the file, fasthttp only streams bodies over the limit and buffers the rest.
+func (h quoteHand) ImportQuotes(c *fiber.Ctx) error {
+	query := models.HandImportQuotesQueryModel{}
+	c.QueryParser(&query)
+
+	body := c.Context().RequestBodyStream()
+	if body == nil {
+		body = bytes.NewReader(c.Body())
+	} else {
+		// a failed import stops reading, what is left must not be taken for the next request
+		c.Context().SetConnectionClose()
+	}
+	upload := models.UploadFileModel{
+		ContentType: c.Get(fiber.HeaderContentType),
+		Size:        int64(c.Request().Header.ContentLength()),
+		Content:     body,
+	}
+	if strings.HasPrefix(upload.ContentType, fiber.MIMEMultipartForm) {
+		part, err := formFilePart(body, string(c.Request().Header.MultipartFormBoundary()), "file")
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ResponseModel{
+				Status:  false,
+				Code:    fiber.StatusBadRequest,
+				Message: err.Error(),
+				Result:  nil,
+			})
+		}
+		defer part.Close()
+		// the size of a part is only known once it is read
+		upload = models.UploadFileModel{
+			Filename:    part.FileName(),
+			ContentType: part.Header.Get("Content-Type"),
+			Content:     part,
+		}
+	}
+
+	result := h.quoteService.ImportQuotes(currentUserID(c), query.Format, query.DryRun, upload)
+	return c.Status(result.Code).JSON(result)
+}
+
+// formFilePart reads a multipart body up to the part of the named field, the
+// part is read from the body as it arrives.
+func formFilePart(body io.Reader, boundary string, name string) (*multipart.Part, error) {
+	if boundary == "" {
+		return nil, errors.New("multipart boundary not found")
+	}
+	reader := multipart.NewReader(body, boundary)
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil, errors.New("file not found")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if part.FormName() == name {
+			return part, nil
+		}
+		part.Close()
+	}
+}
+
+func (h quoteHand) GetContentFilter(c *fiber.Ctx) error {
+	result := h.quoteService.GetContentFilter()
+	return c.Status(result.Code).JSON(result)
+}
+
+func (h quoteHand) ReloadContentFilter(c *fiber.Ctx) error {
+	result := h.quoteService.ReloadContentFilter()
+	return c.Status(result.Code).JSON(result)
+}
+
+func (h quoteHand) GetTranslations(c *fiber.Ctx) error {
+	result := h.quoteService.GetTranslations(c.Params("id"))
+	return c.Status(result.Code).JSON(result)
+}
+
+func (h quoteHand) AddTranslation(c *fiber.Ctx) error {
+	body := models.HandAddTranslationBodyModel{}
+	c.BodyParser(&body)
+
+	result := h.quoteService.AddTranslation(currentUserID(c), currentRole(c), c.Params("id"), body)
+	return c.Status(result.Code).JSON(result)
+}
 
+func (h quoteHand) RemoveTranslation(c *fiber.Ctx) error {
+	result := h.quoteService.RemoveTranslation(currentUserID(c), currentRole(c), c.Params("id"))
 	return c.Status(result.Code).JSON(result)
 }
@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"backend/core/models"
+	"backend/core/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type randomQuoteHand struct {
+	randomQuoteService services.RandomQuoteService
+}
+
+func NewRandomQuoteHandler(randomQuoteService services.RandomQuoteService) randomQuoteHand {
+	return randomQuoteHand{
+		randomQuoteService: randomQuoteService,
+	}
+}
+
+func (h randomQuoteHand) GetRandomQuotes(c *fiber.Ctx) error {
+	query := models.HandRandomQuotesQueryModel{}
+	c.QueryParser(&query)
+
+	result := h.randomQuoteService.GetRandomQuotes(currentUserID(c), query)
+	return c.Status(result.Code).JSON(result)
+}
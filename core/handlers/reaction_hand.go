@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"backend/core/models"
+	"backend/core/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type reactionHand struct {
+	reactionService services.ReactionService
+}
+
+func NewReactionHandler(reactionService services.ReactionService) reactionHand {
+	return reactionHand{
+		reactionService: reactionService,
+	}
+}
+
+func (h reactionHand) GetReactions(c *fiber.Ctx) error {
+	result := h.reactionService.GetReactions(c.Params("id"))
+	return c.Status(result.Code).JSON(result)
+}
+
+func (h reactionHand) AddReaction(c *fiber.Ctx) error {
+	body := models.HandReactQuoteBodyModel{}
+	c.BodyParser(&body)
+
+	result := h.reactionService.AddReaction(currentUserID(c), c.Params("id"), body.Emoji)
+	return c.Status(result.Code).JSON(result)
+}
+
+// RemoveReaction takes the emoji from ?emoji= because emoji in a path segment
+// reach the handler percent-encoded.
+func (h reactionHand) RemoveReaction(c *fiber.Ctx) error {
+	result := h.reactionService.RemoveReaction(currentUserID(c), c.Params("id"), c.Query("emoji"))
+	return c.Status(result.Code).JSON(result)
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"backend/core/models"
+	"backend/core/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type tagHand struct {
+	tagService services.TagService
+}
+
+func NewTagHandler(tagService services.TagService) tagHand {
+	return tagHand{
+		tagService: tagService,
+	}
+}
+
+func (h tagHand) GetTags(c *fiber.Ctx) error {
+	result := h.tagService.GetTags()
+	return c.Status(result.Code).JSON(result)
+}
+
+func (h tagHand) CreateTag(c *fiber.Ctx) error {
+	body := models.HandTagBodyModel{}
+	c.BodyParser(&body)
+
+	result := h.tagService.CreateTag(body.Name)
+	return c.Status(result.Code).JSON(result)
+}
+
+func (h tagHand) RenameTag(c *fiber.Ctx) error {
+	body := models.HandTagBodyModel{}
+	c.BodyParser(&body)
+
+	result := h.tagService.RenameTag(c.Params("id"), body.Name)
+	return c.Status(result.Code).JSON(result)
+}
+
+func (h tagHand) MergeTag(c *fiber.Ctx) error {
+	body := models.HandMergeTagBodyModel{}
+	c.BodyParser(&body)
+
+	result := h.tagService.MergeTag(c.Params("id"), body.TargetID)
+	return c.Status(result.Code).JSON(result)
+}
@@ -1,8 +1,14 @@
 package handlers
 
 import (
+	"archive/zip"
 	"backend/core/models"
 	"backend/core/services"
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -36,3 +42,118 @@ func (h userHand) UpdateVote(c *fiber.Ctx) error {
 	result := h.userService.UpdateVote(c.Params("id"), c.Params("qouteID"))
 	return c.Status(result.Code).JSON(result)
 }
+
+// currentUserID returns the id stored by the AccessToken middleware.
+func currentUserID(c *fiber.Ctx) string {
+	id, _ := c.Locals("user_id").(string)
+	return id
+}
+
+func currentRole(c *fiber.Ctx) string {
+	role, _ := c.Locals("role").(string)
+	return role
+}
+
+func currentTrusted(c *fiber.Ctx) bool {
+	trusted, _ := c.Locals("trusted").(bool)
+	return trusted
+}
+
+func (h userHand) GetMe(c *fiber.Ctx) error {
+	result := h.userService.GetMe(currentUserID(c))
+	return c.Status(result.Code).JSON(result)
+}
+
+func (h userHand) UpdateProfile(c *fiber.Ctx) error {
+	body := models.HandUpdateProfileBodyModel{}
+	c.BodyParser(&body)
+
+	profile := models.UpdateProfileModel{
+		DisplayName: body.DisplayName,
+		Bio:         body.Bio,
+	}
+	if file, err := c.FormFile("avatar"); err == nil {
+		content, err := file.Open()
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ResponseModel{
+				Status:  false,
+				Code:    fiber.StatusBadRequest,
+				Message: err.Error(),
+				Result:  nil,
+			})
+		}
+		defer content.Close()
+		profile.Avatar = &models.UploadFileModel{
+			Filename:    file.Filename,
+			ContentType: file.Header.Get("Content-Type"),
+			Size:        file.Size,
+			Content:     content,
+		}
+	}
+
+	result := h.userService.UpdateProfile(currentUserID(c), profile)
+	return c.Status(result.Code).JSON(result)
+}
+
+func (h userHand) ChangePassword(c *fiber.Ctx) error {
+	body := models.HandChangePasswordBodyModel{}
+	c.BodyParser(&body)
+
+	result := h.userService.ChangePassword(currentUserID(c), body.CurrentPassword, body.NewPassword)
+	return c.Status(result.Code).JSON(result)
+}
+
+// exportFiles names a JSON file after every field of the export, so data a
+// feature adds to UserExportModel is always in the download.
+func exportFiles(data models.UserExportModel) (names []string, contents []interface{}) {
+	v := reflect.ValueOf(data)
+	for i := 0; i < v.NumField(); i++ {
+		if _, ok := v.Field(i).Interface().(time.Time); ok {
+			continue
+		}
+		name, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("json"), ",")
+		names = append(names, name+".json")
+		contents = append(contents, v.Field(i).Interface())
+	}
+	return names, contents
+}
+
+func (h userHand) ExportData(c *fiber.Ctx) error {
+	result := h.userService.ExportData(currentUserID(c))
+	data, ok := result.Result.(models.UserExportModel)
+	if !ok || c.Query("format") != "zip" {
+		return c.Status(result.Code).JSON(result)
+	}
+
+	buf := new(bytes.Buffer)
+	w := zip.NewWriter(buf)
+	names, contents := exportFiles(data)
+	for i, name := range names {
+		f, err := w.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: data.ExportDate})
+		if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		}
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "    ")
+		if err := enc.Encode(contents[i]); err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		}
+	}
+	if err := w.Close(); err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	c.Set(fiber.HeaderContentType, "application/zip")
+	c.Attachment("export-" + data.Profile.ID + ".zip")
+	return c.Status(result.Code).Send(buf.Bytes())
+}
+
+func (h userHand) DeleteAccount(c *fiber.Ctx) error {
+	result := h.userService.DeleteAccount(currentUserID(c))
+	return c.Status(result.Code).JSON(result)
+}
+
+func (h userHand) CancelDeleteAccount(c *fiber.Ctx) error {
+	result := h.userService.CancelDeleteAccount(currentUserID(c))
+	return c.Status(result.Code).JSON(result)
+}
@@ -2,13 +2,23 @@ package middlewares
 
 import (
 	"backend/config"
+	"backend/core/models"
+	"backend/core/repositories"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func AccessToken(c *fiber.Ctx) error {
+// AccessToken validates the bearer token and loads its user, so suspended
+// accounts are rejected even while their token is still valid.
+func AccessToken(userRepo repositories.UserRepository) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return accessToken(c, userRepo)
+	}
+}
+
+func accessToken(c *fiber.Ctx, userRepo repositories.UserRepository) error {
 
 	tokenString := c.Get("Authorization")
 	if tokenString == "" {
@@ -61,6 +71,51 @@ func AccessToken(c *fiber.Ctx) error {
 		})
 	}
 
-	c.Locals("user_id", claims["user_id"])
+	userID, _ := claims["user_id"].(string)
+	user, err := userRepo.GetUserByID(userID)
+	if userID == "" || err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"code":    fiber.StatusUnauthorized,
+			"status":  false,
+			"message": "unauthorized: user not found",
+		})
+	}
+
+	if user.Suspended {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"code":    fiber.StatusForbidden,
+			"status":  false,
+			"message": "forbidden: account suspended",
+		})
+	}
+
+	if user.MustChangePassword && !passwordResetAllowed(c) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"code":    fiber.StatusForbidden,
+			"status":  false,
+			"message": "forbidden: password reset required",
+		})
+	}
+
+	role := user.Role
+	if role == "" {
+		role = models.RoleUser
+	}
+	c.Locals("user_id", user.ID)
+	c.Locals("role", role)
+	// trusted users and staff publish quotes without going through moderation
+	c.Locals("trusted", user.Trusted || role == models.RoleModerator || role == models.RoleAdmin)
 	return c.Next()
 }
+
+// passwordResetAllowed lists the routes a user can still reach while an admin
+// forced password reset is pending.
+func passwordResetAllowed(c *fiber.Ctx) bool {
+	switch {
+	case c.Method() == fiber.MethodGet && c.Path() == "/users/me":
+		return true
+	case c.Method() == fiber.MethodPost && c.Path() == "/users/me/password":
+		return true
+	}
+	return false
+}
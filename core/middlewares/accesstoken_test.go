@@ -0,0 +1,141 @@
+package middlewares_test
+
+import (
+	"backend/config"
+	"backend/core/middlewares"
+	"backend/core/models"
+	"backend/core/repositories"
+	"backend/utils"
+	"errors"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_AccessToken(t *testing.T) {
+	config.Env.JWT_SECRET = "secret"
+	userRepo := repositories.NewUserRepositoryMock()
+	userRepo.On("GetUserByID", "active").Return(models.UserModel{ID: "active"}, nil)
+	userRepo.On("GetUserByID", "moderator").Return(models.UserModel{ID: "moderator", Role: models.RoleModerator}, nil)
+	userRepo.On("GetUserByID", "suspended").Return(models.UserModel{ID: "suspended", Suspended: true}, nil)
+	userRepo.On("GetUserByID", "reset").Return(models.UserModel{ID: "reset", MustChangePassword: true}, nil)
+	userRepo.On("GetUserByID", "gone").Return(models.UserModel{}, errors.New("mongo: no documents in result"))
+
+	app := fiber.New()
+	app.Use(middlewares.AccessToken(userRepo))
+	handler := func(c *fiber.Ctx) error {
+		return c.SendString(fmt.Sprintf("%v %v %v", c.Locals("user_id"), c.Locals("role"), c.Locals("trusted")))
+	}
+	app.Get("/users/me", handler)
+	app.Post("/users/me/password", handler)
+	app.Get("/quotes", handler)
+
+	token := func(id string) string {
+		signed, err := utils.GenerateToken(id, id+"@example.com")
+		assert.NoError(t, err)
+		return "Bearer " + signed
+	}
+
+	cases := []struct {
+		Name          string
+		Method        string
+		Path          string
+		Authorization string
+		Status        int
+		Body          string
+	}{
+		{
+			Name:   "no token",
+			Method: fiber.MethodGet,
+			Path:   "/quotes",
+			Status: fiber.StatusUnauthorized,
+		},
+		{
+			Name:          "not a bearer token",
+			Method:        fiber.MethodGet,
+			Path:          "/quotes",
+			Authorization: "Basic dXNlcjpwYXNz",
+			Status:        fiber.StatusUnauthorized,
+		},
+		{
+			Name:          "invalid token",
+			Method:        fiber.MethodGet,
+			Path:          "/quotes",
+			Authorization: "Bearer not.a.token",
+			Status:        fiber.StatusUnauthorized,
+		},
+		{
+			Name:          "user no longer exists",
+			Method:        fiber.MethodGet,
+			Path:          "/quotes",
+			Authorization: token("gone"),
+			Status:        fiber.StatusUnauthorized,
+		},
+		{
+			Name:          "active user",
+			Method:        fiber.MethodGet,
+			Path:          "/quotes",
+			Authorization: token("active"),
+			Status:        fiber.StatusOK,
+			Body:          "active user false",
+		},
+		{
+			Name:          "moderator is trusted",
+			Method:        fiber.MethodGet,
+			Path:          "/quotes",
+			Authorization: token("moderator"),
+			Status:        fiber.StatusOK,
+			Body:          "moderator moderator true",
+		},
+		{
+			Name:          "suspended user with a valid token",
+			Method:        fiber.MethodGet,
+			Path:          "/users/me",
+			Authorization: token("suspended"),
+			Status:        fiber.StatusForbidden,
+		},
+		{
+			Name:          "forced password change blocks other routes",
+			Method:        fiber.MethodGet,
+			Path:          "/quotes",
+			Authorization: token("reset"),
+			Status:        fiber.StatusForbidden,
+		},
+		{
+			Name:          "forced password change can read the profile",
+			Method:        fiber.MethodGet,
+			Path:          "/users/me",
+			Authorization: token("reset"),
+			Status:        fiber.StatusOK,
+			Body:          "reset user false",
+		},
+		{
+			Name:          "forced password change can change the password",
+			Method:        fiber.MethodPost,
+			Path:          "/users/me/password",
+			Authorization: token("reset"),
+			Status:        fiber.StatusOK,
+			Body:          "reset user false",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			req := httptest.NewRequest(c.Method, c.Path, nil)
+			if c.Authorization != "" {
+				req.Header.Set("Authorization", c.Authorization)
+			}
+			res, err := app.Test(req, -1)
+			assert.NoError(t, err)
+			assert.Equal(t, c.Status, res.StatusCode)
+			if c.Body != "" {
+				body, _ := io.ReadAll(res.Body)
+				assert.Equal(t, c.Body, string(body))
+			}
+		})
+	}
+}
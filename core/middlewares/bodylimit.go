@@ -0,0 +1,59 @@
+package middlewares
+
+import (
+	"backend/config"
+	"io"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// BodyLimit keeps config.Env.BodyLimit for every route but the streamed ones.
+// With StreamRequestBody fasthttp hands over larger or chunked bodies as a
+// stream that c.Body() would read whole, so they are buffered here up to the
+// limit. Handlers of streamed paths read c.Context().RequestBodyStream() and
+// close the connection, fasthttp does not skip what they leave unread.
+func BodyLimit(streamed ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !c.Request().IsBodyStream() {
+			return c.Next()
+		}
+		if streamedPath(c.Path(), streamed) {
+			return c.Next()
+		}
+		limit := config.Env.BodyLimit
+		body, err := io.ReadAll(io.LimitReader(c.Context().RequestBodyStream(), int64(limit)+1))
+		if err != nil || len(body) > limit {
+			// the rest of the body is left unread, it must not be taken for the next request
+			c.Context().SetConnectionClose()
+		}
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"code":    fiber.StatusBadRequest,
+				"status":  false,
+				"message": err.Error(),
+			})
+		}
+		if len(body) > limit {
+			return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{
+				"code":    fiber.StatusRequestEntityTooLarge,
+				"status":  false,
+				"message": "request body is too large",
+			})
+		}
+		c.Request().SetBody(body)
+		return c.Next()
+	}
+}
+
+// streamedPath matches the path the way the router does, without minding case
+// or a trailing slash. c.Path() holds no query string.
+func streamedPath(path string, streamed []string) bool {
+	path = strings.TrimSuffix(path, "/")
+	for _, p := range streamed {
+		if strings.EqualFold(path, strings.TrimSuffix(p, "/")) {
+			return true
+		}
+	}
+	return false
+}
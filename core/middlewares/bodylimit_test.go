@@ -0,0 +1,105 @@
+package middlewares_test
+
+import (
+	"backend/config"
+	"backend/core/middlewares"
+	"bytes"
+	"io"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_BodyLimit(t *testing.T) {
+	limit := 1024
+	config.Env.BodyLimit = limit
+	app := fiber.New(fiber.Config{
+		BodyLimit:                    limit,
+		StreamRequestBody:            true,
+		DisablePreParseMultipartForm: true,
+	})
+	app.Use(middlewares.BodyLimit("/admin/quotes/import"))
+	app.Post("/quotes", func(c *fiber.Ctx) error {
+		return c.SendString(strconv.Itoa(len(c.Body())))
+	})
+	app.Post("/admin/quotes/import", func(c *fiber.Ctx) error {
+		n, err := io.Copy(io.Discard, c.Context().RequestBodyStream())
+		if err != nil {
+			return err
+		}
+		c.Context().SetConnectionClose()
+		return c.SendString(strconv.FormatInt(n, 10))
+	})
+
+	cases := []struct {
+		Name   string
+		Path   string
+		Size   int
+		Status int
+		Body   string
+	}{
+		{
+			Name:   "small body on a normal route",
+			Path:   "/quotes",
+			Size:   100,
+			Status: fiber.StatusOK,
+			Body:   "100",
+		},
+		{
+			Name:   "body at the limit on a normal route",
+			Path:   "/quotes",
+			Size:   limit,
+			Status: fiber.StatusOK,
+			Body:   strconv.Itoa(limit),
+		},
+		{
+			Name:   "body over the limit on a normal route",
+			Path:   "/quotes",
+			Size:   64 * limit,
+			Status: fiber.StatusRequestEntityTooLarge,
+		},
+		{
+			Name:   "large import",
+			Path:   "/admin/quotes/import",
+			Size:   64 * limit,
+			Status: fiber.StatusOK,
+			Body:   strconv.Itoa(64 * limit),
+		},
+		{
+			Name:   "import with a trailing slash",
+			Path:   "/admin/quotes/import/",
+			Size:   64 * limit,
+			Status: fiber.StatusOK,
+			Body:   strconv.Itoa(64 * limit),
+		},
+		{
+			Name:   "import with a query string",
+			Path:   "/admin/quotes/import?format=csv",
+			Size:   64 * limit,
+			Status: fiber.StatusOK,
+			Body:   strconv.Itoa(64 * limit),
+		},
+		{
+			Name:   "import path as a prefix of another route",
+			Path:   "/admin/quotes/import-old",
+			Size:   64 * limit,
+			Status: fiber.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			req := httptest.NewRequest(fiber.MethodPost, c.Path, bytes.NewReader(bytes.Repeat([]byte("a"), c.Size)))
+			res, err := app.Test(req, -1)
+			assert.NoError(t, err)
+			assert.Equal(t, c.Status, res.StatusCode)
+			if c.Body != "" {
+				body, _ := io.ReadAll(res.Body)
+				assert.Equal(t, c.Body, string(body))
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package middlewares
+
+import (
+	"backend/config"
+	"crypto/subtle"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// MailToken lets the mail server through with the shared token of
+// config.Env.MailIngestToken, no token is accepted while it is unset.
+func MailToken() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token := strings.TrimSpace(strings.TrimPrefix(c.Get("Authorization"), "Bearer "))
+		expected := config.Env.MailIngestToken
+		if expected == "" || subtle.ConstantTimeCompare([]byte(token), []byte(expected)) != 1 {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"code":    fiber.StatusUnauthorized,
+				"status":  false,
+				"message": "unauthorized: invalid mail token",
+			})
+		}
+		return c.Next()
+	}
+}
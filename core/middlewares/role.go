@@ -0,0 +1,22 @@
+package middlewares
+
+import (
+	"backend/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequireRole must run after AccessToken, it only lets the given roles through.
+func RequireRole(roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		role, _ := c.Locals("role").(string)
+		if !utils.StringInSlice(roles, role) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"code":    fiber.StatusForbidden,
+				"status":  false,
+				"message": "forbidden: insufficient role",
+			})
+		}
+		return c.Next()
+	}
+}
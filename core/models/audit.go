@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+type AuditModel struct {
+	ID         string    `json:"id" bson:"id"`
+	UserID     string    `json:"user_id" bson:"user_id"`
+	Action     string    `json:"action" bson:"action"`
+	Detail     string    `json:"detail" bson:"detail"`
+	CreateDate time.Time `json:"create_date" bson:"create_date"`
+}
+
+type CreateAuditModel struct {
+	ID         string    `json:"id" bson:"id"`
+	UserID     string    `json:"user_id" bson:"user_id"`
+	Action     string    `json:"action" bson:"action"`
+	Detail     string    `json:"detail" bson:"detail"`
+	CreateDate time.Time `json:"create_date" bson:"create_date"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+type AuthorModel struct {
+	ID         string    `json:"id" bson:"id"`
+	Name       string    `json:"name" bson:"name"`
+	NameKey    string    `json:"-" bson:"name_key"`
+	Bio        string    `json:"bio" bson:"bio"`
+	CreateDate time.Time `json:"create_date" bson:"create_date"`
+	UpdateDate time.Time `json:"update_date" bson:"update_date"`
+}
+
+type CreateAuthorModel struct {
+	ID         string    `json:"id" bson:"id"`
+	Name       string    `json:"name" bson:"name"`
+	NameKey    string    `json:"-" bson:"name_key"`
+	Bio        string    `json:"bio" bson:"bio"`
+	CreateDate time.Time `json:"create_date" bson:"create_date"`
+	UpdateDate time.Time `json:"update_date" bson:"update_date"`
+}
+
+type UpdateAuthorModel struct {
+	Name       string    `json:"name" bson:"name,omitempty"`
+	NameKey    string    `json:"-" bson:"name_key,omitempty"`
+	Bio        *string   `json:"bio" bson:"bio,omitempty"`
+	UpdateDate time.Time `json:"update_date" bson:"update_date"`
+}
+
+type AuthorListModel struct {
+	Authors []AuthorModel `json:"authors"`
+	Total   int64         `json:"total"`
+	Page    int           `json:"page"`
+	Limit   int           `json:"limit"`
+}
+
+// AuthorPageModel is an author with their best voted quotes.
+type AuthorPageModel struct {
+	Author AuthorModel  `json:"author"`
+	Quotes []QuoteModel `json:"quotes"`
+	Total  int64        `json:"total"`
+}
+
+type HandUpdateAuthorBodyModel struct {
+	Name string  `json:"name"`
+	Bio  *string `json:"bio"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+const (
+	CardFormatPNG = "png"
+	CardFormatSVG = "svg"
+)
+
+// CardTemplateDefault is the template used when none is asked for, the
+// templates file can override it like any other template.
+const CardTemplateDefault = "default"
+
+// CardTemplateModel configures how a quote card looks, sizes are in pixels and
+// colors are #RRGGBB or #RRGGBBAA. Zero fields fall back to the default template.
+type CardTemplateModel struct {
+	Width      int    `json:"width"`
+	Height     int    `json:"height"`
+	Padding    int    `json:"padding"`
+	Background string `json:"background"`
+	Foreground string `json:"foreground"`
+	Accent     string `json:"accent"`
+	// FontSize shrinks step by step down to MinFontSize until a long quote fits
+	FontSize      float64 `json:"font_size"`
+	MinFontSize   float64 `json:"min_font_size"`
+	SmallFontSize float64 `json:"small_font_size"`
+	LineHeight    float64 `json:"line_height"`
+	// Align is "left" or "center"
+	Align string `json:"align"`
+	// VoteFormat is the vote line with %d for the count, e.g. "%d votes"
+	VoteFormat string `json:"vote_format"`
+}
+
+// CardModel is a rendered quote card, Body holds the image itself.
+type CardModel struct {
+	QuoteID     string    `json:"quote_id"`
+	Template    string    `json:"template"`
+	Format      string    `json:"format"`
+	ContentType string    `json:"content_type"`
+	ETag        string    `json:"etag"`
+	UpdateDate  time.Time `json:"update_date"`
+	Body        []byte    `json:"-"`
+}
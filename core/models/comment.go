@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+type HandCreateCommentBodyModel struct {
+	Body     string `json:"body"`
+	ParentID string `json:"parent_id"`
+}
+
+type HandUpdateCommentBodyModel struct {
+	Body string `json:"body"`
+}
+
+// CommentModel is a comment on a quote, a reply has the id of its top level
+// comment in ParentID and replies can not be replied to.
+type CommentModel struct {
+	ID         string     `json:"id" bson:"id"`
+	QuoteID    string     `json:"quote_id" bson:"quote_id"`
+	ParentID   string     `json:"parent_id" bson:"parent_id"`
+	UserID     string     `json:"user_id" bson:"user_id"`
+	Body       string     `json:"body" bson:"body"`
+	ReplyCount int        `json:"reply_count" bson:"reply_count"`
+	EditDate   *time.Time `json:"edit_date,omitempty" bson:"edit_date,omitempty"`
+	DeletedAt  *time.Time `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
+	DeletedBy  string     `json:"deleted_by,omitempty" bson:"deleted_by,omitempty"`
+	CreateDate time.Time  `json:"create_date" bson:"create_date"`
+}
+
+type CreateCommentModel struct {
+	ID         string    `json:"id" bson:"id"`
+	QuoteID    string    `json:"quote_id" bson:"quote_id"`
+	ParentID   string    `json:"parent_id" bson:"parent_id"`
+	UserID     string    `json:"user_id" bson:"user_id"`
+	Body       string    `json:"body" bson:"body"`
+	ReplyCount int       `json:"reply_count" bson:"reply_count"`
+	CreateDate time.Time `json:"create_date" bson:"create_date"`
+}
+
+type CommentThreadModel struct {
+	CommentModel `bson:",inline"`
+	Replies      []CommentModel `json:"replies"`
+}
+
+type CommentListModel struct {
+	Comments []CommentThreadModel `json:"comments"`
+	Total    int64                `json:"total"`
+	Page     int                  `json:"page"`
+	Limit    int                  `json:"limit"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// DailyPickedAuto marks a quote of the day chosen by the selection algorithm,
+// otherwise PickedBy holds the id of the admin who set it.
+const DailyPickedAuto = "auto"
+
+// DailyDateFormat is the format of DailyQuoteModel.Date, a calendar day in the
+// configured daily quote timezone.
+const DailyDateFormat = "2006-01-02"
+
+type HandSetDailyQuoteBodyModel struct {
+	QuoteID string `json:"quote_id"`
+	// Date defaults to today
+	Date string `json:"date"`
+}
+
+type DailyQuoteModel struct {
+	Date       string     `json:"date" bson:"_id"`
+	QuoteID    string     `json:"quote_id" bson:"quote_id"`
+	PickedBy   string     `json:"picked_by" bson:"picked_by"`
+	CreateDate time.Time  `json:"create_date" bson:"create_date"`
+	Quote      QuoteModel `json:"quote" bson:"-"`
+}
+
+type CreateDailyQuoteModel struct {
+	Date       string    `json:"date" bson:"_id"`
+	QuoteID    string    `json:"quote_id" bson:"quote_id"`
+	PickedBy   string    `json:"picked_by" bson:"picked_by"`
+	CreateDate time.Time `json:"create_date" bson:"create_date"`
+}
+
+type DailyQuoteHistoryModel struct {
+	Days  []DailyQuoteModel `json:"days"`
+	Total int64             `json:"total"`
+	Page  int               `json:"page"`
+	Limit int               `json:"limit"`
+}
@@ -0,0 +1,29 @@
+package models
+
+type SimilarQuoteModel struct {
+	ID         string  `json:"id"`
+	Similarity float64 `json:"similarity"`
+}
+
+// DuplicateClusterModel is a group of quotes linked by near-duplicate pairs,
+// Similarity is the weakest link in the group.
+type DuplicateClusterModel struct {
+	IDs        []string     `json:"-"`
+	Quotes     []QuoteModel `json:"quotes"`
+	Similarity float64      `json:"similarity"`
+}
+
+type DuplicateQuoteModel struct {
+	Existing   QuoteModel `json:"existing"`
+	Similarity float64    `json:"similarity"`
+}
+
+type HandMergeQuotesBodyModel struct {
+	TargetID  string   `json:"target_id"`
+	SourceIDs []string `json:"source_ids"`
+}
+
+type MergeQuotesResultModel struct {
+	Quote  QuoteModel `json:"quote"`
+	Merged []string   `json:"merged"`
+}
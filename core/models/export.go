@@ -0,0 +1,42 @@
+package models
+
+import "io"
+
+const (
+	ExportFormatCSV    = "csv"
+	ExportFormatNDJSON = "ndjson"
+	ExportFormatXLSX   = "xlsx"
+)
+
+const (
+	// ExportKindQuotes lists quotes with their details, ExportKindResults ranks them by votes
+	ExportKindQuotes  = "quotes"
+	ExportKindResults = "results"
+)
+
+type HandExportQueryModel struct {
+	Format string `query:"format"`
+	// Limit caps how many quotes are exported, 0 exports every quote that matches
+	Limit       int    `query:"limit"`
+	Sort        string `query:"sort"`
+	Order       string `query:"order"`
+	MinVotes    *int   `query:"min_votes"`
+	CreatedFrom string `query:"created_from"`
+	CreatedTo   string `query:"created_to"`
+	Tag         string `query:"tag"`
+	Category    string `query:"category"`
+	Author      string `query:"author"`
+	Language    string `query:"language"`
+	// Voters adds a row for every user who voted on a quote, only admins may ask for it
+	Voters bool `query:"voters"`
+}
+
+// ExportModel is an export ready to be streamed, Write pages through the
+// quotes while it writes so the export is never held in memory whole.
+type ExportModel struct {
+	Kind        string                `json:"kind"`
+	Format      string                `json:"format"`
+	ContentType string                `json:"content_type"`
+	Filename    string                `json:"filename"`
+	Write       func(io.Writer) error `json:"-"`
+}
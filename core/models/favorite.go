@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+type FavoriteModel struct {
+	UserID     string    `json:"user_id" bson:"user_id"`
+	QuoteID    string    `json:"quote_id" bson:"quote_id"`
+	CreateDate time.Time `json:"create_date" bson:"create_date"`
+}
+
+type CreateFavoriteModel struct {
+	UserID     string    `json:"user_id" bson:"user_id"`
+	QuoteID    string    `json:"quote_id" bson:"quote_id"`
+	CreateDate time.Time `json:"create_date" bson:"create_date"`
+}
+
+type FavoriteListModel struct {
+	Quotes []QuoteModel `json:"quotes"`
+	Total  int64        `json:"total"`
+	Page   int          `json:"page"`
+	Limit  int          `json:"limit"`
+}
@@ -0,0 +1,137 @@
+package models
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+const (
+	// FeedKindNew lists the newest quotes, FeedKindTop the best voted ones
+	FeedKindNew = "new"
+	FeedKindTop = "top"
+)
+
+const (
+	FeedFormatAtom = "atom"
+	FeedFormatRSS  = "rss"
+	FeedFormatJSON = "json"
+)
+
+type HandFeedQueryModel struct {
+	Tag    string `query:"tag"`
+	Author string `query:"author"`
+	Limit  int    `query:"limit"`
+	// BaseURL and SelfURL come from the request, links in the feed are built from them
+	BaseURL string `query:"-"`
+	SelfURL string `query:"-"`
+}
+
+// FeedModel is a rendered feed, Updated is the newest update_date of its
+// entries and Body holds the document itself.
+type FeedModel struct {
+	Kind        string    `json:"kind"`
+	Format      string    `json:"format"`
+	ContentType string    `json:"content_type"`
+	ETag        string    `json:"etag"`
+	Updated     time.Time `json:"updated"`
+	Body        []byte    `json:"-"`
+}
+
+// AtomFeedModel is an Atom 1.0 (RFC 4287) document.
+type AtomFeedModel struct {
+	XMLName xml.Name         `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string           `xml:"title"`
+	ID      string           `xml:"id"`
+	Updated string           `xml:"updated"`
+	Links   []AtomLinkModel  `xml:"link"`
+	Author  AtomPersonModel  `xml:"author"`
+	Entries []AtomEntryModel `xml:"entry"`
+}
+
+type AtomLinkModel struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type AtomPersonModel struct {
+	Name string `xml:"name"`
+}
+
+type AtomCategoryModel struct {
+	Term string `xml:"term,attr"`
+}
+
+type AtomTextModel struct {
+	Type string `xml:"type,attr"`
+	Text string `xml:",chardata"`
+}
+
+type AtomEntryModel struct {
+	Title      string              `xml:"title"`
+	ID         string              `xml:"id"`
+	Updated    string              `xml:"updated"`
+	Published  string              `xml:"published"`
+	Links      []AtomLinkModel     `xml:"link"`
+	Authors    []AtomPersonModel   `xml:"author"`
+	Categories []AtomCategoryModel `xml:"category"`
+	Content    AtomTextModel       `xml:"content"`
+}
+
+// RSSFeedModel is an RSS 2.0 document, the atom namespace carries the self link.
+type RSSFeedModel struct {
+	XMLName xml.Name        `xml:"rss"`
+	Version string          `xml:"version,attr"`
+	AtomNS  string          `xml:"xmlns:atom,attr"`
+	DCNS    string          `xml:"xmlns:dc,attr"`
+	Channel RSSChannelModel `xml:"channel"`
+}
+
+type RSSChannelModel struct {
+	Title         string         `xml:"title"`
+	Link          string         `xml:"link"`
+	Description   string         `xml:"description"`
+	LastBuildDate string         `xml:"lastBuildDate"`
+	SelfLink      AtomLinkModel  `xml:"atom:link"`
+	Items         []RSSItemModel `xml:"item"`
+}
+
+type RSSGUIDModel struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	GUID        string `xml:",chardata"`
+}
+
+type RSSItemModel struct {
+	Title       string       `xml:"title"`
+	Link        string       `xml:"link"`
+	GUID        RSSGUIDModel `xml:"guid"`
+	PubDate     string       `xml:"pubDate"`
+	Creator     string       `xml:"dc:creator,omitempty"`
+	Categories  []string     `xml:"category"`
+	Description string       `xml:"description"`
+}
+
+// JSONFeedModel is a JSON Feed 1.1 document.
+type JSONFeedModel struct {
+	Version     string              `json:"version"`
+	Title       string              `json:"title"`
+	HomePageURL string              `json:"home_page_url"`
+	FeedURL     string              `json:"feed_url"`
+	Items       []JSONFeedItemModel `json:"items"`
+}
+
+type JSONFeedAuthorModel struct {
+	Name string `json:"name"`
+}
+
+type JSONFeedItemModel struct {
+	ID            string                `json:"id"`
+	URL           string                `json:"url"`
+	Title         string                `json:"title"`
+	ContentText   string                `json:"content_text"`
+	DatePublished string                `json:"date_published"`
+	DateModified  string                `json:"date_modified"`
+	Authors       []JSONFeedAuthorModel `json:"authors,omitempty"`
+	Tags          []string              `json:"tags,omitempty"`
+	Language      string                `json:"language,omitempty"`
+}
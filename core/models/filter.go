@@ -0,0 +1,37 @@
+package models
+
+const (
+	FilterActionAccept = "accept"
+	FilterActionFlag   = "flag"
+	FilterActionReject = "reject"
+)
+
+// FilterPhrasesModel is the phrase list of one language, Thai phrases match
+// anywhere in the text because Thai is written without spaces between words.
+type FilterPhrasesModel struct {
+	Reject []string `json:"reject"`
+	Flag   []string `json:"flag"`
+}
+
+type FilterPatternModel struct {
+	Pattern string `json:"pattern"`
+	Action  string `json:"action"`
+	Reason  string `json:"reason"`
+}
+
+// FilterRulesModel configures the content filter, Phrases is keyed by language
+// code and "*" applies to every language.
+type FilterRulesModel struct {
+	MaxLength int `json:"max_length"`
+	// LinkAction is applied to any quote with a link, more than MaxLinks links
+	// always rejects, 0 means no limit.
+	LinkAction string                        `json:"link_action"`
+	MaxLinks   int                           `json:"max_links"`
+	Phrases    map[string]FilterPhrasesModel `json:"phrases"`
+	Patterns   []FilterPatternModel          `json:"patterns"`
+}
+
+type FilterDecisionModel struct {
+	Action  string   `json:"action"`
+	Reasons []string `json:"reasons"`
+}
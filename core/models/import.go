@@ -0,0 +1,39 @@
+package models
+
+const (
+	ImportFormatCSV    = "csv"
+	ImportFormatJSON   = "json"
+	ImportFormatNDJSON = "ndjson"
+)
+
+const (
+	// ImportRowCreated is also used by a dry run for rows that would be created
+	ImportRowCreated = "created"
+	ImportRowSkipped = "skipped"
+	ImportRowFailed  = "failed"
+)
+
+type HandImportQuotesQueryModel struct {
+	Format string `query:"format"`
+	DryRun bool   `query:"dry_run"`
+}
+
+// ImportRowModel is the outcome of one row of an import, Row counts the
+// records of the file from 1 and does not count a CSV header.
+type ImportRowModel struct {
+	Row         int    `json:"row"`
+	Status      string `json:"status"`
+	QuoteID     string `json:"quote_id,omitempty"`
+	DuplicateOf string `json:"duplicate_of,omitempty"`
+	Message     string `json:"message,omitempty"`
+}
+
+type ImportReportModel struct {
+	Format  string           `json:"format"`
+	DryRun  bool             `json:"dry_run"`
+	Total   int              `json:"total"`
+	Created int              `json:"created"`
+	Skipped int              `json:"skipped"`
+	Failed  int              `json:"failed"`
+	Rows    []ImportRowModel `json:"rows"`
+}
@@ -0,0 +1,19 @@
+package models
+
+// MailIngestRowModel is the outcome of one message of a Maildir run.
+type MailIngestRowModel struct {
+	File    string `json:"file"`
+	From    string `json:"from,omitempty"`
+	Status  string `json:"status"`
+	QuoteID string `json:"quote_id,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// MailIngestReportModel sums up a Maildir run, Status of a row is one of the
+// ImportRow statuses.
+type MailIngestReportModel struct {
+	Processed int                  `json:"processed"`
+	Created   int                  `json:"created"`
+	Failed    int                  `json:"failed"`
+	Rows      []MailIngestRowModel `json:"rows"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+const (
+	NotificationQuoteApproved  = "quote_approved"
+	NotificationQuoteRejected  = "quote_rejected"
+	NotificationQuoteHidden    = "quote_hidden"
+	NotificationQuotePublished = "quote_published"
+)
+
+type NotificationModel struct {
+	ID         string    `json:"id" bson:"id"`
+	UserID     string    `json:"user_id" bson:"user_id"`
+	Type       string    `json:"type" bson:"type"`
+	QuoteID    string    `json:"quote_id" bson:"quote_id"`
+	Message    string    `json:"message" bson:"message"`
+	Read       bool      `json:"read" bson:"read"`
+	CreateDate time.Time `json:"create_date" bson:"create_date"`
+}
+
+type CreateNotificationModel struct {
+	ID         string    `json:"id" bson:"id"`
+	UserID     string    `json:"user_id" bson:"user_id"`
+	Type       string    `json:"type" bson:"type"`
+	QuoteID    string    `json:"quote_id" bson:"quote_id"`
+	Message    string    `json:"message" bson:"message"`
+	Read       bool      `json:"read" bson:"read"`
+	CreateDate time.Time `json:"create_date" bson:"create_date"`
+}
+
+type NotificationListModel struct {
+	Notifications []NotificationModel `json:"notifications"`
+	Unread        int64               `json:"unread"`
+	Total         int64               `json:"total"`
+	Page          int                 `json:"page"`
+	Limit         int                 `json:"limit"`
+}
@@ -2,35 +2,226 @@ package models
 
 import "time"
 
+const (
+	QuoteStatusPending  = "pending"
+	QuoteStatusApproved = "approved"
+	QuoteStatusRejected = "rejected"
+	// QuoteStatusScheduled is an approved quote hidden until its publish_at
+	QuoteStatusScheduled = "scheduled"
+)
+
+const (
+	SourceTypeBook   = "book"
+	SourceTypeSpeech = "speech"
+	SourceTypeURL    = "url"
+	SourceTypeOther  = "other"
+)
+
+// QuoteSourceModel is where a quote was said or written, every field is optional.
+type QuoteSourceModel struct {
+	Type  string `json:"type" bson:"type"`
+	Title string `json:"title" bson:"title"`
+	URL   string `json:"url" bson:"url"`
+}
+
 type HandCreateQuoteBodyModel struct {
-	Quote string `json:"quote"`
+	Quote      string           `json:"quote"`
+	Tags       []string         `json:"tags"`
+	CategoryID string           `json:"category_id"`
+	Author     string           `json:"author"`
+	Source     QuoteSourceModel `json:"source"`
+	Language   string           `json:"language"`
+	Year       int              `json:"year"`
+	// PublishAt is RFC 3339 or "2006-01-02 15:04:05" in server time
+	PublishAt string `json:"publish_at"`
 }
 
 type CreateQuoteModel struct {
-	ID         string    `json:"id" bson:"id"`
-	Quote      string    `json:"quote" bson:"quote"`
-	Vote       int       `json:"vote" bson:"vote"`
-	CreateDate time.Time `json:"create_date" bson:"create_date"`
-	UpdateDate time.Time `json:"update_date" bson:"update_date"`
+	ID         string           `json:"id" bson:"id"`
+	Quote      string           `json:"quote" bson:"quote"`
+	Vote       int              `json:"vote" bson:"vote"`
+	CreatedBy  string           `json:"created_by" bson:"created_by"`
+	Tags       []string         `json:"tags" bson:"tags"`
+	CategoryID string           `json:"category_id" bson:"category_id"`
+	AuthorID   string           `json:"author_id" bson:"author_id"`
+	Author     string           `json:"author" bson:"author"`
+	Source     QuoteSourceModel `json:"source" bson:"source"`
+	Language   string           `json:"language" bson:"language"`
+	Year       int              `json:"year" bson:"year"`
+	Status     string           `json:"status" bson:"status"`
+	// FlagReasons explains why the content filter held the quote for moderation
+	FlagReasons []string   `json:"flag_reasons,omitempty" bson:"flag_reasons,omitempty"`
+	PublishAt   *time.Time `json:"publish_at,omitempty" bson:"publish_at,omitempty"`
+	CreateDate  time.Time  `json:"create_date" bson:"create_date"`
+	UpdateDate  time.Time  `json:"update_date" bson:"update_date"`
 }
 
 type UpdateQuoteModel struct {
-	Quote      string    `json:"quote" bson:"quote,omitempty"`
-	Vote       int       `json:"vote" bson:"vote,omitempty"`
-	UpdateDate time.Time `json:"update_date" bson:"update_date"`
+	Quote      string            `json:"quote" bson:"quote,omitempty"`
+	Vote       int               `json:"vote" bson:"vote,omitempty"`
+	Tags       *[]string         `json:"tags" bson:"tags,omitempty"`
+	CategoryID *string           `json:"category_id" bson:"category_id,omitempty"`
+	AuthorID   *string           `json:"author_id" bson:"author_id,omitempty"`
+	Author     *string           `json:"author" bson:"author,omitempty"`
+	Source     *QuoteSourceModel `json:"source" bson:"source,omitempty"`
+	Language   *string           `json:"language" bson:"language,omitempty"`
+	Year       *int              `json:"year" bson:"year,omitempty"`
+	// Status and FlagReasons are set when the content filter flags an edit
+	Status      string     `json:"status" bson:"status,omitempty"`
+	FlagReasons []string   `json:"flag_reasons" bson:"flag_reasons,omitempty"`
+	PublishAt   *time.Time `json:"publish_at" bson:"publish_at,omitempty"`
+	UpdateDate  time.Time  `json:"update_date" bson:"update_date"`
 }
 
 type QuoteModel struct {
-	ID         string    `json:"id" bson:"id"`
-	Quote      string    `json:"quote" bson:"quote"`
-	Vote       int       `json:"vote" bson:"vote"`
-	CreateDate time.Time `json:"create_date" bson:"create_date"`
-	UpdateDate time.Time `json:"update_date" bson:"update_date"`
+	ID            string           `json:"id" bson:"id"`
+	Quote         string           `json:"quote" bson:"quote"`
+	Vote          int              `json:"vote" bson:"vote"`
+	CreatedBy     string           `json:"created_by" bson:"created_by"`
+	Tags          []string         `json:"tags" bson:"tags"`
+	CategoryID    string           `json:"category_id" bson:"category_id"`
+	AuthorID      string           `json:"author_id" bson:"author_id"`
+	Author        string           `json:"author" bson:"author"`
+	Source        QuoteSourceModel `json:"source" bson:"source"`
+	Language      string           `json:"language" bson:"language"`
+	Year          int              `json:"year" bson:"year"`
+	Status        string           `json:"status" bson:"status"`
+	ModeratedBy   string           `json:"moderated_by,omitempty" bson:"moderated_by,omitempty"`
+	ModeratedAt   *time.Time       `json:"moderated_at,omitempty" bson:"moderated_at,omitempty"`
+	RejectReason  string           `json:"reject_reason,omitempty" bson:"reject_reason,omitempty"`
+	FlagReasons   []string         `json:"flag_reasons,omitempty" bson:"flag_reasons,omitempty"`
+	FavoriteCount int              `json:"favorite_count" bson:"favorite_count"`
+	CommentCount  int              `json:"comment_count" bson:"comment_count"`
+	Reactions     map[string]int   `json:"reactions,omitempty" bson:"reactions,omitempty"`
+	IsFavorited   bool             `json:"is_favorited" bson:"-"` // set per request for the current user
+	DeletedAt     *time.Time       `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
+	DeletedBy     string           `json:"deleted_by,omitempty" bson:"deleted_by,omitempty"`
+	PublishAt     *time.Time       `json:"publish_at,omitempty" bson:"publish_at,omitempty"`
+	// GroupID links translations of one quote, it is the id of the group's root
+	// quote. Every quote of a group carries the group's vote tally.
+	GroupID      string                  `json:"group_id,omitempty" bson:"group_id,omitempty"`
+	Translations []QuoteTranslationModel `json:"translations,omitempty" bson:"-"` // set on lists, every variant of the group
+	CreateDate   time.Time               `json:"create_date" bson:"create_date"`
+	UpdateDate   time.Time               `json:"update_date" bson:"update_date"`
+}
+
+const (
+	QuoteSortVote       = "votes"
+	QuoteSortCreateDate = "create_date"
+	QuoteSortUpdateDate = "update_date"
+)
+
+type HandGetQuotesQueryModel struct {
+	Limit        int    `query:"limit"`
+	Cursor       string `query:"cursor"`
+	Sort         string `query:"sort"`
+	Order        string `query:"order"`
+	MinVotes     *int   `query:"min_votes"`
+	CreatedFrom  string `query:"created_from"`
+	CreatedTo    string `query:"created_to"`
+	Tag          string `query:"tag"`
+	Category     string `query:"category"`
+	Author       string `query:"author"`
+	Language     string `query:"language"`
+	IncludeTotal bool   `query:"include_total"`
+	// AcceptLanguage comes from the header and picks the variant of translated quotes
+	AcceptLanguage string `query:"-"`
+}
+
+type HandRandomQuotesQueryModel struct {
+	Count    int    `query:"count"`
+	Weighted bool   `query:"weighted"`
+	Tag      string `query:"tag"`
+	Language string `query:"language"`
+	// Exclude is a comma separated list of quote ids the caller has seen
+	Exclude string `query:"exclude"`
+	// Track excludes the quotes served to the caller before and remembers these
+	Track bool `query:"track"`
+}
+
+type RandomQuoteListModel struct {
+	Quotes []QuoteModel `json:"quotes"`
+}
+
+// QuoteCursorModel is the keyset position after the last quote of a page.
+type QuoteCursorModel struct {
+	Sort string    `json:"s"`
+	Vote int       `json:"v,omitempty"`
+	Date time.Time `json:"d,omitempty"`
+	ID   string    `json:"id"`
+}
+
+type QuoteFilterModel struct {
+	Limit       int
+	Sort        string
+	Descending  bool
+	After       *QuoteCursorModel
+	MinVotes    *int
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	// Tags must all be present on a quote, CategoryIDs match any
+	Tags        []string
+	CategoryIDs []string
+	AuthorID    string
+	Language    string
+	// Statuses defaults to approved quotes only
+	Statuses   []string
+	ExcludeIDs []string
+	// Translations lists every variant, otherwise a translation group is listed once by its root quote
+	Translations bool
+}
+
+type QuoteTranslationModel struct {
+	ID       string `json:"id"`
+	Language string `json:"language"`
+}
+
+type HandAddTranslationBodyModel struct {
+	QuoteID string `json:"quote_id"`
+}
+
+type QuoteTranslationsModel struct {
+	GroupID string       `json:"group_id"`
+	Vote    int          `json:"vote"`
+	Quotes  []QuoteModel `json:"quotes"`
+}
+
+type QuoteListModel struct {
+	Quotes     []QuoteModel `json:"quotes"`
+	NextCursor string       `json:"next_cursor"`
+	Total      *int64       `json:"total,omitempty"`
+}
+
+type ModerationQueueModel struct {
+	Quotes []QuoteModel `json:"quotes"`
+	Total  int64        `json:"total"`
+	Page   int          `json:"page"`
+	Limit  int          `json:"limit"`
+}
+
+type HandRejectQuoteBodyModel struct {
+	Reason string `json:"reason"`
+}
+
+type TrashListModel struct {
+	Quotes []QuoteModel `json:"quotes"`
+	Total  int64        `json:"total"`
+	Page   int          `json:"page"`
+	Limit  int          `json:"limit"`
 }
 
 type HandUpdateQuoteBodyModel struct {
-	Quote string `json:"quote"`
-	Vote  int    `json:"vote"`
+	Quote      string            `json:"quote"`
+	Vote       int               `json:"vote"`
+	Tags       *[]string         `json:"tags"`
+	CategoryID *string           `json:"category_id"`
+	Author     *string           `json:"author"`
+	Source     *QuoteSourceModel `json:"source"`
+	Language   *string           `json:"language"`
+	Year       *int              `json:"year"`
+	Reason     string            `json:"reason"`
+	// PublishAt reschedules a quote that is not public yet, "" publishes it now
+	PublishAt *string `json:"publish_at"`
 }
 
 type ResponseModel struct {
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+type HandReactQuoteBodyModel struct {
+	Emoji string `json:"emoji"`
+}
+
+type ReactionModel struct {
+	QuoteID    string    `json:"quote_id" bson:"quote_id"`
+	UserID     string    `json:"user_id" bson:"user_id"`
+	Emoji      string    `json:"emoji" bson:"emoji"`
+	CreateDate time.Time `json:"create_date" bson:"create_date"`
+}
+
+type CreateReactionModel struct {
+	QuoteID    string    `json:"quote_id" bson:"quote_id"`
+	UserID     string    `json:"user_id" bson:"user_id"`
+	Emoji      string    `json:"emoji" bson:"emoji"`
+	CreateDate time.Time `json:"create_date" bson:"create_date"`
+}
+
+type ReactorModel struct {
+	UserID      string    `json:"user_id"`
+	DisplayName string    `json:"display_name"`
+	AvatarURL   string    `json:"avatar_url"`
+	CreateDate  time.Time `json:"create_date"`
+}
+
+type ReactionBreakdownModel struct {
+	Emoji string         `json:"emoji"`
+	Count int            `json:"count"`
+	Users []ReactorModel `json:"users"`
+}
+
+type QuoteReactionsModel struct {
+	QuoteID   string                   `json:"quote_id"`
+	Reactions []ReactionBreakdownModel `json:"reactions"`
+}
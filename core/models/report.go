@@ -0,0 +1,77 @@
+package models
+
+import "time"
+
+const (
+	ReportReasonSpam          = "spam"
+	ReportReasonOffensive     = "offensive"
+	ReportReasonMisattributed = "misattributed"
+	ReportReasonCopyright     = "copyright"
+	ReportReasonOther         = "other"
+)
+
+const (
+	ReportStatusOpen      = "open"
+	ReportStatusDismissed = "dismissed"
+	ReportStatusActioned  = "actioned"
+)
+
+const (
+	ReportActionReject = "reject"
+	ReportActionDelete = "delete"
+)
+
+type HandReportQuoteBodyModel struct {
+	Reason string `json:"reason"`
+	Text   string `json:"text"`
+}
+
+type ReportModel struct {
+	ID         string     `json:"id" bson:"id"`
+	QuoteID    string     `json:"quote_id" bson:"quote_id"`
+	UserID     string     `json:"user_id" bson:"user_id"`
+	Reason     string     `json:"reason" bson:"reason"`
+	Text       string     `json:"text" bson:"text"`
+	Status     string     `json:"status" bson:"status"`
+	ResolvedBy string     `json:"resolved_by,omitempty" bson:"resolved_by,omitempty"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty" bson:"resolved_at,omitempty"`
+	CreateDate time.Time  `json:"create_date" bson:"create_date"`
+}
+
+type CreateReportModel struct {
+	ID         string    `json:"id" bson:"id"`
+	QuoteID    string    `json:"quote_id" bson:"quote_id"`
+	UserID     string    `json:"user_id" bson:"user_id"`
+	Reason     string    `json:"reason" bson:"reason"`
+	Text       string    `json:"text" bson:"text"`
+	Status     string    `json:"status" bson:"status"`
+	CreateDate time.Time `json:"create_date" bson:"create_date"`
+}
+
+// ReportGroupModel is every open report of one quote.
+type ReportGroupModel struct {
+	QuoteID      string        `json:"quote_id" bson:"_id"`
+	Count        int           `json:"count" bson:"count"`
+	LastReported time.Time     `json:"last_reported" bson:"last_reported"`
+	Reports      []ReportModel `json:"reports" bson:"reports"`
+}
+
+type ReportedQuoteModel struct {
+	Quote        QuoteModel     `json:"quote"`
+	Count        int            `json:"count"`
+	Reasons      map[string]int `json:"reasons"`
+	LastReported time.Time      `json:"last_reported"`
+	Reports      []ReportModel  `json:"reports"`
+}
+
+type ReportListModel struct {
+	Quotes []ReportedQuoteModel `json:"quotes"`
+	Total  int64                `json:"total"`
+	Page   int                  `json:"page"`
+	Limit  int                  `json:"limit"`
+}
+
+type HandActOnReportsBodyModel struct {
+	Action string `json:"action"`
+	Reason string `json:"reason"`
+}
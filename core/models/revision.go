@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+const (
+	RevisionVotesKeep  = "keep"
+	RevisionVotesReset = "reset"
+)
+
+type RevisionModel struct {
+	ID         string    `json:"id" bson:"id"`
+	QuoteID    string    `json:"quote_id" bson:"quote_id"`
+	Rev        int       `json:"rev" bson:"rev"`
+	Quote      string    `json:"quote" bson:"quote"`
+	EditedBy   string    `json:"edited_by" bson:"edited_by"`
+	Reason     string    `json:"reason" bson:"reason"`
+	VoteReset  bool      `json:"vote_reset" bson:"vote_reset"`
+	CreateDate time.Time `json:"create_date" bson:"create_date"`
+}
+
+type CreateRevisionModel struct {
+	ID         string    `json:"id" bson:"id"`
+	QuoteID    string    `json:"quote_id" bson:"quote_id"`
+	Rev        int       `json:"rev" bson:"rev"`
+	Quote      string    `json:"quote" bson:"quote"`
+	EditedBy   string    `json:"edited_by" bson:"edited_by"`
+	Reason     string    `json:"reason" bson:"reason"`
+	VoteReset  bool      `json:"vote_reset" bson:"vote_reset"`
+	CreateDate time.Time `json:"create_date" bson:"create_date"`
+}
+
+type DiffPartModel struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+type RevisionDiffModel struct {
+	From       int             `json:"from"`
+	To         int             `json:"to"`
+	Similarity float64         `json:"similarity"`
+	Parts      []DiffPartModel `json:"parts"`
+}
+
+type QuoteRevisionsModel struct {
+	Revisions []RevisionModel    `json:"revisions"`
+	Diff      *RevisionDiffModel `json:"diff,omitempty"`
+}
@@ -0,0 +1,13 @@
+package models
+
+type SearchHitModel struct {
+	ID        string  `json:"id"`
+	Score     float64 `json:"score"`
+	Highlight string  `json:"highlight"`
+}
+
+type QuoteSearchResultModel struct {
+	Quote     QuoteModel `json:"quote"`
+	Score     float64    `json:"score"`
+	Highlight string     `json:"highlight"`
+}
@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+// SeenQuoteModel records a quote served to a user by GET /quote/random?track=true.
+type SeenQuoteModel struct {
+	UserID   string    `json:"user_id" bson:"user_id"`
+	QuoteID  string    `json:"quote_id" bson:"quote_id"`
+	SeenDate time.Time `json:"seen_date" bson:"seen_date"`
+}
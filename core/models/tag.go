@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+type TagModel struct {
+	ID         string    `json:"id" bson:"id"`
+	Name       string    `json:"name" bson:"name"`
+	CreateDate time.Time `json:"create_date" bson:"create_date"`
+	UpdateDate time.Time `json:"update_date" bson:"update_date"`
+}
+
+type CreateTagModel struct {
+	ID         string    `json:"id" bson:"id"`
+	Name       string    `json:"name" bson:"name"`
+	CreateDate time.Time `json:"create_date" bson:"create_date"`
+	UpdateDate time.Time `json:"update_date" bson:"update_date"`
+}
+
+type TagCountModel struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+type HandTagBodyModel struct {
+	Name string `json:"name"`
+}
+
+type HandMergeTagBodyModel struct {
+	TargetID string `json:"target_id"`
+}
+
+type CategoryModel struct {
+	ID         string    `json:"id" bson:"id"`
+	Name       string    `json:"name" bson:"name"`
+	ParentID   string    `json:"parent_id" bson:"parent_id"`
+	CreateDate time.Time `json:"create_date" bson:"create_date"`
+	UpdateDate time.Time `json:"update_date" bson:"update_date"`
+}
+
+type CreateCategoryModel struct {
+	ID         string    `json:"id" bson:"id"`
+	Name       string    `json:"name" bson:"name"`
+	ParentID   string    `json:"parent_id" bson:"parent_id"`
+	CreateDate time.Time `json:"create_date" bson:"create_date"`
+	UpdateDate time.Time `json:"update_date" bson:"update_date"`
+}
+
+type UpdateCategoryModel struct {
+	Name       string    `json:"name" bson:"name,omitempty"`
+	ParentID   *string   `json:"parent_id" bson:"parent_id,omitempty"`
+	UpdateDate time.Time `json:"update_date" bson:"update_date"`
+}
+
+type CategoryCountModel struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	ParentID string `json:"parent_id"`
+	Count    int64  `json:"count"`
+}
+
+type HandCategoryBodyModel struct {
+	Name     string  `json:"name"`
+	ParentID *string `json:"parent_id"`
+}
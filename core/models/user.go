@@ -1,6 +1,15 @@
 package models
 
-import "time"
+import (
+	"io"
+	"time"
+)
+
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
 
 type HandGetUserBodyModel struct {
 	Email    string `json:"email"`
@@ -14,12 +23,80 @@ type SignInResModel struct {
 }
 
 type UserModel struct {
-	ID         string    `json:"id" bson:"id"`
-	Email      string    `json:"email" bson:"email"`
-	Password   string    `json:"password" bson:"password"`
-	QouteID    string    `json:"quote_id" bson:"quote_id"`
-	CreateDate time.Time `json:"create_date" bson:"create_date"`
-	UpdateDate time.Time `json:"update_date" bson:"update_date"`
+	ID                 string     `json:"id" bson:"id"`
+	Email              string     `json:"email" bson:"email"`
+	Password           string     `json:"password" bson:"password"`
+	QouteID            string     `json:"quote_id" bson:"quote_id"`
+	DisplayName        string     `json:"display_name" bson:"display_name"`
+	Bio                string     `json:"bio" bson:"bio"`
+	AvatarURL          string     `json:"avatar_url" bson:"avatar_url"`
+	AvatarKey          string     `json:"-" bson:"avatar_key"`
+	Role               string     `json:"role" bson:"role"`
+	Suspended          bool       `json:"suspended" bson:"suspended"`
+	SuspendReason      string     `json:"suspend_reason" bson:"suspend_reason"`
+	MustChangePassword bool       `json:"must_change_password" bson:"must_change_password"`
+	Trusted            bool       `json:"trusted" bson:"trusted"`
+	DeleteAt           *time.Time `json:"delete_at" bson:"delete_at"`
+	CreateDate         time.Time  `json:"create_date" bson:"create_date"`
+	UpdateDate         time.Time  `json:"update_date" bson:"update_date"`
+}
+
+// UserResModel is the public view of a user, it never carries the password hash.
+type UserResModel struct {
+	ID                 string     `json:"id"`
+	Email              string     `json:"email"`
+	QouteID            string     `json:"quote_id"`
+	DisplayName        string     `json:"display_name"`
+	Bio                string     `json:"bio"`
+	AvatarURL          string     `json:"avatar_url"`
+	Role               string     `json:"role"`
+	Suspended          bool       `json:"suspended"`
+	SuspendReason      string     `json:"suspend_reason,omitempty"`
+	MustChangePassword bool       `json:"must_change_password"`
+	Trusted            bool       `json:"trusted"`
+	DeleteAt           *time.Time `json:"delete_at,omitempty"`
+	CreateDate         time.Time  `json:"create_date"`
+	UpdateDate         time.Time  `json:"update_date"`
+}
+
+type UserListModel struct {
+	Users []UserResModel `json:"users"`
+	Total int64          `json:"total"`
+	Page  int            `json:"page"`
+	Limit int            `json:"limit"`
+}
+
+// AdminUserDetailModel is what an admin sees for a single user.
+type AdminUserDetailModel struct {
+	User   UserResModel `json:"user"`
+	Vote   *QuoteModel  `json:"vote"`
+	Quotes []QuoteModel `json:"quotes"`
+}
+
+type HandSuspendUserBodyModel struct {
+	Reason string `json:"reason"`
+}
+
+type HandSetTrustedBodyModel struct {
+	Trusted bool `json:"trusted"`
+}
+
+type HandUpdateRoleBodyModel struct {
+	Role string `json:"role"`
+}
+
+// UserExportModel is the personal data bundle returned by GET /users/me/export.
+type UserExportModel struct {
+	Profile       UserResModel        `json:"profile"`
+	Quotes        []QuoteModel        `json:"quotes"`
+	Votes         []QuoteModel        `json:"votes"`
+	Audits        []AuditModel        `json:"audits"`
+	Notifications []NotificationModel `json:"notifications"`
+	Favorites     []FavoriteModel     `json:"favorites"`
+	Comments      []CommentModel      `json:"comments"`
+	Reactions     []ReactionModel     `json:"reactions"`
+	SeenQuotes    []SeenQuoteModel    `json:"seen_quotes"`
+	ExportDate    time.Time           `json:"export_date"`
 }
 
 type CreateUserModel struct {
@@ -27,6 +104,7 @@ type CreateUserModel struct {
 	Email      string    `json:"email" bson:"email"`
 	QouteID    string    `json:"quote_id" bson:"quote_id"`
 	Password   string    `json:"password" bson:"password"`
+	Role       string    `json:"role" bson:"role"`
 	CreateDate time.Time `json:"create_date" bson:"create_date"`
 	UpdateDate time.Time `json:"update_date" bson:"update_date"`
 }
@@ -41,9 +119,42 @@ type HandSignInBodyModel struct {
 	Password string `json:"password" bson:"password"`
 }
 
+type HandUpdateProfileBodyModel struct {
+	DisplayName *string `json:"display_name" form:"display_name"`
+	Bio         *string `json:"bio" form:"bio"`
+}
+
+type HandChangePasswordBodyModel struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+// UpdateProfileModel carries the optional fields of PATCH /users/me, nil means unchanged.
+type UpdateProfileModel struct {
+	DisplayName *string
+	Bio         *string
+	Avatar      *UploadFileModel
+}
+
+type UploadFileModel struct {
+	Filename    string
+	ContentType string
+	Size        int64
+	Content     io.Reader
+}
+
 type UpdateUserModel struct {
-	Email      string    `json:"email" bson:"email,omitempty"`
-	QuoteID    string    `json:"quote_id" bson:"quote_id,omitempty"`
-	Password   string    `json:"password" bson:"password,omitempty"`
-	UpdateDate time.Time `json:"update_date" bson:"update_date"`
+	Email              string    `json:"email" bson:"email,omitempty"`
+	QuoteID            string    `json:"quote_id" bson:"quote_id,omitempty"`
+	Password           string    `json:"password" bson:"password,omitempty"`
+	DisplayName        *string   `json:"display_name" bson:"display_name,omitempty"`
+	Bio                *string   `json:"bio" bson:"bio,omitempty"`
+	AvatarURL          *string   `json:"avatar_url" bson:"avatar_url,omitempty"`
+	AvatarKey          *string   `json:"-" bson:"avatar_key,omitempty"`
+	Role               string    `json:"role" bson:"role,omitempty"`
+	Suspended          *bool     `json:"suspended" bson:"suspended,omitempty"`
+	SuspendReason      *string   `json:"suspend_reason" bson:"suspend_reason,omitempty"`
+	MustChangePassword *bool     `json:"must_change_password" bson:"must_change_password,omitempty"`
+	Trusted            *bool     `json:"trusted" bson:"trusted,omitempty"`
+	UpdateDate         time.Time `json:"update_date" bson:"update_date"`
 }
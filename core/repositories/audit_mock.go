@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"backend/core/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type auditRepoMock struct {
+	mock.Mock
+}
+
+func NewAuditRepositoryMock() *auditRepoMock {
+	return &auditRepoMock{}
+}
+
+func (m *auditRepoMock) CreateAudit(audit models.CreateAuditModel) error {
+	args := m.Called(audit)
+	return args.Error(0)
+}
+
+func (m *auditRepoMock) GetAuditsByUser(userID string) (result []models.AuditModel, err error) {
+	args := m.Called(userID)
+	return args.Get(0).([]models.AuditModel), args.Error(1)
+}
+
+func (m *auditRepoMock) DeleteAuditsByUser(userID string) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"backend/core/models"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type AuditRepository interface {
+	CreateAudit(audit models.CreateAuditModel) error
+
+	GetAuditsByUser(userID string) (result []models.AuditModel, err error)
+
+	DeleteAuditsByUser(userID string) error
+}
+
+type auditRepo struct {
+	db         *mongo.Database
+	collection string
+}
+
+func NewAuditRepository(db *mongo.Database, collection string) AuditRepository {
+	return &auditRepo{
+		db:         db,
+		collection: collection,
+	}
+}
+
+func (r *auditRepo) CreateAudit(audit models.CreateAuditModel) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := r.db.Collection(r.collection).InsertOne(ctx, audit)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *auditRepo) GetAuditsByUser(userID string) (result []models.AuditModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	filter := bson.D{{Key: "user_id", Value: userID}}
+	opts := options.Find().SetSort(bson.D{{Key: "create_date", Value: 1}})
+	cursor, err := r.db.Collection(r.collection).Find(ctx, filter, opts)
+	if err != nil {
+		return result, err
+	}
+	if err = cursor.All(ctx, &result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+func (r *auditRepo) DeleteAuditsByUser(userID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	filter := bson.D{{Key: "user_id", Value: userID}}
+	_, err := r.db.Collection(r.collection).DeleteMany(ctx, filter)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"backend/core/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type authorRepoMock struct {
+	mock.Mock
+}
+
+func NewAuthorRepositoryMock() *authorRepoMock {
+	return &authorRepoMock{}
+}
+
+func (m *authorRepoMock) GetAuthors(search string, page int, limit int) (result []models.AuthorModel, total int64, err error) {
+	args := m.Called(search, page, limit)
+	return args.Get(0).([]models.AuthorModel), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *authorRepoMock) GetAuthor(id string) (result models.AuthorModel, err error) {
+	args := m.Called(id)
+	return args.Get(0).(models.AuthorModel), args.Error(1)
+}
+
+func (m *authorRepoMock) GetAuthorByNameKey(nameKey string) (result models.AuthorModel, err error) {
+	args := m.Called(nameKey)
+	return args.Get(0).(models.AuthorModel), args.Error(1)
+}
+
+func (m *authorRepoMock) CreateAuthor(author models.CreateAuthorModel) (result models.AuthorModel, err error) {
+	args := m.Called(author)
+	return args.Get(0).(models.AuthorModel), args.Error(1)
+}
+
+func (m *authorRepoMock) UpdateAuthor(id string, payload models.UpdateAuthorModel) (result models.AuthorModel, err error) {
+	args := m.Called(id, payload)
+	return args.Get(0).(models.AuthorModel), args.Error(1)
+}
+
+func (m *authorRepoMock) EnsureIndexes() error {
+	args := m.Called()
+	return args.Error(0)
+}
@@ -0,0 +1,127 @@
+package repositories
+
+import (
+	"backend/core/models"
+	"context"
+	"regexp"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type AuthorRepository interface {
+	GetAuthors(search string, page int, limit int) (result []models.AuthorModel, total int64, err error)
+
+	GetAuthor(id string) (result models.AuthorModel, err error)
+
+	GetAuthorByNameKey(nameKey string) (result models.AuthorModel, err error)
+
+	CreateAuthor(author models.CreateAuthorModel) (result models.AuthorModel, err error)
+
+	UpdateAuthor(id string, payload models.UpdateAuthorModel) (result models.AuthorModel, err error)
+
+	EnsureIndexes() error
+}
+
+type authorRepo struct {
+	db         *mongo.Database
+	collection string
+}
+
+func NewAuthorRepository(db *mongo.Database, collection string) AuthorRepository {
+	return &authorRepo{
+		db:         db,
+		collection: collection,
+	}
+}
+
+func (r *authorRepo) GetAuthors(search string, page int, limit int) (result []models.AuthorModel, total int64, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	filter := bson.D{}
+	if search != "" {
+		filter = bson.D{{Key: "name", Value: primitive.Regex{Pattern: regexp.QuoteMeta(search), Options: "i"}}}
+	}
+	total, err = r.db.Collection(r.collection).CountDocuments(ctx, filter)
+	if err != nil {
+		return result, total, err
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "name", Value: 1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+	cursor, err := r.db.Collection(r.collection).Find(ctx, filter, opts)
+	if err != nil {
+		return result, total, err
+	}
+	if err = cursor.All(ctx, &result); err != nil {
+		return result, total, err
+	}
+	return result, total, nil
+}
+
+func (r *authorRepo) GetAuthor(id string) (result models.AuthorModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	filter := bson.D{{Key: "id", Value: id}}
+	err = r.db.Collection(r.collection).FindOne(ctx, filter).Decode(&result)
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+func (r *authorRepo) GetAuthorByNameKey(nameKey string) (result models.AuthorModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	filter := bson.D{{Key: "name_key", Value: nameKey}}
+	err = r.db.Collection(r.collection).FindOne(ctx, filter).Decode(&result)
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+func (r *authorRepo) CreateAuthor(author models.CreateAuthorModel) (result models.AuthorModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err = r.db.Collection(r.collection).InsertOne(ctx, author)
+	if err != nil {
+		return result, err
+	}
+	err = r.db.Collection(r.collection).FindOne(ctx, bson.D{{Key: "id", Value: author.ID}}).Decode(&result)
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+func (r *authorRepo) UpdateAuthor(id string, payload models.UpdateAuthorModel) (result models.AuthorModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	filter := bson.D{{Key: "id", Value: id}}
+	_, err = r.db.Collection(r.collection).UpdateOne(ctx, filter, bson.D{{Key: "$set", Value: payload}})
+	if err != nil {
+		return result, err
+	}
+	err = r.db.Collection(r.collection).FindOne(ctx, filter).Decode(&result)
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// EnsureIndexes makes name_key unique so two requests naming a new author at
+// the same time cannot both create it.
+func (r *authorRepo) EnsureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	_, err := r.db.Collection(r.collection).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "name_key", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"io"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type blobRepoMock struct {
+	mock.Mock
+}
+
+func NewBlobRepositoryMock() *blobRepoMock {
+	return &blobRepoMock{}
+}
+
+func (m *blobRepoMock) Put(key string, content io.Reader) (url string, err error) {
+	args := m.Called(key, content)
+	return args.String(0), args.Error(1)
+}
+
+func (m *blobRepoMock) Delete(key string) error {
+	args := m.Called(key)
+	return args.Error(0)
+}
@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+type BlobRepository interface {
+	Put(key string, content io.Reader) (url string, err error)
+
+	Delete(key string) error
+}
+
+type localBlobRepo struct {
+	dir     string
+	baseURL string
+}
+
+// NewLocalBlobRepository stores blobs under dir on the local disk, they are
+// expected to be served publicly at baseURL.
+func NewLocalBlobRepository(dir string, baseURL string) BlobRepository {
+	return &localBlobRepo{
+		dir:     dir,
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}
+}
+
+func (r *localBlobRepo) path(key string) (string, error) {
+	clean := path.Clean("/" + key)
+	if clean == "/" || strings.Contains(key, "..") {
+		return "", errors.New("invalid blob key")
+	}
+	return filepath.Join(r.dir, filepath.FromSlash(clean)), nil
+}
+
+func (r *localBlobRepo) Put(key string, content io.Reader) (url string, err error) {
+	p, err := r.path(key)
+	if err != nil {
+		return url, err
+	}
+	if err = os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return url, err
+	}
+	f, err := os.Create(p)
+	if err != nil {
+		return url, err
+	}
+	defer f.Close()
+	if _, err = io.Copy(f, content); err != nil {
+		os.Remove(p)
+		return url, err
+	}
+	return r.baseURL + "/" + strings.TrimLeft(key, "/"), nil
+}
+
+func (r *localBlobRepo) Delete(key string) error {
+	p, err := r.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(p)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"backend/core/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type cardTemplateRepoMock struct {
+	mock.Mock
+}
+
+func NewCardTemplateRepositoryMock() *cardTemplateRepoMock {
+	return &cardTemplateRepoMock{}
+}
+
+func (m *cardTemplateRepoMock) GetTemplates() (result map[string]models.CardTemplateModel, err error) {
+	args := m.Called()
+	return args.Get(0).(map[string]models.CardTemplateModel), args.Error(1)
+}
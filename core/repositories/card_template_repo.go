@@ -0,0 +1,39 @@
+package repositories
+
+import (
+	"backend/core/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+type CardTemplateRepository interface {
+	GetTemplates() (result map[string]models.CardTemplateModel, err error)
+}
+
+type fileCardTemplateRepo struct {
+	path string
+}
+
+// NewFileCardTemplateRepository reads the quote card templates from a JSON
+// object keyed by template name, a missing file only has the built in default.
+func NewFileCardTemplateRepository(path string) CardTemplateRepository {
+	return &fileCardTemplateRepo{
+		path: path,
+	}
+}
+
+func (r *fileCardTemplateRepo) GetTemplates() (result map[string]models.CardTemplateModel, err error) {
+	data, err := os.ReadFile(r.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]models.CardTemplateModel{}, nil
+	}
+	if err != nil {
+		return result, err
+	}
+	if err = json.Unmarshal(data, &result); err != nil {
+		return result, fmt.Errorf("card templates %s: %w", r.path, err)
+	}
+	return result, nil
+}
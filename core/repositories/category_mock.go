@@ -0,0 +1,40 @@
+package repositories
+
+import (
+	"backend/core/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type categoryRepoMock struct {
+	mock.Mock
+}
+
+func NewCategoryRepositoryMock() *categoryRepoMock {
+	return &categoryRepoMock{}
+}
+
+func (m *categoryRepoMock) GetCategories() (result []models.CategoryModel, err error) {
+	args := m.Called()
+	return args.Get(0).([]models.CategoryModel), args.Error(1)
+}
+
+func (m *categoryRepoMock) GetCategory(id string) (result models.CategoryModel, err error) {
+	args := m.Called(id)
+	return args.Get(0).(models.CategoryModel), args.Error(1)
+}
+
+func (m *categoryRepoMock) CreateCategory(category models.CreateCategoryModel) (result models.CategoryModel, err error) {
+	args := m.Called(category)
+	return args.Get(0).(models.CategoryModel), args.Error(1)
+}
+
+func (m *categoryRepoMock) UpdateCategory(id string, payload models.UpdateCategoryModel) (result models.CategoryModel, err error) {
+	args := m.Called(id, payload)
+	return args.Get(0).(models.CategoryModel), args.Error(1)
+}
+
+func (m *categoryRepoMock) DeleteCategory(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
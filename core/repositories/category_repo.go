@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"backend/core/models"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CategoryRepository interface {
+	GetCategories() (result []models.CategoryModel, err error)
+
+	GetCategory(id string) (result models.CategoryModel, err error)
+
+	CreateCategory(category models.CreateCategoryModel) (result models.CategoryModel, err error)
+
+	UpdateCategory(id string, payload models.UpdateCategoryModel) (result models.CategoryModel, err error)
+
+	DeleteCategory(id string) error
+}
+
+type categoryRepo struct {
+	db         *mongo.Database
+	collection string
+}
+
+func NewCategoryRepository(db *mongo.Database, collection string) CategoryRepository {
+	return &categoryRepo{
+		db:         db,
+		collection: collection,
+	}
+}
+
+func (r *categoryRepo) GetCategories() (result []models.CategoryModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := r.db.Collection(r.collection).Find(ctx, bson.D{}, opts)
+	if err != nil {
+		return result, err
+	}
+	if err = cursor.All(ctx, &result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+func (r *categoryRepo) GetCategory(id string) (result models.CategoryModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	filter := bson.D{{Key: "id", Value: id}}
+	err = r.db.Collection(r.collection).FindOne(ctx, filter).Decode(&result)
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+func (r *categoryRepo) CreateCategory(category models.CreateCategoryModel) (result models.CategoryModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err = r.db.Collection(r.collection).InsertOne(ctx, category)
+	if err != nil {
+		return result, err
+	}
+	err = r.db.Collection(r.collection).FindOne(ctx, bson.D{{Key: "id", Value: category.ID}}).Decode(&result)
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+func (r *categoryRepo) UpdateCategory(id string, payload models.UpdateCategoryModel) (result models.CategoryModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	filter := bson.D{{Key: "id", Value: id}}
+	_, err = r.db.Collection(r.collection).UpdateOne(ctx, filter, bson.D{{Key: "$set", Value: payload}})
+	if err != nil {
+		return result, err
+	}
+	err = r.db.Collection(r.collection).FindOne(ctx, filter).Decode(&result)
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+func (r *categoryRepo) DeleteCategory(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	filter := bson.D{{Key: "id", Value: id}}
+	_, err := r.db.Collection(r.collection).DeleteOne(ctx, filter)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"backend/core/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type commentRepoMock struct {
+	mock.Mock
+}
+
+func NewCommentRepositoryMock() *commentRepoMock {
+	return &commentRepoMock{}
+}
+
+func (m *commentRepoMock) CreateComment(payload models.CreateCommentModel) (result models.CommentModel, err error) {
+	args := m.Called(payload)
+	return args.Get(0).(models.CommentModel), args.Error(1)
+}
+
+func (m *commentRepoMock) GetComment(id string) (result models.CommentModel, err error) {
+	args := m.Called(id)
+	return args.Get(0).(models.CommentModel), args.Error(1)
+}
+
+func (m *commentRepoMock) UpdateComment(id string, body string) (result models.CommentModel, err error) {
+	args := m.Called(id, body)
+	return args.Get(0).(models.CommentModel), args.Error(1)
+}
+
+func (m *commentRepoMock) DeleteComment(id string, userID string) (result models.CommentModel, err error) {
+	args := m.Called(id, userID)
+	return args.Get(0).(models.CommentModel), args.Error(1)
+}
+
+func (m *commentRepoMock) GetComments(quoteID string, page int, limit int) (result []models.CommentModel, total int64, err error) {
+	args := m.Called(quoteID, page, limit)
+	return args.Get(0).([]models.CommentModel), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *commentRepoMock) GetReplies(parentIDs []string) (result []models.CommentModel, err error) {
+	args := m.Called(parentIDs)
+	return args.Get(0).([]models.CommentModel), args.Error(1)
+}
+
+func (m *commentRepoMock) GetCommentsByUser(userID string) (result []models.CommentModel, err error) {
+	args := m.Called(userID)
+	return args.Get(0).([]models.CommentModel), args.Error(1)
+}
+
+func (m *commentRepoMock) IncrementReplyCount(id string, delta int) error {
+	args := m.Called(id, delta)
+	return args.Error(0)
+}
+
+func (m *commentRepoMock) ClearCommentAuthor(userID string) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func (m *commentRepoMock) DeleteCommentsByQuote(quoteID string) error {
+	args := m.Called(quoteID)
+	return args.Error(0)
+}
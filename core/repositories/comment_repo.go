@@ -0,0 +1,209 @@
+package repositories
+
+import (
+	"backend/core/models"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CommentRepository interface {
+	CreateComment(payload models.CreateCommentModel) (result models.CommentModel, err error)
+
+	GetComment(id string) (result models.CommentModel, err error)
+
+	UpdateComment(id string, body string) (result models.CommentModel, err error)
+
+	DeleteComment(id string, userID string) (result models.CommentModel, err error)
+
+	GetComments(quoteID string, page int, limit int) (result []models.CommentModel, total int64, err error)
+
+	GetReplies(parentIDs []string) (result []models.CommentModel, err error)
+
+	GetCommentsByUser(userID string) (result []models.CommentModel, err error)
+
+	IncrementReplyCount(id string, delta int) error
+
+	ClearCommentAuthor(userID string) error
+
+	DeleteCommentsByQuote(quoteID string) error
+}
+
+type commentRepo struct {
+	db         *mongo.Database
+	collection string
+}
+
+func NewCommentRepository(db *mongo.Database, collection string) CommentRepository {
+	return &commentRepo{
+		db:         db,
+		collection: collection,
+	}
+}
+
+var commentNotDeleted = bson.E{Key: "deleted_at", Value: nil}
+
+func (r *commentRepo) CreateComment(payload models.CreateCommentModel) (result models.CommentModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err = r.db.Collection(r.collection).InsertOne(ctx, payload)
+	if err != nil {
+		return result, err
+	}
+	err = r.db.Collection(r.collection).FindOne(ctx, bson.D{{Key: "id", Value: payload.ID}}).Decode(&result)
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+func (r *commentRepo) GetComment(id string) (result models.CommentModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.D{{Key: "id", Value: id}, commentNotDeleted}
+	err = r.db.Collection(r.collection).FindOne(ctx, filter).Decode(&result)
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+func (r *commentRepo) UpdateComment(id string, body string) (result models.CommentModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.D{{Key: "id", Value: id}, commentNotDeleted}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "body", Value: body},
+		{Key: "edit_date", Value: time.Now()},
+	}}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = r.db.Collection(r.collection).FindOneAndUpdate(ctx, filter, update, opts).Decode(&result)
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// DeleteComment blanks a comment but keeps it, so the replies of a deleted
+// comment still have a thread to hang in.
+func (r *commentRepo) DeleteComment(id string, userID string) (result models.CommentModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.D{{Key: "id", Value: id}, commentNotDeleted}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "body", Value: ""},
+		{Key: "deleted_at", Value: time.Now()},
+		{Key: "deleted_by", Value: userID},
+	}}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = r.db.Collection(r.collection).FindOneAndUpdate(ctx, filter, update, opts).Decode(&result)
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// GetComments pages through the top level comments of a quote, oldest first.
+// Deleted comments are listed only while they still have replies.
+func (r *commentRepo) GetComments(quoteID string, page int, limit int) (result []models.CommentModel, total int64, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.D{
+		{Key: "quote_id", Value: quoteID},
+		{Key: "parent_id", Value: ""},
+		{Key: "$or", Value: bson.A{
+			bson.D{commentNotDeleted},
+			bson.D{{Key: "reply_count", Value: bson.D{{Key: "$gt", Value: 0}}}},
+		}},
+	}
+	total, err = r.db.Collection(r.collection).CountDocuments(ctx, filter)
+	if err != nil {
+		return result, 0, err
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "create_date", Value: 1}, {Key: "id", Value: 1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+	cursor, err := r.db.Collection(r.collection).Find(ctx, filter, opts)
+	if err != nil {
+		return result, 0, err
+	}
+	if err = cursor.All(ctx, &result); err != nil {
+		return result, 0, err
+	}
+	return result, total, nil
+}
+
+func (r *commentRepo) GetReplies(parentIDs []string) (result []models.CommentModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.D{{Key: "parent_id", Value: bson.D{{Key: "$in", Value: parentIDs}}}, commentNotDeleted}
+	opts := options.Find().SetSort(bson.D{{Key: "create_date", Value: 1}, {Key: "id", Value: 1}})
+	cursor, err := r.db.Collection(r.collection).Find(ctx, filter, opts)
+	if err != nil {
+		return result, err
+	}
+	if err = cursor.All(ctx, &result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+func (r *commentRepo) GetCommentsByUser(userID string) (result []models.CommentModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.D{{Key: "user_id", Value: userID}, commentNotDeleted}
+	opts := options.Find().SetSort(bson.D{{Key: "create_date", Value: 1}})
+	cursor, err := r.db.Collection(r.collection).Find(ctx, filter, opts)
+	if err != nil {
+		return result, err
+	}
+	if err = cursor.All(ctx, &result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+func (r *commentRepo) IncrementReplyCount(id string, delta int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.D{{Key: "id", Value: id}}
+	if delta < 0 {
+		filter = append(filter, bson.E{Key: "reply_count", Value: bson.D{{Key: "$gte", Value: -delta}}})
+	}
+	update := bson.D{{Key: "$inc", Value: bson.D{{Key: "reply_count", Value: delta}}}}
+	_, err := r.db.Collection(r.collection).UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// ClearCommentAuthor detaches every comment from userID, like ClearCreator does for quotes.
+func (r *commentRepo) ClearCommentAuthor(userID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.D{{Key: "user_id", Value: userID}}
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "user_id", Value: ""}}}}
+	_, err := r.db.Collection(r.collection).UpdateMany(ctx, filter, update)
+	return err
+}
+
+func (r *commentRepo) DeleteCommentsByQuote(quoteID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := r.db.Collection(r.collection).DeleteMany(ctx, bson.D{{Key: "quote_id", Value: quoteID}})
+	return err
+}
@@ -0,0 +1,40 @@
+package repositories
+
+import (
+	"backend/core/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type dailyQuoteRepoMock struct {
+	mock.Mock
+}
+
+func NewDailyQuoteRepositoryMock() *dailyQuoteRepoMock {
+	return &dailyQuoteRepoMock{}
+}
+
+func (m *dailyQuoteRepoMock) GetDailyQuote(date string) (result models.DailyQuoteModel, err error) {
+	args := m.Called(date)
+	return args.Get(0).(models.DailyQuoteModel), args.Error(1)
+}
+
+func (m *dailyQuoteRepoMock) CreateDailyQuote(payload models.CreateDailyQuoteModel) (result models.DailyQuoteModel, err error) {
+	args := m.Called(payload)
+	return args.Get(0).(models.DailyQuoteModel), args.Error(1)
+}
+
+func (m *dailyQuoteRepoMock) SetDailyQuote(payload models.CreateDailyQuoteModel) (result models.DailyQuoteModel, err error) {
+	args := m.Called(payload)
+	return args.Get(0).(models.DailyQuoteModel), args.Error(1)
+}
+
+func (m *dailyQuoteRepoMock) GetDailyQuotes(until string, page int, limit int) (result []models.DailyQuoteModel, total int64, err error) {
+	args := m.Called(until, page, limit)
+	return args.Get(0).([]models.DailyQuoteModel), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *dailyQuoteRepoMock) GetDailyQuotesSince(date string) (result []models.DailyQuoteModel, err error) {
+	args := m.Called(date)
+	return args.Get(0).([]models.DailyQuoteModel), args.Error(1)
+}
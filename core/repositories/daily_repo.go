@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"backend/core/models"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type DailyQuoteRepository interface {
+	GetDailyQuote(date string) (result models.DailyQuoteModel, err error)
+
+	CreateDailyQuote(payload models.CreateDailyQuoteModel) (result models.DailyQuoteModel, err error)
+
+	SetDailyQuote(payload models.CreateDailyQuoteModel) (result models.DailyQuoteModel, err error)
+
+	GetDailyQuotes(until string, page int, limit int) (result []models.DailyQuoteModel, total int64, err error)
+
+	GetDailyQuotesSince(date string) (result []models.DailyQuoteModel, err error)
+}
+
+type dailyQuoteRepo struct {
+	db         *mongo.Database
+	collection string
+}
+
+func NewDailyQuoteRepository(db *mongo.Database, collection string) DailyQuoteRepository {
+	return &dailyQuoteRepo{
+		db:         db,
+		collection: collection,
+	}
+}
+
+func (r *dailyQuoteRepo) GetDailyQuote(date string) (result models.DailyQuoteModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.D{{Key: "_id", Value: date}}
+	err = r.db.Collection(r.collection).FindOne(ctx, filter).Decode(&result)
+	return result, err
+}
+
+// CreateDailyQuote stores the pick for a day unless one is already stored and
+// returns the stored pick, so instances racing on the same day agree.
+func (r *dailyQuoteRepo) CreateDailyQuote(payload models.CreateDailyQuoteModel) (result models.DailyQuoteModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.D{{Key: "_id", Value: payload.Date}}
+	update := bson.D{{Key: "$setOnInsert", Value: payload}}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	err = r.db.Collection(r.collection).FindOneAndUpdate(ctx, filter, update, opts).Decode(&result)
+	return result, err
+}
+
+// SetDailyQuote stores the pick for a day, replacing any earlier pick.
+func (r *dailyQuoteRepo) SetDailyQuote(payload models.CreateDailyQuoteModel) (result models.DailyQuoteModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.D{{Key: "_id", Value: payload.Date}}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "quote_id", Value: payload.QuoteID},
+		{Key: "picked_by", Value: payload.PickedBy},
+		{Key: "create_date", Value: payload.CreateDate},
+	}}}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	err = r.db.Collection(r.collection).FindOneAndUpdate(ctx, filter, update, opts).Decode(&result)
+	return result, err
+}
+
+// GetDailyQuotes pages through the picks up to and including until, newest first.
+func (r *dailyQuoteRepo) GetDailyQuotes(until string, page int, limit int) (result []models.DailyQuoteModel, total int64, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.D{{Key: "_id", Value: bson.D{{Key: "$lte", Value: until}}}}
+	total, err = r.db.Collection(r.collection).CountDocuments(ctx, filter)
+	if err != nil {
+		return result, 0, err
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+	cursor, err := r.db.Collection(r.collection).Find(ctx, filter, opts)
+	if err != nil {
+		return result, 0, err
+	}
+	if err = cursor.All(ctx, &result); err != nil {
+		return result, 0, err
+	}
+	return result, total, nil
+}
+
+func (r *dailyQuoteRepo) GetDailyQuotesSince(date string) (result []models.DailyQuoteModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.D{{Key: "_id", Value: bson.D{{Key: "$gte", Value: date}}}}
+	cursor, err := r.db.Collection(r.collection).Find(ctx, filter)
+	if err != nil {
+		return result, err
+	}
+	if err = cursor.All(ctx, &result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
@@ -0,0 +1,172 @@
+package repositories
+
+import (
+	"backend/core/models"
+	"backend/utils"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+type DuplicateRepository interface {
+	Index(id string, text string) error
+
+	Remove(id string) error
+
+	FindSimilar(text string, threshold float64) (result []models.SimilarQuoteModel, err error)
+
+	Clusters(threshold float64) (result []models.DuplicateClusterModel, err error)
+}
+
+type memoryDuplicateRepo struct {
+	mu         sync.RWMutex
+	signatures map[string][]uint64
+	buckets    map[string]map[string]bool
+}
+
+// NewMemoryDuplicateRepository keeps MinHash signatures in process memory and finds
+// candidates with locality sensitive hashing, it has to be filled with Index on startup.
+func NewMemoryDuplicateRepository() DuplicateRepository {
+	return &memoryDuplicateRepo{
+		signatures: map[string][]uint64{},
+		buckets:    map[string]map[string]bool{},
+	}
+}
+
+// bandKeys splits a signature into bands, two quotes that share any band are candidates.
+func bandKeys(signature []uint64) []string {
+	rows := len(signature) / utils.MinHashBands
+	keys := make([]string, 0, utils.MinHashBands)
+	for band := 0; band < utils.MinHashBands; band++ {
+		keys = append(keys, fmt.Sprintf("%d:%x", band, signature[band*rows:(band+1)*rows]))
+	}
+	return keys
+}
+
+func (r *memoryDuplicateRepo) Index(id string, text string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.remove(id)
+	signature := utils.MinHash(text)
+	r.signatures[id] = signature
+	for _, key := range bandKeys(signature) {
+		if r.buckets[key] == nil {
+			r.buckets[key] = map[string]bool{}
+		}
+		r.buckets[key][id] = true
+	}
+	return nil
+}
+
+func (r *memoryDuplicateRepo) Remove(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.remove(id)
+	return nil
+}
+
+func (r *memoryDuplicateRepo) remove(id string) {
+	signature, ok := r.signatures[id]
+	if !ok {
+		return
+	}
+	for _, key := range bandKeys(signature) {
+		delete(r.buckets[key], id)
+		if len(r.buckets[key]) == 0 {
+			delete(r.buckets, key)
+		}
+	}
+	delete(r.signatures, id)
+}
+
+func (r *memoryDuplicateRepo) similar(signature []uint64, threshold float64) []models.SimilarQuoteModel {
+	seen := map[string]bool{}
+	result := []models.SimilarQuoteModel{}
+	for _, key := range bandKeys(signature) {
+		for id := range r.buckets[key] {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			if similarity := utils.MinHashSimilarity(signature, r.signatures[id]); similarity >= threshold {
+				result = append(result, models.SimilarQuoteModel{ID: id, Similarity: similarity})
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Similarity != result[j].Similarity {
+			return result[i].Similarity > result[j].Similarity
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+func (r *memoryDuplicateRepo) FindSimilar(text string, threshold float64) (result []models.SimilarQuoteModel, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.similar(utils.MinHash(text), threshold), nil
+}
+
+func (r *memoryDuplicateRepo) Clusters(threshold float64) (result []models.DuplicateClusterModel, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	parent := map[string]string{}
+	var find func(id string) string
+	find = func(id string) string {
+		if parent[id] != id {
+			parent[id] = find(parent[id])
+		}
+		return parent[id]
+	}
+	weakest := map[string]float64{}
+	for id := range r.signatures {
+		parent[id] = id
+	}
+	for id, signature := range r.signatures {
+		for _, match := range r.similar(signature, threshold) {
+			if match.ID == id {
+				continue
+			}
+			a, b := find(id), find(match.ID)
+			if a != b {
+				parent[b] = a
+				weakest[a] = min(weakestOr(weakest, a), weakestOr(weakest, b), match.Similarity)
+				delete(weakest, b)
+			} else {
+				weakest[a] = min(weakestOr(weakest, a), match.Similarity)
+			}
+		}
+	}
+	groups := map[string][]string{}
+	for id := range r.signatures {
+		root := find(id)
+		groups[root] = append(groups[root], id)
+	}
+	result = []models.DuplicateClusterModel{}
+	for root, ids := range groups {
+		if len(ids) < 2 {
+			continue
+		}
+		sort.Strings(ids)
+		result = append(result, models.DuplicateClusterModel{IDs: ids, Similarity: weakest[root]})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if len(result[i].IDs) != len(result[j].IDs) {
+			return len(result[i].IDs) > len(result[j].IDs)
+		}
+		return result[i].IDs[0] < result[j].IDs[0]
+	})
+	return result, nil
+}
+
+func weakestOr(weakest map[string]float64, root string) float64 {
+	if value, ok := weakest[root]; ok {
+		return value
+	}
+	return 1
+}
@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"backend/core/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type favoriteRepoMock struct {
+	mock.Mock
+}
+
+func NewFavoriteRepositoryMock() *favoriteRepoMock {
+	return &favoriteRepoMock{}
+}
+
+func (m *favoriteRepoMock) AddFavorite(payload models.CreateFavoriteModel) (created bool, err error) {
+	args := m.Called(payload)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *favoriteRepoMock) RemoveFavorite(userID string, quoteID string) (removed bool, err error) {
+	args := m.Called(userID, quoteID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *favoriteRepoMock) GetFavorites(userID string, page int, limit int) (result []models.FavoriteModel, total int64, err error) {
+	args := m.Called(userID, page, limit)
+	return args.Get(0).([]models.FavoriteModel), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *favoriteRepoMock) GetAllFavorites(userID string) (result []models.FavoriteModel, err error) {
+	args := m.Called(userID)
+	return args.Get(0).([]models.FavoriteModel), args.Error(1)
+}
+
+func (m *favoriteRepoMock) GetFavoritedIDs(userID string, quoteIDs []string) (result []string, err error) {
+	args := m.Called(userID, quoteIDs)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *favoriteRepoMock) DeleteFavoritesByQuote(quoteID string) error {
+	args := m.Called(quoteID)
+	return args.Error(0)
+}
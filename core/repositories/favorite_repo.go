@@ -0,0 +1,130 @@
+package repositories
+
+import (
+	"backend/core/models"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type FavoriteRepository interface {
+	AddFavorite(payload models.CreateFavoriteModel) (created bool, err error)
+
+	RemoveFavorite(userID string, quoteID string) (removed bool, err error)
+
+	GetFavorites(userID string, page int, limit int) (result []models.FavoriteModel, total int64, err error)
+
+	GetAllFavorites(userID string) (result []models.FavoriteModel, err error)
+
+	GetFavoritedIDs(userID string, quoteIDs []string) (result []string, err error)
+
+	DeleteFavoritesByQuote(quoteID string) error
+}
+
+type favoriteRepo struct {
+	db         *mongo.Database
+	collection string
+}
+
+func NewFavoriteRepository(db *mongo.Database, collection string) FavoriteRepository {
+	return &favoriteRepo{
+		db:         db,
+		collection: collection,
+	}
+}
+
+// AddFavorite saves a quote for a user, created is false when it was already saved.
+func (r *favoriteRepo) AddFavorite(payload models.CreateFavoriteModel) (created bool, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.D{{Key: "user_id", Value: payload.UserID}, {Key: "quote_id", Value: payload.QuoteID}}
+	update := bson.D{{Key: "$setOnInsert", Value: payload}}
+	res, err := r.db.Collection(r.collection).UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return false, err
+	}
+	return res.UpsertedCount > 0, nil
+}
+
+func (r *favoriteRepo) RemoveFavorite(userID string, quoteID string) (removed bool, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.D{{Key: "user_id", Value: userID}, {Key: "quote_id", Value: quoteID}}
+	res, err := r.db.Collection(r.collection).DeleteOne(ctx, filter)
+	if err != nil {
+		return false, err
+	}
+	return res.DeletedCount > 0, nil
+}
+
+// GetFavorites lists the saved quotes of a user, most recently saved first.
+func (r *favoriteRepo) GetFavorites(userID string, page int, limit int) (result []models.FavoriteModel, total int64, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.D{{Key: "user_id", Value: userID}}
+	total, err = r.db.Collection(r.collection).CountDocuments(ctx, filter)
+	if err != nil {
+		return result, 0, err
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "create_date", Value: -1}, {Key: "quote_id", Value: 1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+	cursor, err := r.db.Collection(r.collection).Find(ctx, filter, opts)
+	if err != nil {
+		return result, 0, err
+	}
+	if err = cursor.All(ctx, &result); err != nil {
+		return result, 0, err
+	}
+	return result, total, nil
+}
+
+func (r *favoriteRepo) GetAllFavorites(userID string) (result []models.FavoriteModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.D{{Key: "user_id", Value: userID}}
+	opts := options.Find().SetSort(bson.D{{Key: "create_date", Value: 1}})
+	cursor, err := r.db.Collection(r.collection).Find(ctx, filter, opts)
+	if err != nil {
+		return result, err
+	}
+	if err = cursor.All(ctx, &result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// GetFavoritedIDs returns which of quoteIDs the user has saved.
+func (r *favoriteRepo) GetFavoritedIDs(userID string, quoteIDs []string) (result []string, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.D{{Key: "user_id", Value: userID}, {Key: "quote_id", Value: bson.D{{Key: "$in", Value: quoteIDs}}}}
+	cursor, err := r.db.Collection(r.collection).Find(ctx, filter, options.Find().SetProjection(bson.D{{Key: "quote_id", Value: 1}}))
+	if err != nil {
+		return result, err
+	}
+	var favorites []models.FavoriteModel
+	if err = cursor.All(ctx, &favorites); err != nil {
+		return result, err
+	}
+	for _, favorite := range favorites {
+		result = append(result, favorite.QuoteID)
+	}
+	return result, nil
+}
+
+func (r *favoriteRepo) DeleteFavoritesByQuote(quoteID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := r.db.Collection(r.collection).DeleteMany(ctx, bson.D{{Key: "quote_id", Value: quoteID}})
+	return err
+}
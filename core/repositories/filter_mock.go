@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"backend/core/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type filterRepoMock struct {
+	mock.Mock
+}
+
+func NewFilterRepositoryMock() *filterRepoMock {
+	return &filterRepoMock{}
+}
+
+func (m *filterRepoMock) GetRules() (result models.FilterRulesModel, err error) {
+	args := m.Called()
+	return args.Get(0).(models.FilterRulesModel), args.Error(1)
+}
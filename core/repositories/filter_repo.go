@@ -0,0 +1,39 @@
+package repositories
+
+import (
+	"backend/core/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+type FilterRepository interface {
+	GetRules() (result models.FilterRulesModel, err error)
+}
+
+type fileFilterRepo struct {
+	path string
+}
+
+// NewFileFilterRepository reads the content filter rules from a JSON file on
+// every call so edits are picked up without a restart, a missing file has no rules.
+func NewFileFilterRepository(path string) FilterRepository {
+	return &fileFilterRepo{
+		path: path,
+	}
+}
+
+func (r *fileFilterRepo) GetRules() (result models.FilterRulesModel, err error) {
+	data, err := os.ReadFile(r.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return models.FilterRulesModel{}, nil
+	}
+	if err != nil {
+		return result, err
+	}
+	if err = json.Unmarshal(data, &result); err != nil {
+		return result, fmt.Errorf("content filter %s: %w", r.path, err)
+	}
+	return result, nil
+}
@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type leaseRepoMock struct {
+	mock.Mock
+}
+
+func NewLeaseRepositoryMock() *leaseRepoMock {
+	return &leaseRepoMock{}
+}
+
+func (m *leaseRepoMock) AcquireLease(name string, owner string, ttl time.Duration) (acquired bool, err error) {
+	args := m.Called(name, owner, ttl)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *leaseRepoMock) ReleaseLease(name string, owner string) error {
+	args := m.Called(name, owner)
+	return args.Error(0)
+}
@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// LeaseRepository hands out named leases so a background job runs on one
+// instance at a time.
+type LeaseRepository interface {
+	AcquireLease(name string, owner string, ttl time.Duration) (acquired bool, err error)
+
+	ReleaseLease(name string, owner string) error
+}
+
+type leaseRepo struct {
+	db         *mongo.Database
+	collection string
+}
+
+func NewLeaseRepository(db *mongo.Database, collection string) LeaseRepository {
+	return &leaseRepo{
+		db:         db,
+		collection: collection,
+	}
+}
+
+// AcquireLease takes or renews the lease for ttl. It is not acquired while
+// another owner holds a lease that has not expired.
+func (r *leaseRepo) AcquireLease(name string, owner string, ttl time.Duration) (acquired bool, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	filter := bson.D{{Key: "_id", Value: name}, {Key: "$or", Value: bson.A{
+		bson.D{{Key: "owner", Value: owner}},
+		bson.D{{Key: "expires_at", Value: bson.D{{Key: "$lte", Value: now}}}},
+	}}}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "owner", Value: owner},
+		{Key: "expires_at", Value: now.Add(ttl)},
+	}}}
+	_, err = r.db.Collection(r.collection).UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		// the filter missed a live lease of another owner and the upsert hit its _id
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *leaseRepo) ReleaseLease(name string, owner string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := r.db.Collection(r.collection).DeleteOne(ctx, bson.D{{Key: "_id", Value: name}, {Key: "owner", Value: owner}})
+	return err
+}
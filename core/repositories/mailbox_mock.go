@@ -0,0 +1,28 @@
+package repositories
+
+import (
+	"github.com/stretchr/testify/mock"
+)
+
+type mailboxRepoMock struct {
+	mock.Mock
+}
+
+func NewMailboxRepositoryMock() *mailboxRepoMock {
+	return &mailboxRepoMock{}
+}
+
+func (m *mailboxRepoMock) List() (names []string, err error) {
+	args := m.Called()
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *mailboxRepoMock) Claim(name string) (raw []byte, err error) {
+	args := m.Called(name)
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (m *mailboxRepoMock) Finish(name string, failed bool) error {
+	args := m.Called(name, failed)
+	return args.Error(0)
+}
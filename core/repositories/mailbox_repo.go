@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type MailboxRepository interface {
+	// List returns the names of the messages that have not been read yet, oldest first.
+	List() (names []string, err error)
+
+	// Claim takes a message for reading, it fails when another reader claimed it first.
+	Claim(name string) (raw []byte, err error)
+
+	// Finish marks a claimed message as seen, failed messages are flagged too.
+	Finish(name string, failed bool) error
+}
+
+type maildirRepo struct {
+	path string
+}
+
+// NewMaildirRepository reads the messages a mail server delivers to the
+// Maildir at path. A message is moved from new to cur when it is claimed so a
+// message is only read once, an empty path or a missing Maildir has no mail.
+func NewMaildirRepository(path string) MailboxRepository {
+	return &maildirRepo{
+		path: path,
+	}
+}
+
+func (r *maildirRepo) List() (names []string, err error) {
+	names = []string{}
+	if r.path == "" {
+		return names, nil
+	}
+	entries, err := os.ReadDir(filepath.Join(r.path, "new"))
+	if errors.Is(err, os.ErrNotExist) {
+		return names, nil
+	}
+	if err != nil {
+		return names, err
+	}
+	for _, entry := range entries {
+		// dot files are not messages, tmp holds the ones still being delivered
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	// delivery names start with the unix time so they sort oldest first
+	sort.Strings(names)
+	return names, nil
+}
+
+func (r *maildirRepo) Claim(name string) (raw []byte, err error) {
+	if name != filepath.Base(name) {
+		return nil, errors.New("message name invalid")
+	}
+	claimed := r.cur(name, "")
+	if err = os.Rename(filepath.Join(r.path, "new", name), claimed); err != nil {
+		return nil, err
+	}
+	return os.ReadFile(claimed)
+}
+
+func (r *maildirRepo) Finish(name string, failed bool) error {
+	flags := "S"
+	if failed {
+		flags = "FS"
+	}
+	return os.Rename(r.cur(name, ""), r.cur(name, flags))
+}
+
+// cur is the path of a message in cur with the Maildir info and flags
+// appended, flags are kept in ASCII order as the format asks.
+func (r *maildirRepo) cur(name string, flags string) string {
+	return filepath.Join(r.path, "cur", name+":2,"+flags)
+}
@@ -0,0 +1,40 @@
+package repositories
+
+import (
+	"backend/core/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type notificationRepoMock struct {
+	mock.Mock
+}
+
+func NewNotificationRepositoryMock() *notificationRepoMock {
+	return &notificationRepoMock{}
+}
+
+func (m *notificationRepoMock) CreateNotification(notification models.CreateNotificationModel) error {
+	args := m.Called(notification)
+	return args.Error(0)
+}
+
+func (m *notificationRepoMock) GetNotifications(userID string, page int, limit int) (result []models.NotificationModel, total int64, unread int64, err error) {
+	args := m.Called(userID, page, limit)
+	return args.Get(0).([]models.NotificationModel), args.Get(1).(int64), args.Get(2).(int64), args.Error(3)
+}
+
+func (m *notificationRepoMock) GetAllNotifications(userID string) (result []models.NotificationModel, err error) {
+	args := m.Called(userID)
+	return args.Get(0).([]models.NotificationModel), args.Error(1)
+}
+
+func (m *notificationRepoMock) MarkNotificationsRead(userID string) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func (m *notificationRepoMock) DeleteNotificationsByUser(userID string) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
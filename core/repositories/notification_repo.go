@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"backend/core/models"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type NotificationRepository interface {
+	CreateNotification(notification models.CreateNotificationModel) error
+
+	GetNotifications(userID string, page int, limit int) (result []models.NotificationModel, total int64, unread int64, err error)
+
+	GetAllNotifications(userID string) (result []models.NotificationModel, err error)
+
+	MarkNotificationsRead(userID string) error
+
+	DeleteNotificationsByUser(userID string) error
+}
+
+type notificationRepo struct {
+	db         *mongo.Database
+	collection string
+}
+
+func NewNotificationRepository(db *mongo.Database, collection string) NotificationRepository {
+	return &notificationRepo{
+		db:         db,
+		collection: collection,
+	}
+}
+
+func (r *notificationRepo) CreateNotification(notification models.CreateNotificationModel) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := r.db.Collection(r.collection).InsertOne(ctx, notification)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *notificationRepo) GetNotifications(userID string, page int, limit int) (result []models.NotificationModel, total int64, unread int64, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	filter := bson.D{{Key: "user_id", Value: userID}}
+	total, err = r.db.Collection(r.collection).CountDocuments(ctx, filter)
+	if err != nil {
+		return result, 0, 0, err
+	}
+	unread, err = r.db.Collection(r.collection).CountDocuments(ctx, bson.D{{Key: "user_id", Value: userID}, {Key: "read", Value: false}})
+	if err != nil {
+		return result, 0, 0, err
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "create_date", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+	cursor, err := r.db.Collection(r.collection).Find(ctx, filter, opts)
+	if err != nil {
+		return result, 0, 0, err
+	}
+	if err = cursor.All(ctx, &result); err != nil {
+		return result, 0, 0, err
+	}
+	return result, total, unread, nil
+}
+
+func (r *notificationRepo) GetAllNotifications(userID string) (result []models.NotificationModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	filter := bson.D{{Key: "user_id", Value: userID}}
+	opts := options.Find().SetSort(bson.D{{Key: "create_date", Value: 1}})
+	cursor, err := r.db.Collection(r.collection).Find(ctx, filter, opts)
+	if err != nil {
+		return result, err
+	}
+	if err = cursor.All(ctx, &result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+func (r *notificationRepo) MarkNotificationsRead(userID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	filter := bson.D{{Key: "user_id", Value: userID}, {Key: "read", Value: false}}
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "read", Value: true}}}}
+	_, err := r.db.Collection(r.collection).UpdateMany(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *notificationRepo) DeleteNotificationsByUser(userID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	filter := bson.D{{Key: "user_id", Value: userID}}
+	_, err := r.db.Collection(r.collection).DeleteMany(ctx, filter)
+	if err != nil {
+		return err
+	}
+	return nil
+}
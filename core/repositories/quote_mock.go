@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"backend/core/models"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -14,21 +15,36 @@ func NewQuoteRepositoryMock() *quoteRepoMock {
 	return &quoteRepoMock{}
 }
 
-func (m *quoteRepoMock) GetQuotes() (result []models.QuoteModel, err error) {
-	args := m.Called()
+func (m *quoteRepoMock) GetQuotes(filter models.QuoteFilterModel) (result []models.QuoteModel, err error) {
+	args := m.Called(filter)
 	return args.Get(0).([]models.QuoteModel), args.Error(1)
 }
 
+func (m *quoteRepoMock) CountQuotes(filter models.QuoteFilterModel) (total int64, err error) {
+	args := m.Called(filter)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *quoteRepoMock) GetQuote(id string) (result models.QuoteModel, err error) {
 	args := m.Called(id)
 	return args.Get(0).(models.QuoteModel), args.Error(1)
 }
 
+func (m *quoteRepoMock) GetQuotesByIDs(ids []string) (result []models.QuoteModel, err error) {
+	args := m.Called(ids)
+	return args.Get(0).([]models.QuoteModel), args.Error(1)
+}
+
 func (m *quoteRepoMock) CreateQuote(payload models.CreateQuoteModel) (result models.QuoteModel, err error) {
 	args := m.Called(payload)
 	return args.Get(0).(models.QuoteModel), args.Error(1)
 }
 
+func (m *quoteRepoMock) CreateQuotes(payloads []models.CreateQuoteModel) (inserted int, err error) {
+	args := m.Called(payloads)
+	return args.Int(0), args.Error(1)
+}
+
 func (m *quoteRepoMock) UpdateQuote(id string, payload models.UpdateQuoteModel) (result models.QuoteModel, err error) {
 	args := m.Called(id, payload)
 	return args.Get(0).(models.QuoteModel), args.Error(1)
@@ -38,3 +54,133 @@ func (m *quoteRepoMock) DeleteQuote(id string) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
+
+func (m *quoteRepoMock) GetQuotesByCreator(userID string) (result []models.QuoteModel, err error) {
+	args := m.Called(userID)
+	return args.Get(0).([]models.QuoteModel), args.Error(1)
+}
+
+func (m *quoteRepoMock) IncrementVote(id string, delta int) error {
+	args := m.Called(id, delta)
+	return args.Error(0)
+}
+
+func (m *quoteRepoMock) IncrementFavoriteCount(id string, delta int) error {
+	args := m.Called(id, delta)
+	return args.Error(0)
+}
+
+func (m *quoteRepoMock) IncrementCommentCount(id string, delta int) error {
+	args := m.Called(id, delta)
+	return args.Error(0)
+}
+
+func (m *quoteRepoMock) GetRandomQuotes(filter models.QuoteFilterModel, size int) (result []models.QuoteModel, err error) {
+	args := m.Called(filter, size)
+	return args.Get(0).([]models.QuoteModel), args.Error(1)
+}
+
+func (m *quoteRepoMock) IncrementReaction(id string, emoji string, delta int) error {
+	args := m.Called(id, emoji, delta)
+	return args.Error(0)
+}
+
+func (m *quoteRepoMock) ResetVote(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *quoteRepoMock) SoftDeleteQuote(id string, userID string) (result models.QuoteModel, err error) {
+	args := m.Called(id, userID)
+	return args.Get(0).(models.QuoteModel), args.Error(1)
+}
+
+func (m *quoteRepoMock) ModerateQuote(id string, status string, moderatorID string, reason string) (result models.QuoteModel, err error) {
+	args := m.Called(id, status, moderatorID, reason)
+	return args.Get(0).(models.QuoteModel), args.Error(1)
+}
+
+func (m *quoteRepoMock) GetDueQuotes(now time.Time) (result []models.QuoteModel, err error) {
+	args := m.Called(now)
+	return args.Get(0).([]models.QuoteModel), args.Error(1)
+}
+
+func (m *quoteRepoMock) PublishQuote(id string) (result models.QuoteModel, err error) {
+	args := m.Called(id)
+	return args.Get(0).(models.QuoteModel), args.Error(1)
+}
+
+func (m *quoteRepoMock) GetGroup(groupID string) (result []models.QuoteModel, err error) {
+	args := m.Called(groupID)
+	return args.Get(0).([]models.QuoteModel), args.Error(1)
+}
+
+func (m *quoteRepoMock) GetGroups(groupIDs []string) (result []models.QuoteModel, err error) {
+	args := m.Called(groupIDs)
+	return args.Get(0).([]models.QuoteModel), args.Error(1)
+}
+
+func (m *quoteRepoMock) SetGroup(ids []string, groupID string, vote int) error {
+	args := m.Called(ids, groupID, vote)
+	return args.Error(0)
+}
+
+func (m *quoteRepoMock) HideQuote(id string, reason string) (result models.QuoteModel, err error) {
+	args := m.Called(id, reason)
+	return args.Get(0).(models.QuoteModel), args.Error(1)
+}
+
+func (m *quoteRepoMock) GetModerationQueue(page int, limit int) (result []models.QuoteModel, total int64, err error) {
+	args := m.Called(page, limit)
+	return args.Get(0).([]models.QuoteModel), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *quoteRepoMock) RestoreQuote(id string) (result models.QuoteModel, err error) {
+	args := m.Called(id)
+	return args.Get(0).(models.QuoteModel), args.Error(1)
+}
+
+func (m *quoteRepoMock) GetTrash(page int, limit int) (result []models.QuoteModel, total int64, err error) {
+	args := m.Called(page, limit)
+	return args.Get(0).([]models.QuoteModel), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *quoteRepoMock) GetQuotesDeletedBefore(cutoff time.Time) (result []models.QuoteModel, err error) {
+	args := m.Called(cutoff)
+	return args.Get(0).([]models.QuoteModel), args.Error(1)
+}
+
+func (m *quoteRepoMock) GetQuotesUpdatedSince(since time.Time) (result []models.QuoteModel, err error) {
+	args := m.Called(since)
+	return args.Get(0).([]models.QuoteModel), args.Error(1)
+}
+
+func (m *quoteRepoMock) ClearCreator(userID string) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func (m *quoteRepoMock) ReplaceTag(oldName string, newName string) error {
+	args := m.Called(oldName, newName)
+	return args.Error(0)
+}
+
+func (m *quoteRepoMock) CountByTag() (result map[string]int64, err error) {
+	args := m.Called()
+	return args.Get(0).(map[string]int64), args.Error(1)
+}
+
+func (m *quoteRepoMock) CountByCategory() (result map[string]int64, err error) {
+	args := m.Called()
+	return args.Get(0).(map[string]int64), args.Error(1)
+}
+
+func (m *quoteRepoMock) SetAuthorName(authorID string, name string) error {
+	args := m.Called(authorID, name)
+	return args.Error(0)
+}
+
+func (m *quoteRepoMock) MigrateQuotes() (migrated int64, err error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -3,22 +3,82 @@ package repositories
 import (
 	"backend/core/models"
 	"context"
+	"errors"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type QuoteRepository interface {
-	GetQuotes() (result []models.QuoteModel, err error)
+	GetQuotes(filter models.QuoteFilterModel) (result []models.QuoteModel, err error)
+
+	CountQuotes(filter models.QuoteFilterModel) (total int64, err error)
+
+	GetRandomQuotes(filter models.QuoteFilterModel, size int) (result []models.QuoteModel, err error)
 
 	GetQuote(id string) (result models.QuoteModel, err error)
 
+	GetQuotesByIDs(ids []string) (result []models.QuoteModel, err error)
+
 	CreateQuote(quote models.CreateQuoteModel) (result models.QuoteModel, err error)
 
+	CreateQuotes(quotes []models.CreateQuoteModel) (inserted int, err error)
+
 	UpdateQuote(id string, payload models.UpdateQuoteModel) (result models.QuoteModel, err error)
 
 	DeleteQuote(id string) error
+
+	GetQuotesByCreator(userID string) (result []models.QuoteModel, err error)
+
+	IncrementVote(id string, delta int) error
+
+	ResetVote(id string) error
+
+	GetGroup(groupID string) (result []models.QuoteModel, err error)
+
+	GetGroups(groupIDs []string) (result []models.QuoteModel, err error)
+
+	SetGroup(ids []string, groupID string, vote int) error
+
+	IncrementFavoriteCount(id string, delta int) error
+
+	IncrementCommentCount(id string, delta int) error
+
+	IncrementReaction(id string, emoji string, delta int) error
+
+	SoftDeleteQuote(id string, userID string) (result models.QuoteModel, err error)
+
+	ModerateQuote(id string, status string, moderatorID string, reason string) (result models.QuoteModel, err error)
+
+	GetModerationQueue(page int, limit int) (result []models.QuoteModel, total int64, err error)
+
+	GetDueQuotes(now time.Time) (result []models.QuoteModel, err error)
+
+	PublishQuote(id string) (result models.QuoteModel, err error)
+
+	HideQuote(id string, reason string) (result models.QuoteModel, err error)
+
+	RestoreQuote(id string) (result models.QuoteModel, err error)
+
+	GetTrash(page int, limit int) (result []models.QuoteModel, total int64, err error)
+
+	GetQuotesDeletedBefore(cutoff time.Time) (result []models.QuoteModel, err error)
+
+	GetQuotesUpdatedSince(since time.Time) (result []models.QuoteModel, err error)
+
+	ClearCreator(userID string) error
+
+	ReplaceTag(oldName string, newName string) error
+
+	CountByTag() (result map[string]int64, err error)
+
+	CountByCategory() (result map[string]int64, err error)
+
+	SetAuthorName(authorID string, name string) error
+
+	MigrateQuotes() (migrated int64, err error)
 }
 
 type QuoteRepo struct {
@@ -33,11 +93,134 @@ func NewQuoteRepository(db *mongo.Database, collection string) QuoteRepository {
 	}
 }
 
-func (r *QuoteRepo) GetQuotes() (result []models.QuoteModel, err error) {
+// quoteSortField maps the public sort names onto document fields.
+var quoteSortField = map[string]string{
+	models.QuoteSortVote:       "vote",
+	models.QuoteSortCreateDate: "create_date",
+	models.QuoteSortUpdateDate: "update_date",
+}
+
+// notDeleted matches quotes that are not in the trash, it also matches quotes
+// created before soft delete existed.
+var notDeleted = bson.E{Key: "deleted_at", Value: nil}
+
+// quoteFilter builds the match stage shared by GetQuotes and CountQuotes,
+// the keyset cursor is left out so counts cover every page.
+func quoteFilter(filter models.QuoteFilterModel) bson.D {
+	statuses := filter.Statuses
+	if len(statuses) == 0 {
+		statuses = []string{models.QuoteStatusApproved}
+	}
+	match := bson.D{notDeleted, {Key: "status", Value: bson.D{{Key: "$in", Value: statuses}}}}
+	if filter.MinVotes != nil {
+		match = append(match, bson.E{Key: "vote", Value: bson.D{{Key: "$gte", Value: *filter.MinVotes}}})
+	}
+	created := bson.D{}
+	if filter.CreatedFrom != nil {
+		created = append(created, bson.E{Key: "$gte", Value: *filter.CreatedFrom})
+	}
+	if filter.CreatedTo != nil {
+		created = append(created, bson.E{Key: "$lte", Value: *filter.CreatedTo})
+	}
+	if len(created) > 0 {
+		match = append(match, bson.E{Key: "create_date", Value: created})
+	}
+	if len(filter.Tags) > 0 {
+		match = append(match, bson.E{Key: "tags", Value: bson.D{{Key: "$all", Value: filter.Tags}}})
+	}
+	if len(filter.CategoryIDs) > 0 {
+		match = append(match, bson.E{Key: "category_id", Value: bson.D{{Key: "$in", Value: filter.CategoryIDs}}})
+	}
+	if filter.AuthorID != "" {
+		match = append(match, bson.E{Key: "author_id", Value: filter.AuthorID})
+	}
+	if filter.Language != "" {
+		match = append(match, bson.E{Key: "language", Value: filter.Language})
+	}
+	if len(filter.ExcludeIDs) > 0 {
+		match = append(match, bson.E{Key: "id", Value: bson.D{{Key: "$nin", Value: filter.ExcludeIDs}}})
+	}
+	return match
+}
+
+// groupFirst keeps one quote per translation group, the oldest of the group
+// that matched, ungrouped quotes are groups of their own. A group whose root
+// is pending or hidden is still listed through its other translations.
+var groupFirst = []bson.D{
+	{{Key: "$sort", Value: bson.D{{Key: "create_date", Value: 1}, {Key: "id", Value: 1}}}},
+	{{Key: "$group", Value: bson.D{
+		{Key: "_id", Value: bson.D{{Key: "$cond", Value: bson.A{
+			bson.D{{Key: "$eq", Value: bson.A{bson.D{{Key: "$ifNull", Value: bson.A{"$group_id", ""}}}, ""}}},
+			"$id",
+			"$group_id",
+		}}}},
+		{Key: "quote", Value: bson.D{{Key: "$first", Value: "$$ROOT"}}},
+	}}},
+	{{Key: "$replaceRoot", Value: bson.D{{Key: "newRoot", Value: "$quote"}}}},
+}
+
+// quotePipeline starts every quote listing, the match of quoteFilter and,
+// unless translations are asked for, one quote per translation group.
+func quotePipeline(filter models.QuoteFilterModel) mongo.Pipeline {
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: quoteFilter(filter)}}}
+	if !filter.Translations {
+		pipeline = append(pipeline, groupFirst...)
+	}
+	return pipeline
+}
+
+// matchAll adds a condition under the $and of match. Conditions that share a
+// key such as $or can not sit side by side in a match, MongoDB keeps only one.
+func matchAll(match bson.D, condition bson.D) bson.D {
+	for i, e := range match {
+		if e.Key == "$and" {
+			match[i].Value = append(e.Value.(bson.A), condition)
+			return match
+		}
+	}
+	return append(match, bson.E{Key: "$and", Value: bson.A{condition}})
+}
+
+// quotePageFilter is the match of GetQuotes, quoteFilter past the keyset cursor.
+// It runs after the translation groups are folded so the cursor compares the
+// quotes that were listed.
+func quotePageFilter(filter models.QuoteFilterModel, field string, op string) bson.D {
+	match := bson.D{}
+	if filter.After != nil {
+		var value interface{} = filter.After.Date
+		if field == "vote" {
+			value = filter.After.Vote
+		}
+		match = matchAll(match, bson.D{{Key: "$or", Value: bson.A{
+			bson.D{{Key: field, Value: bson.D{{Key: op, Value: value}}}},
+			bson.D{{Key: field, Value: value}, {Key: "id", Value: bson.D{{Key: op, Value: filter.After.ID}}}},
+		}}})
+	}
+	return match
+}
+
+func (r *QuoteRepo) GetQuotes(filter models.QuoteFilterModel) (result []models.QuoteModel, err error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	cursor, err := r.db.Collection(r.collection).Find(ctx, bson.D{})
+	field := quoteSortField[filter.Sort]
+	if field == "" {
+		field = "create_date"
+	}
+	dir, op := 1, "$gt"
+	if filter.Descending {
+		dir, op = -1, "$lt"
+	}
+
+	pipeline := quotePipeline(filter)
+	if match := quotePageFilter(filter, field, op); len(match) > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: match}})
+	}
+	pipeline = append(pipeline, bson.D{{Key: "$sort", Value: bson.D{{Key: field, Value: dir}, {Key: "id", Value: dir}}}})
+	if filter.Limit > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$limit", Value: filter.Limit}})
+	}
+	cursor, err := r.db.Collection(r.collection).Aggregate(ctx, pipeline)
 	if err != nil {
 		return result, err
 	}
@@ -48,10 +231,34 @@ func (r *QuoteRepo) GetQuotes() (result []models.QuoteModel, err error) {
 	return result, nil
 }
 
+func (r *QuoteRepo) CountQuotes(filter models.QuoteFilterModel) (total int64, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if filter.Translations {
+		return r.db.Collection(r.collection).CountDocuments(ctx, quoteFilter(filter))
+	}
+	pipeline := append(quotePipeline(filter), bson.D{{Key: "$count", Value: "total"}})
+	cursor, err := r.db.Collection(r.collection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return total, err
+	}
+	var counts []struct {
+		Total int64 `bson:"total"`
+	}
+	if err = cursor.All(ctx, &counts); err != nil {
+		return total, err
+	}
+	if len(counts) > 0 {
+		total = counts[0].Total
+	}
+	return total, nil
+}
+
 func (r *QuoteRepo) GetQuote(id string) (result models.QuoteModel, err error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
-	filter := bson.D{{Key: "id", Value: id}}
+	filter := bson.D{{Key: "id", Value: id}, notDeleted}
 	err = r.db.Collection(r.collection).FindOne(ctx, filter).Decode(&result)
 	if err != nil {
 		return result, err
@@ -59,6 +266,20 @@ func (r *QuoteRepo) GetQuote(id string) (result models.QuoteModel, err error) {
 	return result, nil
 }
 
+func (r *QuoteRepo) GetQuotesByIDs(ids []string) (result []models.QuoteModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	filter := bson.D{{Key: "id", Value: bson.D{{Key: "$in", Value: ids}}}, notDeleted}
+	cursor, err := r.db.Collection(r.collection).Find(ctx, filter)
+	if err != nil {
+		return result, err
+	}
+	if err = cursor.All(ctx, &result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
 func (r *QuoteRepo) CreateQuote(payload models.CreateQuoteModel) (result models.QuoteModel, err error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -74,30 +295,564 @@ func (r *QuoteRepo) CreateQuote(payload models.CreateQuoteModel) (result models.
 	return result, nil
 }
 
+// CreateQuotes inserts the quotes in order with one round trip, on error
+// inserted says how many of the leading quotes were stored.
+func (r *QuoteRepo) CreateQuotes(payloads []models.CreateQuoteModel) (inserted int, err error) {
+	if len(payloads) == 0 {
+		return 0, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	docs := make([]interface{}, len(payloads))
+	for i, payload := range payloads {
+		docs[i] = payload
+	}
+	_, err = r.db.Collection(r.collection).InsertMany(ctx, docs)
+	if err != nil {
+		var bulkErr mongo.BulkWriteException
+		if errors.As(err, &bulkErr) && len(bulkErr.WriteErrors) > 0 {
+			return bulkErr.WriteErrors[0].Index, err
+		}
+		return 0, err
+	}
+	return len(payloads), nil
+}
+
 func (r *QuoteRepo) UpdateQuote(id string, payload models.UpdateQuoteModel) (result models.QuoteModel, err error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	filter := bson.D{{Key: "id", Value: id}}
 
-	err = r.db.Collection(r.collection).FindOne(ctx, filter).Decode(&result)
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = r.db.Collection(r.collection).FindOneAndUpdate(ctx, filter, bson.D{{Key: "$set", Value: payload}}, opts).Decode(&result)
 	if err != nil {
 		return result, err
 	}
-	_, err = r.db.Collection(r.collection).UpdateOne(ctx, filter, bson.D{{Key: "$set", Value: payload}})
+	return result, nil
+}
+
+func (r *QuoteRepo) DeleteQuote(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	filter := bson.D{{Key: "id", Value: id}}
+	_, err := r.db.Collection(r.collection).DeleteOne(ctx, filter)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// SoftDeleteQuote moves a quote to the trash, its votes are released so the tally starts from 0.
+func (r *QuoteRepo) SoftDeleteQuote(id string, userID string) (result models.QuoteModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.D{{Key: "id", Value: id}, notDeleted}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "deleted_at", Value: time.Now()},
+		{Key: "deleted_by", Value: userID},
+		{Key: "vote", Value: 0},
+		{Key: "update_date", Value: time.Now()},
+	}}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = r.db.Collection(r.collection).FindOneAndUpdate(ctx, filter, update, opts).Decode(&result)
 	if err != nil {
 		return result, err
 	}
 	return result, nil
 }
 
-func (r *QuoteRepo) DeleteQuote(id string) error {
+func (r *QuoteRepo) RestoreQuote(id string) (result models.QuoteModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.D{{Key: "id", Value: id}, {Key: "deleted_at", Value: bson.D{{Key: "$ne", Value: nil}}}}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "deleted_at", Value: nil},
+		{Key: "deleted_by", Value: ""},
+		{Key: "update_date", Value: time.Now()},
+	}}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = r.db.Collection(r.collection).FindOneAndUpdate(ctx, filter, update, opts).Decode(&result)
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// ModerateQuote decides on a pending quote. An approved quote whose publish_at
+// is still ahead becomes scheduled instead.
+func (r *QuoteRepo) ModerateQuote(id string, status string, moderatorID string, reason string) (result models.QuoteModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	var newStatus interface{} = bson.D{{Key: "$literal", Value: status}}
+	if status == models.QuoteStatusApproved {
+		newStatus = bson.D{{Key: "$cond", Value: bson.A{
+			bson.D{{Key: "$gt", Value: bson.A{"$publish_at", now}}},
+			models.QuoteStatusScheduled,
+			models.QuoteStatusApproved,
+		}}}
+	}
+	filter := bson.D{{Key: "id", Value: id}, {Key: "status", Value: models.QuoteStatusPending}, notDeleted}
+	update := mongo.Pipeline{bson.D{{Key: "$set", Value: bson.D{
+		{Key: "status", Value: newStatus},
+		{Key: "moderated_by", Value: bson.D{{Key: "$literal", Value: moderatorID}}},
+		{Key: "moderated_at", Value: now},
+		{Key: "reject_reason", Value: bson.D{{Key: "$literal", Value: reason}}},
+		{Key: "update_date", Value: now},
+	}}}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = r.db.Collection(r.collection).FindOneAndUpdate(ctx, filter, update, opts).Decode(&result)
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// GetDueQuotes returns the scheduled quotes whose publish_at has passed.
+func (r *QuoteRepo) GetDueQuotes(now time.Time) (result []models.QuoteModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.D{
+		{Key: "status", Value: models.QuoteStatusScheduled},
+		{Key: "publish_at", Value: bson.D{{Key: "$lte", Value: now}}},
+		notDeleted,
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "publish_at", Value: 1}})
+	cursor, err := r.db.Collection(r.collection).Find(ctx, filter, opts)
+	if err != nil {
+		return result, err
+	}
+	if err = cursor.All(ctx, &result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// PublishQuote makes a scheduled quote public, it fails when the quote was
+// published or unscheduled in the meantime.
+func (r *QuoteRepo) PublishQuote(id string) (result models.QuoteModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.D{{Key: "id", Value: id}, {Key: "status", Value: models.QuoteStatusScheduled}, notDeleted}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "status", Value: models.QuoteStatusApproved},
+		{Key: "update_date", Value: time.Now()},
+	}}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = r.db.Collection(r.collection).FindOneAndUpdate(ctx, filter, update, opts).Decode(&result)
+	return result, err
+}
+
+// HideQuote sends an approved quote back to the moderation queue.
+func (r *QuoteRepo) HideQuote(id string, reason string) (result models.QuoteModel, err error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
+
+	filter := bson.D{{Key: "id", Value: id}, {Key: "status", Value: models.QuoteStatusApproved}, notDeleted}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "status", Value: models.QuoteStatusPending},
+		{Key: "flag_reasons", Value: []string{reason}},
+		{Key: "update_date", Value: time.Now()},
+	}}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = r.db.Collection(r.collection).FindOneAndUpdate(ctx, filter, update, opts).Decode(&result)
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// GetModerationQueue lists pending quotes, oldest first.
+func (r *QuoteRepo) GetModerationQueue(page int, limit int) (result []models.QuoteModel, total int64, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.D{{Key: "status", Value: models.QuoteStatusPending}, notDeleted}
+	total, err = r.db.Collection(r.collection).CountDocuments(ctx, filter)
+	if err != nil {
+		return result, 0, err
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "create_date", Value: 1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+	cursor, err := r.db.Collection(r.collection).Find(ctx, filter, opts)
+	if err != nil {
+		return result, 0, err
+	}
+	if err = cursor.All(ctx, &result); err != nil {
+		return result, 0, err
+	}
+	return result, total, nil
+}
+
+// GetTrash lists deleted quotes, most recently deleted first.
+func (r *QuoteRepo) GetTrash(page int, limit int) (result []models.QuoteModel, total int64, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.D{{Key: "deleted_at", Value: bson.D{{Key: "$ne", Value: nil}}}}
+	total, err = r.db.Collection(r.collection).CountDocuments(ctx, filter)
+	if err != nil {
+		return result, 0, err
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "deleted_at", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+	cursor, err := r.db.Collection(r.collection).Find(ctx, filter, opts)
+	if err != nil {
+		return result, 0, err
+	}
+	if err = cursor.All(ctx, &result); err != nil {
+		return result, 0, err
+	}
+	return result, total, nil
+}
+
+func (r *QuoteRepo) GetQuotesDeletedBefore(cutoff time.Time) (result []models.QuoteModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.D{{Key: "deleted_at", Value: bson.D{{Key: "$ne", Value: nil}, {Key: "$lte", Value: cutoff}}}}
+	cursor, err := r.db.Collection(r.collection).Find(ctx, filter)
+	if err != nil {
+		return result, err
+	}
+	if err = cursor.All(ctx, &result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// GetQuotesUpdatedSince returns every quote written since then in any status,
+// quotes in the trash included, oldest change first.
+func (r *QuoteRepo) GetQuotesUpdatedSince(since time.Time) (result []models.QuoteModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.D{{Key: "update_date", Value: bson.D{{Key: "$gte", Value: since}}}}
+	opts := options.Find().SetSort(bson.D{{Key: "update_date", Value: 1}})
+	cursor, err := r.db.Collection(r.collection).Find(ctx, filter, opts)
+	if err != nil {
+		return result, err
+	}
+	if err = cursor.All(ctx, &result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+func (r *QuoteRepo) GetQuotesByCreator(userID string) (result []models.QuoteModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.D{{Key: "created_by", Value: userID}}
+	cursor, err := r.db.Collection(r.collection).Find(ctx, filter)
+	if err != nil {
+		return result, err
+	}
+	if err = cursor.All(ctx, &result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// IncrementVote adds delta to the vote tally atomically, a negative delta never
+// takes the tally below zero. Every translation of the quote shares the tally.
+func (r *QuoteRepo) IncrementVote(id string, delta int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	quote := models.QuoteModel{}
+	opts := options.FindOne().SetProjection(bson.D{{Key: "group_id", Value: 1}})
+	if err := r.db.Collection(r.collection).FindOne(ctx, bson.D{{Key: "id", Value: id}}, opts).Decode(&quote); err != nil {
+		return err
+	}
 	filter := bson.D{{Key: "id", Value: id}}
-	_, err := r.db.Collection(r.collection).DeleteOne(ctx, filter)
+	if quote.GroupID != "" {
+		filter = bson.D{{Key: "group_id", Value: quote.GroupID}}
+	}
+	if delta < 0 {
+		filter = append(filter, bson.E{Key: "vote", Value: bson.D{{Key: "$gte", Value: -delta}}})
+	}
+	update := bson.D{
+		{Key: "$inc", Value: bson.D{{Key: "vote", Value: delta}}},
+		{Key: "$set", Value: bson.D{{Key: "update_date", Value: time.Now()}}},
+	}
+	_, err := r.db.Collection(r.collection).UpdateMany(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetGroup returns the live quotes of a translation group, oldest first.
+func (r *QuoteRepo) GetGroup(groupID string) (result []models.QuoteModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.D{{Key: "group_id", Value: groupID}, notDeleted}
+	opts := options.Find().SetSort(bson.D{{Key: "create_date", Value: 1}, {Key: "id", Value: 1}})
+	cursor, err := r.db.Collection(r.collection).Find(ctx, filter, opts)
+	if err != nil {
+		return result, err
+	}
+	if err = cursor.All(ctx, &result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// GetGroups returns the approved quotes of the given translation groups.
+func (r *QuoteRepo) GetGroups(groupIDs []string) (result []models.QuoteModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.D{
+		{Key: "group_id", Value: bson.D{{Key: "$in", Value: groupIDs}}},
+		{Key: "status", Value: models.QuoteStatusApproved},
+		notDeleted,
+	}
+	cursor, err := r.db.Collection(r.collection).Find(ctx, filter)
+	if err != nil {
+		return result, err
+	}
+	if err = cursor.All(ctx, &result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// SetGroup moves quotes into a translation group and gives them its vote
+// tally, an empty groupID takes them out of their group.
+func (r *QuoteRepo) SetGroup(ids []string, groupID string, vote int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	set := bson.D{{Key: "vote", Value: vote}}
+	update := bson.D{{Key: "$set", Value: append(set, bson.E{Key: "group_id", Value: groupID})}}
+	if groupID == "" {
+		update = bson.D{{Key: "$set", Value: set}, {Key: "$unset", Value: bson.D{{Key: "group_id", Value: ""}}}}
+	}
+	filter := bson.D{{Key: "id", Value: bson.D{{Key: "$in", Value: ids}}}}
+	_, err := r.db.Collection(r.collection).UpdateMany(ctx, filter, update)
+	return err
+}
+
+// IncrementFavoriteCount works like IncrementVote on a single quote but leaves update_date alone,
+// saving a quote is not an edit.
+func (r *QuoteRepo) IncrementFavoriteCount(id string, delta int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.D{{Key: "id", Value: id}}
+	if delta < 0 {
+		filter = append(filter, bson.E{Key: "favorite_count", Value: bson.D{{Key: "$gte", Value: -delta}}})
+	}
+	update := bson.D{{Key: "$inc", Value: bson.D{{Key: "favorite_count", Value: delta}}}}
+	_, err := r.db.Collection(r.collection).UpdateOne(ctx, filter, update)
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+func (r *QuoteRepo) IncrementCommentCount(id string, delta int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.D{{Key: "id", Value: id}}
+	if delta < 0 {
+		filter = append(filter, bson.E{Key: "comment_count", Value: bson.D{{Key: "$gte", Value: -delta}}})
+	}
+	update := bson.D{{Key: "$inc", Value: bson.D{{Key: "comment_count", Value: delta}}}}
+	_, err := r.db.Collection(r.collection).UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// IncrementReaction adds delta to the count of one emoji on a quote, a count
+// that drops to zero is removed.
+func (r *QuoteRepo) IncrementReaction(id string, emoji string, delta int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	key := "reactions." + emoji
+	filter := bson.D{{Key: "id", Value: id}}
+	if delta < 0 {
+		filter = append(filter, bson.E{Key: key, Value: bson.D{{Key: "$gte", Value: -delta}}})
+	}
+	update := bson.D{{Key: "$inc", Value: bson.D{{Key: key, Value: delta}}}}
+	if _, err := r.db.Collection(r.collection).UpdateOne(ctx, filter, update); err != nil {
+		return err
+	}
+	if delta < 0 {
+		filter = bson.D{{Key: "id", Value: id}, {Key: key, Value: 0}}
+		update = bson.D{{Key: "$unset", Value: bson.D{{Key: key, Value: ""}}}}
+		if _, err := r.db.Collection(r.collection).UpdateOne(ctx, filter, update); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *QuoteRepo) ResetVote(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.D{{Key: "id", Value: id}}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "vote", Value: 0},
+		{Key: "update_date", Value: time.Now()},
+	}}}
+	_, err := r.db.Collection(r.collection).UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// ClearCreator detaches every quote from userID, the quotes themselves stay.
+func (r *QuoteRepo) ClearCreator(userID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.D{{Key: "created_by", Value: userID}}
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "created_by", Value: ""}}}}
+	_, err := r.db.Collection(r.collection).UpdateMany(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// ReplaceTag swaps oldName for newName on every quote in a single update, a
+// quote that already has newName keeps it only once.
+func (r *QuoteRepo) ReplaceTag(oldName string, newName string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.D{{Key: "tags", Value: oldName}}
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.D{{Key: "tags", Value: bson.D{{Key: "$setUnion", Value: bson.A{
+			bson.D{{Key: "$setDifference", Value: bson.A{"$tags", bson.A{oldName}}}},
+			bson.A{newName},
+		}}}}}}},
+	}
+	_, err := r.db.Collection(r.collection).UpdateMany(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetRandomQuotes samples up to size quotes matching filter inside the
+// database. $sample can return a quote twice, callers dedupe.
+func (r *QuoteRepo) GetRandomQuotes(filter models.QuoteFilterModel, size int) (result []models.QuoteModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	pipeline := append(quotePipeline(filter), bson.D{{Key: "$sample", Value: bson.D{{Key: "size", Value: size}}}})
+	cursor, err := r.db.Collection(r.collection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return result, err
+	}
+	if err = cursor.All(ctx, &result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+func (r *QuoteRepo) CountByTag() (result map[string]int64, err error) {
+	return r.countBy("$tags", true)
+}
+
+func (r *QuoteRepo) CountByCategory() (result map[string]int64, err error) {
+	return r.countBy("$category_id", false)
+}
+
+func (r *QuoteRepo) countBy(field string, unwind bool) (result map[string]int64, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	pipeline := mongo.Pipeline{bson.D{{Key: "$match", Value: bson.D{notDeleted, {Key: "status", Value: models.QuoteStatusApproved}}}}}
+	if unwind {
+		pipeline = append(pipeline, bson.D{{Key: "$unwind", Value: field}})
+	}
+	pipeline = append(pipeline, bson.D{{Key: "$group", Value: bson.D{
+		{Key: "_id", Value: field},
+		{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+	}}})
+	cursor, err := r.db.Collection(r.collection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return result, err
+	}
+	rows := []struct {
+		ID    string `bson:"_id"`
+		Count int64  `bson:"count"`
+	}{}
+	if err = cursor.All(ctx, &rows); err != nil {
+		return result, err
+	}
+	result = map[string]int64{}
+	for _, row := range rows {
+		result[row.ID] = row.Count
+	}
+	return result, nil
+}
+
+// SetAuthorName keeps the author name copied onto quotes in step with the authors collection.
+func (r *QuoteRepo) SetAuthorName(authorID string, name string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.D{{Key: "author_id", Value: authorID}}
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "author", Value: name}}}}
+	_, err := r.db.Collection(r.collection).UpdateMany(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// quoteDefaults are the values a quote stored before a field existed gets on migration.
+var quoteDefaults = bson.D{
+	{Key: "created_by", Value: ""},
+	{Key: "tags", Value: bson.A{}},
+	{Key: "category_id", Value: ""},
+	{Key: "author_id", Value: ""},
+	{Key: "author", Value: ""},
+	{Key: "source", Value: models.QuoteSourceModel{}},
+	{Key: "language", Value: ""},
+	{Key: "year", Value: 0},
+	// quotes from before moderation were already public
+	{Key: "status", Value: models.QuoteStatusApproved},
+}
+
+// MigrateQuotes fills fields missing on older quotes with their empty value,
+// running it again is a no-op.
+func (r *QuoteRepo) MigrateQuotes() (migrated int64, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	for _, field := range quoteDefaults {
+		filter := bson.D{{Key: field.Key, Value: bson.D{{Key: "$exists", Value: false}}}}
+		update := bson.D{{Key: "$set", Value: bson.D{field}}}
+		res, err := r.db.Collection(r.collection).UpdateMany(ctx, filter, update)
+		if err != nil {
+			return migrated, err
+		}
+		migrated += res.ModifiedCount
+	}
+	return migrated, nil
+}
@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"backend/core/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// assertUniqueKeys fails when a document repeats a key at any depth, the
+// server would silently keep only one of them.
+func assertUniqueKeys(t *testing.T, doc bson.D) {
+	seen := map[string]bool{}
+	for _, e := range doc {
+		assert.False(t, seen[e.Key], "duplicate key %s", e.Key)
+		seen[e.Key] = true
+		assertNestedUniqueKeys(t, e.Value)
+	}
+}
+
+func assertNestedUniqueKeys(t *testing.T, value interface{}) {
+	switch v := value.(type) {
+	case bson.D:
+		assertUniqueKeys(t, v)
+	case bson.A:
+		for _, item := range v {
+			assertNestedUniqueKeys(t, item)
+		}
+	}
+}
+
+func Test_QuotePageFilter(t *testing.T) {
+	after := &models.QuoteCursorModel{ID: "q1", Vote: 3, Date: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)}
+	cases := []struct {
+		Name   string
+		Filter models.QuoteFilterModel
+		And    int
+	}{
+		{Name: "groups", Filter: models.QuoteFilterModel{}, And: 0},
+		{Name: "groups after a cursor", Filter: models.QuoteFilterModel{After: after}, And: 1},
+		{Name: "translations after a cursor", Filter: models.QuoteFilterModel{Translations: true, After: after}, And: 1},
+		{Name: "translations", Filter: models.QuoteFilterModel{Translations: true}, And: 0},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			match := quotePageFilter(c.Filter, "vote", "$lt")
+			assertUniqueKeys(t, match)
+			and := bson.A{}
+			for _, e := range match {
+				assert.NotEqual(t, "$or", e.Key)
+				if e.Key == "$and" {
+					and = e.Value.(bson.A)
+				}
+			}
+			assert.Len(t, and, c.And)
+		})
+	}
+}
+
+func Test_QuotePipeline(t *testing.T) {
+	cases := []struct {
+		Name   string
+		Filter models.QuoteFilterModel
+		Stages []string
+	}{
+		{Name: "one quote per group", Filter: models.QuoteFilterModel{}, Stages: []string{"$match", "$sort", "$group", "$replaceRoot"}},
+		{Name: "translations", Filter: models.QuoteFilterModel{Translations: true}, Stages: []string{"$match"}},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			pipeline := quotePipeline(c.Filter)
+			stages := []string{}
+			for _, stage := range pipeline {
+				assertUniqueKeys(t, stage)
+				stages = append(stages, stage[0].Key)
+			}
+			assert.Equal(t, c.Stages, stages)
+			// the group is picked among the approved quotes, its root may be pending or hidden
+			match := pipeline[0][0].Value.(bson.D)
+			for _, e := range match {
+				assert.NotContains(t, []string{"$and", "$or", "$expr", "group_id"}, e.Key)
+			}
+			assert.Contains(t, match, bson.E{Key: "status", Value: bson.D{{Key: "$in", Value: []string{models.QuoteStatusApproved}}}})
+		})
+	}
+}
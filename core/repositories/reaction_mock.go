@@ -0,0 +1,40 @@
+package repositories
+
+import (
+	"backend/core/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type reactionRepoMock struct {
+	mock.Mock
+}
+
+func NewReactionRepositoryMock() *reactionRepoMock {
+	return &reactionRepoMock{}
+}
+
+func (m *reactionRepoMock) AddReaction(payload models.CreateReactionModel) (created bool, err error) {
+	args := m.Called(payload)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *reactionRepoMock) RemoveReaction(quoteID string, userID string, emoji string) (removed bool, err error) {
+	args := m.Called(quoteID, userID, emoji)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *reactionRepoMock) GetReactions(quoteID string) (result []models.ReactionModel, err error) {
+	args := m.Called(quoteID)
+	return args.Get(0).([]models.ReactionModel), args.Error(1)
+}
+
+func (m *reactionRepoMock) GetReactionsByUser(userID string) (result []models.ReactionModel, err error) {
+	args := m.Called(userID)
+	return args.Get(0).([]models.ReactionModel), args.Error(1)
+}
+
+func (m *reactionRepoMock) DeleteReactionsByQuote(quoteID string) error {
+	args := m.Called(quoteID)
+	return args.Error(0)
+}
@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"backend/core/models"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ReactionRepository interface {
+	AddReaction(payload models.CreateReactionModel) (created bool, err error)
+
+	RemoveReaction(quoteID string, userID string, emoji string) (removed bool, err error)
+
+	GetReactions(quoteID string) (result []models.ReactionModel, err error)
+
+	GetReactionsByUser(userID string) (result []models.ReactionModel, err error)
+
+	DeleteReactionsByQuote(quoteID string) error
+}
+
+type reactionRepo struct {
+	db         *mongo.Database
+	collection string
+}
+
+func NewReactionRepository(db *mongo.Database, collection string) ReactionRepository {
+	return &reactionRepo{
+		db:         db,
+		collection: collection,
+	}
+}
+
+// AddReaction stores a reaction, created is false when the user already
+// reacted to the quote with the same emoji.
+func (r *reactionRepo) AddReaction(payload models.CreateReactionModel) (created bool, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.D{
+		{Key: "quote_id", Value: payload.QuoteID},
+		{Key: "user_id", Value: payload.UserID},
+		{Key: "emoji", Value: payload.Emoji},
+	}
+	update := bson.D{{Key: "$setOnInsert", Value: payload}}
+	res, err := r.db.Collection(r.collection).UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return false, err
+	}
+	return res.UpsertedCount > 0, nil
+}
+
+func (r *reactionRepo) RemoveReaction(quoteID string, userID string, emoji string) (removed bool, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.D{
+		{Key: "quote_id", Value: quoteID},
+		{Key: "user_id", Value: userID},
+		{Key: "emoji", Value: emoji},
+	}
+	res, err := r.db.Collection(r.collection).DeleteOne(ctx, filter)
+	if err != nil {
+		return false, err
+	}
+	return res.DeletedCount > 0, nil
+}
+
+func (r *reactionRepo) GetReactions(quoteID string) (result []models.ReactionModel, err error) {
+	return r.find(bson.D{{Key: "quote_id", Value: quoteID}})
+}
+
+func (r *reactionRepo) GetReactionsByUser(userID string) (result []models.ReactionModel, err error) {
+	return r.find(bson.D{{Key: "user_id", Value: userID}})
+}
+
+func (r *reactionRepo) find(filter bson.D) (result []models.ReactionModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "create_date", Value: 1}})
+	cursor, err := r.db.Collection(r.collection).Find(ctx, filter, opts)
+	if err != nil {
+		return result, err
+	}
+	if err = cursor.All(ctx, &result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+func (r *reactionRepo) DeleteReactionsByQuote(quoteID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := r.db.Collection(r.collection).DeleteMany(ctx, bson.D{{Key: "quote_id", Value: quoteID}})
+	return err
+}
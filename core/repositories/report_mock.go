@@ -0,0 +1,35 @@
+package repositories
+
+import (
+	"backend/core/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type reportRepoMock struct {
+	mock.Mock
+}
+
+func NewReportRepositoryMock() *reportRepoMock {
+	return &reportRepoMock{}
+}
+
+func (m *reportRepoMock) CreateReport(payload models.CreateReportModel) (created bool, err error) {
+	args := m.Called(payload)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *reportRepoMock) CountOpenReports(quoteID string) (count int64, err error) {
+	args := m.Called(quoteID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *reportRepoMock) GetReportGroups(page int, limit int) (result []models.ReportGroupModel, total int64, err error) {
+	args := m.Called(page, limit)
+	return args.Get(0).([]models.ReportGroupModel), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *reportRepoMock) ResolveReports(quoteID string, status string, moderatorID string) (count int64, err error) {
+	args := m.Called(quoteID, status, moderatorID)
+	return args.Get(0).(int64), args.Error(1)
+}
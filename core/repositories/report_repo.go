@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"backend/core/models"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ReportRepository interface {
+	CreateReport(payload models.CreateReportModel) (created bool, err error)
+
+	CountOpenReports(quoteID string) (count int64, err error)
+
+	GetReportGroups(page int, limit int) (result []models.ReportGroupModel, total int64, err error)
+
+	ResolveReports(quoteID string, status string, moderatorID string) (count int64, err error)
+}
+
+type reportRepo struct {
+	db         *mongo.Database
+	collection string
+}
+
+func NewReportRepository(db *mongo.Database, collection string) ReportRepository {
+	return &reportRepo{
+		db:         db,
+		collection: collection,
+	}
+}
+
+// CreateReport stores a report unless the user already has an open report on
+// the quote, created is false for the duplicate.
+func (r *reportRepo) CreateReport(payload models.CreateReportModel) (created bool, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.D{
+		{Key: "quote_id", Value: payload.QuoteID},
+		{Key: "user_id", Value: payload.UserID},
+		{Key: "status", Value: models.ReportStatusOpen},
+	}
+	update := bson.D{{Key: "$setOnInsert", Value: payload}}
+	res, err := r.db.Collection(r.collection).UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return false, err
+	}
+	return res.UpsertedCount > 0, nil
+}
+
+func (r *reportRepo) CountOpenReports(quoteID string) (count int64, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	filter := bson.D{{Key: "quote_id", Value: quoteID}, {Key: "status", Value: models.ReportStatusOpen}}
+	return r.db.Collection(r.collection).CountDocuments(ctx, filter)
+}
+
+// GetReportGroups groups the open reports by quote, most reported first.
+func (r *reportRepo) GetReportGroups(page int, limit int) (result []models.ReportGroupModel, total int64, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{{Key: "status", Value: models.ReportStatusOpen}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "create_date", Value: 1}}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$quote_id"},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+			{Key: "last_reported", Value: bson.D{{Key: "$max", Value: "$create_date"}}},
+			{Key: "reports", Value: bson.D{{Key: "$push", Value: "$$ROOT"}}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "last_reported", Value: -1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$facet", Value: bson.D{
+			{Key: "items", Value: bson.A{
+				bson.D{{Key: "$skip", Value: int64((page - 1) * limit)}},
+				bson.D{{Key: "$limit", Value: int64(limit)}},
+			}},
+			{Key: "total", Value: bson.A{bson.D{{Key: "$count", Value: "n"}}}},
+		}}},
+	}
+	cursor, err := r.db.Collection(r.collection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return result, 0, err
+	}
+	var facets []struct {
+		Items []models.ReportGroupModel `bson:"items"`
+		Total []struct {
+			N int64 `bson:"n"`
+		} `bson:"total"`
+	}
+	if err = cursor.All(ctx, &facets); err != nil {
+		return result, 0, err
+	}
+	if len(facets) == 0 {
+		return result, 0, nil
+	}
+	if len(facets[0].Total) > 0 {
+		total = facets[0].Total[0].N
+	}
+	return facets[0].Items, total, nil
+}
+
+// ResolveReports closes every open report of a quote.
+func (r *reportRepo) ResolveReports(quoteID string, status string, moderatorID string) (count int64, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.D{{Key: "quote_id", Value: quoteID}, {Key: "status", Value: models.ReportStatusOpen}}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "status", Value: status},
+		{Key: "resolved_by", Value: moderatorID},
+		{Key: "resolved_at", Value: time.Now()},
+	}}}
+	res, err := r.db.Collection(r.collection).UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
@@ -0,0 +1,35 @@
+package repositories
+
+import (
+	"backend/core/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type revisionRepoMock struct {
+	mock.Mock
+}
+
+func NewRevisionRepositoryMock() *revisionRepoMock {
+	return &revisionRepoMock{}
+}
+
+func (m *revisionRepoMock) GetRevisions(quoteID string) (result []models.RevisionModel, err error) {
+	args := m.Called(quoteID)
+	return args.Get(0).([]models.RevisionModel), args.Error(1)
+}
+
+func (m *revisionRepoMock) GetRevision(quoteID string, rev int) (result models.RevisionModel, err error) {
+	args := m.Called(quoteID, rev)
+	return args.Get(0).(models.RevisionModel), args.Error(1)
+}
+
+func (m *revisionRepoMock) CreateRevision(revision models.CreateRevisionModel) error {
+	args := m.Called(revision)
+	return args.Error(0)
+}
+
+func (m *revisionRepoMock) DeleteRevisionsByQuote(quoteID string) error {
+	args := m.Called(quoteID)
+	return args.Error(0)
+}
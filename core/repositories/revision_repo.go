@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"backend/core/models"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type RevisionRepository interface {
+	GetRevisions(quoteID string) (result []models.RevisionModel, err error)
+
+	GetRevision(quoteID string, rev int) (result models.RevisionModel, err error)
+
+	CreateRevision(revision models.CreateRevisionModel) error
+
+	DeleteRevisionsByQuote(quoteID string) error
+}
+
+type revisionRepo struct {
+	db         *mongo.Database
+	collection string
+}
+
+func NewRevisionRepository(db *mongo.Database, collection string) RevisionRepository {
+	return &revisionRepo{
+		db:         db,
+		collection: collection,
+	}
+}
+
+func (r *revisionRepo) GetRevisions(quoteID string) (result []models.RevisionModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	filter := bson.D{{Key: "quote_id", Value: quoteID}}
+	opts := options.Find().SetSort(bson.D{{Key: "rev", Value: 1}})
+	cursor, err := r.db.Collection(r.collection).Find(ctx, filter, opts)
+	if err != nil {
+		return result, err
+	}
+	if err = cursor.All(ctx, &result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+func (r *revisionRepo) GetRevision(quoteID string, rev int) (result models.RevisionModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	filter := bson.D{{Key: "quote_id", Value: quoteID}, {Key: "rev", Value: rev}}
+	err = r.db.Collection(r.collection).FindOne(ctx, filter).Decode(&result)
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+func (r *revisionRepo) CreateRevision(revision models.CreateRevisionModel) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := r.db.Collection(r.collection).InsertOne(ctx, revision)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *revisionRepo) DeleteRevisionsByQuote(quoteID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	filter := bson.D{{Key: "quote_id", Value: quoteID}}
+	_, err := r.db.Collection(r.collection).DeleteMany(ctx, filter)
+	if err != nil {
+		return err
+	}
+	return nil
+}
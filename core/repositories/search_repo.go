@@ -0,0 +1,209 @@
+package repositories
+
+import (
+	"backend/core/models"
+	"backend/utils"
+	"html"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+type SearchRepository interface {
+	Index(id string, text string) error
+
+	Remove(id string) error
+
+	Search(query string, limit int) (result []models.SearchHitModel, err error)
+}
+
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+
+	highlightBefore = 30
+	highlightAfter  = 90
+	highlightOpen   = "<mark>"
+	highlightClose  = "</mark>"
+)
+
+type searchDoc struct {
+	text   string
+	tokens []utils.Token
+	// terms holds every indexed term of a token, Thai compounds add their subwords
+	terms  [][]string
+	length int
+}
+
+type memorySearchRepo struct {
+	mu       sync.RWMutex
+	docs     map[string]searchDoc
+	postings map[string]map[string]int
+	total    int
+}
+
+// NewMemorySearchRepository keeps a BM25 inverted index in process memory,
+// it has to be filled with Index on startup.
+func NewMemorySearchRepository() SearchRepository {
+	return &memorySearchRepo{
+		docs:     map[string]searchDoc{},
+		postings: map[string]map[string]int{},
+	}
+}
+
+func (r *memorySearchRepo) Index(id string, text string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.remove(id)
+	doc := searchDoc{
+		text:   text,
+		tokens: utils.Tokenize(text),
+	}
+	for _, token := range doc.tokens {
+		terms := []string{token.Text}
+		if utils.IsThai([]rune(token.Text)[0]) {
+			terms = append(terms, utils.SubwordsThai(token.Text)...)
+		}
+		doc.terms = append(doc.terms, terms)
+		for _, term := range terms {
+			if r.postings[term] == nil {
+				r.postings[term] = map[string]int{}
+			}
+			r.postings[term][id]++
+		}
+	}
+	doc.length = len(doc.tokens)
+	r.docs[id] = doc
+	r.total += doc.length
+	return nil
+}
+
+func (r *memorySearchRepo) Remove(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.remove(id)
+	return nil
+}
+
+func (r *memorySearchRepo) remove(id string) {
+	doc, ok := r.docs[id]
+	if !ok {
+		return
+	}
+	for _, terms := range doc.terms {
+		for _, term := range terms {
+			delete(r.postings[term], id)
+			if len(r.postings[term]) == 0 {
+				delete(r.postings, term)
+			}
+		}
+	}
+	r.total -= doc.length
+	delete(r.docs, id)
+}
+
+func (r *memorySearchRepo) Search(query string, limit int) (result []models.SearchHitModel, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	terms := map[string]bool{}
+	for _, token := range utils.Tokenize(query) {
+		terms[token.Text] = true
+	}
+	if len(terms) == 0 || len(r.docs) == 0 {
+		return []models.SearchHitModel{}, nil
+	}
+
+	n := float64(len(r.docs))
+	avg := float64(r.total) / n
+	scores := map[string]float64{}
+	for term := range terms {
+		postings := r.postings[term]
+		if len(postings) == 0 {
+			continue
+		}
+		df := float64(len(postings))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		for id, tf := range postings {
+			length := float64(r.docs[id].length)
+			f := float64(tf)
+			scores[id] += idf * f * (bm25K1 + 1) / (f + bm25K1*(1-bm25B+bm25B*length/avg))
+		}
+	}
+
+	result = []models.SearchHitModel{}
+	for id, score := range scores {
+		result = append(result, models.SearchHitModel{ID: id, Score: score})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Score != result[j].Score {
+			return result[i].Score > result[j].Score
+		}
+		return result[i].ID < result[j].ID
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	for i := range result {
+		result[i].Highlight = highlight(r.docs[result[i].ID], terms)
+	}
+	return result, nil
+}
+
+// highlight wraps the matched tokens in <mark> and trims the text to a
+// fragment around the first match. The text is HTML escaped, only the marks
+// are markup.
+func highlight(doc searchDoc, terms map[string]bool) string {
+	runes := []rune(doc.text)
+	spans := [][2]int{}
+	for i, token := range doc.tokens {
+		for _, term := range doc.terms[i] {
+			if terms[term] {
+				if len(spans) > 0 && spans[len(spans)-1][1] >= token.Start-1 && !hasLetter(runes[spans[len(spans)-1][1]:token.Start]) {
+					spans[len(spans)-1][1] = token.End
+				} else {
+					spans = append(spans, [2]int{token.Start, token.End})
+				}
+				break
+			}
+		}
+	}
+	if len(spans) == 0 {
+		return ""
+	}
+
+	from := max(spans[0][0]-highlightBefore, 0)
+	to := min(spans[0][0]+highlightAfter, len(runes))
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+	pos := from
+	for _, span := range spans {
+		if span[0] >= to {
+			break
+		}
+		b.WriteString(html.EscapeString(string(runes[pos:span[0]])))
+		end := min(span[1], to)
+		b.WriteString(highlightOpen + html.EscapeString(string(runes[span[0]:end])) + highlightClose)
+		pos = end
+	}
+	b.WriteString(html.EscapeString(string(runes[pos:to])))
+	if to < len(runes) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+func hasLetter(runes []rune) bool {
+	for _, r := range runes {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return true
+		}
+	}
+	return false
+}
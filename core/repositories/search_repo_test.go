@@ -0,0 +1,147 @@
+package repositories
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func searchIDs(t *testing.T, repo SearchRepository, query string) []string {
+	hits, err := repo.Search(query, 0)
+	assert.NoError(t, err)
+	ids := []string{}
+	for _, hit := range hits {
+		ids = append(ids, hit.ID)
+	}
+	return ids
+}
+
+func Test_SearchRanking(t *testing.T) {
+	repo := NewMemorySearchRepository()
+	docs := map[string]string{
+		"often":  "Love, love, love is all you need.",
+		"once":   "Love is all you need.",
+		"long":   "All you need is love and a long walk on a quiet morning by the sea.",
+		"other":  "Stay hungry, stay foolish.",
+		"thai":   "ความรักคือการให้",
+		"thai2":  "ความสุขอยู่ที่ใจ",
+		"thai3":  "รักคือการให้โดยไม่หวังผล",
+		"rarely": "Hope is a good thing.",
+	}
+	for id, text := range docs {
+		assert.NoError(t, repo.Index(id, text))
+	}
+
+	cases := []struct {
+		Name     string
+		Query    string
+		Expected []string
+	}{
+		{
+			Name:     "more occurrences rank higher",
+			Query:    "love",
+			Expected: []string{"often", "once", "long"},
+		},
+		{
+			Name:     "a rare term outweighs a common one",
+			Query:    "hope is",
+			Expected: []string{"rarely", "once", "often", "long"},
+		},
+		{
+			Name:     "query is case insensitive",
+			Query:    "FOOLISH",
+			Expected: []string{"other"},
+		},
+		{
+			Name:     "thai word inside a compound, the shorter quote first",
+			Query:    "รัก",
+			Expected: []string{"thai", "thai3"},
+		},
+		{
+			Name:     "thai compound",
+			Query:    "ความสุข",
+			Expected: []string{"thai2"},
+		},
+		{
+			Name:     "no match",
+			Query:    "xyz",
+			Expected: []string{},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			assert.Equal(t, c.Expected, searchIDs(t, repo, c.Query))
+		})
+	}
+}
+
+func Test_SearchRemove(t *testing.T) {
+	repo := NewMemorySearchRepository()
+	assert.NoError(t, repo.Index("1", "Love is all you need."))
+	assert.NoError(t, repo.Index("2", "Stay hungry, stay foolish."))
+	assert.NoError(t, repo.Index("2", "Love wins."))
+	assert.Equal(t, []string{"2", "1"}, searchIDs(t, repo, "love"))
+
+	assert.NoError(t, repo.Remove("2"))
+	assert.Equal(t, []string{"1"}, searchIDs(t, repo, "love"))
+	assert.Equal(t, []string{}, searchIDs(t, repo, "hungry"))
+}
+
+func Test_SearchHighlight(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Text     string
+		Query    string
+		Expected string
+	}{
+		{
+			Name:     "match is marked",
+			Text:     "Love is all you need.",
+			Query:    "need",
+			Expected: "Love is all you <mark>need</mark>.",
+		},
+		{
+			Name:     "adjacent matches share one mark",
+			Text:     "Love is all you need.",
+			Query:    "you all",
+			Expected: "Love is <mark>all you</mark> need.",
+		},
+		{
+			Name:     "markup in the text is escaped",
+			Text:     `<script>alert("love")</script> & love`,
+			Query:    "love",
+			Expected: `&lt;script&gt;alert(&#34;<mark>love</mark>&#34;)&lt;/script&gt; &amp; <mark>love</mark>`,
+		},
+		{
+			Name:     "markup in the query is not echoed",
+			Text:     "Love is all you need.",
+			Query:    "<mark>love</mark>",
+			Expected: "<mark>Love</mark> is all you need.",
+		},
+		{
+			Name:     "thai subword is marked as its whole word",
+			Text:     "ความรักคือการให้",
+			Query:    "รัก",
+			Expected: "<mark>ความรัก</mark>คือการให้",
+		},
+		{
+			Name:     "long text is cut around the first match",
+			Text:     "Some words come first and they go on for quite a while before the point, which is that love is all you need in the end, and after that the text still goes on and on for a long time.",
+			Query:    "love",
+			Expected: "…fore the point, which is that <mark>love</mark> is all you need in the end, and after that the text still goes on and on for a long t…",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			repo := NewMemorySearchRepository()
+			assert.NoError(t, repo.Index("1", c.Text))
+			hits, err := repo.Search(c.Query, 1)
+			assert.NoError(t, err)
+			if assert.Len(t, hits, 1) {
+				assert.Equal(t, c.Expected, hits[0].Highlight)
+			}
+		})
+	}
+}
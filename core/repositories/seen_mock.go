@@ -0,0 +1,35 @@
+package repositories
+
+import (
+	"backend/core/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type seenRepoMock struct {
+	mock.Mock
+}
+
+func NewSeenRepositoryMock() *seenRepoMock {
+	return &seenRepoMock{}
+}
+
+func (m *seenRepoMock) AddSeen(userID string, quoteIDs []string) error {
+	args := m.Called(userID, quoteIDs)
+	return args.Error(0)
+}
+
+func (m *seenRepoMock) GetSeenIDs(userID string, limit int) (result []string, err error) {
+	args := m.Called(userID, limit)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *seenRepoMock) GetSeenByUser(userID string) (result []models.SeenQuoteModel, err error) {
+	args := m.Called(userID)
+	return args.Get(0).([]models.SeenQuoteModel), args.Error(1)
+}
+
+func (m *seenRepoMock) DeleteSeenByUser(userID string) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"backend/core/models"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type SeenRepository interface {
+	AddSeen(userID string, quoteIDs []string) error
+
+	GetSeenIDs(userID string, limit int) (result []string, err error)
+
+	GetSeenByUser(userID string) (result []models.SeenQuoteModel, err error)
+
+	DeleteSeenByUser(userID string) error
+}
+
+type seenRepo struct {
+	db         *mongo.Database
+	collection string
+}
+
+func NewSeenRepository(db *mongo.Database, collection string) SeenRepository {
+	return &seenRepo{
+		db:         db,
+		collection: collection,
+	}
+}
+
+// AddSeen marks quotes as seen by a user now, quotes seen before move to the front.
+func (r *seenRepo) AddSeen(userID string, quoteIDs []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if len(quoteIDs) == 0 {
+		return nil
+	}
+	now := time.Now()
+	writes := []mongo.WriteModel{}
+	for _, quoteID := range quoteIDs {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.D{{Key: "user_id", Value: userID}, {Key: "quote_id", Value: quoteID}}).
+			SetUpdate(bson.D{{Key: "$set", Value: bson.D{{Key: "seen_date", Value: now}}}}).
+			SetUpsert(true))
+	}
+	_, err := r.db.Collection(r.collection).BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+// GetSeenIDs returns the ids of the quotes a user saw most recently.
+func (r *seenRepo) GetSeenIDs(userID string, limit int) (result []string, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.D{{Key: "user_id", Value: userID}}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "seen_date", Value: -1}}).
+		SetLimit(int64(limit)).
+		SetProjection(bson.D{{Key: "quote_id", Value: 1}})
+	cursor, err := r.db.Collection(r.collection).Find(ctx, filter, opts)
+	if err != nil {
+		return result, err
+	}
+	seen := []models.SeenQuoteModel{}
+	if err = cursor.All(ctx, &seen); err != nil {
+		return result, err
+	}
+	for _, s := range seen {
+		result = append(result, s.QuoteID)
+	}
+	return result, nil
+}
+
+func (r *seenRepo) GetSeenByUser(userID string) (result []models.SeenQuoteModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.D{{Key: "user_id", Value: userID}}
+	opts := options.Find().SetSort(bson.D{{Key: "seen_date", Value: 1}})
+	cursor, err := r.db.Collection(r.collection).Find(ctx, filter, opts)
+	if err != nil {
+		return result, err
+	}
+	if err = cursor.All(ctx, &result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+func (r *seenRepo) DeleteSeenByUser(userID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := r.db.Collection(r.collection).DeleteMany(ctx, bson.D{{Key: "user_id", Value: userID}})
+	return err
+}
@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"backend/core/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type tagRepoMock struct {
+	mock.Mock
+}
+
+func NewTagRepositoryMock() *tagRepoMock {
+	return &tagRepoMock{}
+}
+
+func (m *tagRepoMock) GetTags() (result []models.TagModel, err error) {
+	args := m.Called()
+	return args.Get(0).([]models.TagModel), args.Error(1)
+}
+
+func (m *tagRepoMock) GetTag(id string) (result models.TagModel, err error) {
+	args := m.Called(id)
+	return args.Get(0).(models.TagModel), args.Error(1)
+}
+
+func (m *tagRepoMock) GetTagByName(name string) (result models.TagModel, err error) {
+	args := m.Called(name)
+	return args.Get(0).(models.TagModel), args.Error(1)
+}
+
+func (m *tagRepoMock) CreateTag(tag models.CreateTagModel) (result models.TagModel, err error) {
+	args := m.Called(tag)
+	return args.Get(0).(models.TagModel), args.Error(1)
+}
+
+func (m *tagRepoMock) RenameTag(id string, name string) (result models.TagModel, err error) {
+	args := m.Called(id, name)
+	return args.Get(0).(models.TagModel), args.Error(1)
+}
+
+func (m *tagRepoMock) DeleteTag(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *tagRepoMock) EnsureIndexes() error {
+	args := m.Called()
+	return args.Error(0)
+}
@@ -0,0 +1,131 @@
+package repositories
+
+import (
+	"backend/core/models"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type TagRepository interface {
+	GetTags() (result []models.TagModel, err error)
+
+	GetTag(id string) (result models.TagModel, err error)
+
+	GetTagByName(name string) (result models.TagModel, err error)
+
+	CreateTag(tag models.CreateTagModel) (result models.TagModel, err error)
+
+	RenameTag(id string, name string) (result models.TagModel, err error)
+
+	DeleteTag(id string) error
+
+	EnsureIndexes() error
+}
+
+type tagRepo struct {
+	db         *mongo.Database
+	collection string
+}
+
+func NewTagRepository(db *mongo.Database, collection string) TagRepository {
+	return &tagRepo{
+		db:         db,
+		collection: collection,
+	}
+}
+
+func (r *tagRepo) GetTags() (result []models.TagModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := r.db.Collection(r.collection).Find(ctx, bson.D{}, opts)
+	if err != nil {
+		return result, err
+	}
+	if err = cursor.All(ctx, &result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+func (r *tagRepo) GetTag(id string) (result models.TagModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	filter := bson.D{{Key: "id", Value: id}}
+	err = r.db.Collection(r.collection).FindOne(ctx, filter).Decode(&result)
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+func (r *tagRepo) GetTagByName(name string) (result models.TagModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	filter := bson.D{{Key: "name", Value: name}}
+	err = r.db.Collection(r.collection).FindOne(ctx, filter).Decode(&result)
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+func (r *tagRepo) CreateTag(tag models.CreateTagModel) (result models.TagModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err = r.db.Collection(r.collection).InsertOne(ctx, tag)
+	if err != nil {
+		return result, err
+	}
+	err = r.db.Collection(r.collection).FindOne(ctx, bson.D{{Key: "id", Value: tag.ID}}).Decode(&result)
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+func (r *tagRepo) RenameTag(id string, name string) (result models.TagModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	filter := bson.D{{Key: "id", Value: id}}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "name", Value: name},
+		{Key: "update_date", Value: time.Now()},
+	}}}
+	_, err = r.db.Collection(r.collection).UpdateOne(ctx, filter, update)
+	if err != nil {
+		return result, err
+	}
+	err = r.db.Collection(r.collection).FindOne(ctx, filter).Decode(&result)
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+func (r *tagRepo) DeleteTag(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	filter := bson.D{{Key: "id", Value: id}}
+	_, err := r.db.Collection(r.collection).DeleteOne(ctx, filter)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// EnsureIndexes makes name unique so two quotes using a new tag at the same
+// time cannot both create it.
+func (r *tagRepo) EnsureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	_, err := r.db.Collection(r.collection).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "name", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
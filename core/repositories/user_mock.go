@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"backend/core/models"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -19,6 +20,11 @@ func (m *userRepoMock) GetUser(email string) (result models.UserModel, err error
 	return args.Get(0).(models.UserModel), args.Error(1)
 }
 
+func (m *userRepoMock) GetUserByID(id string) (result models.UserModel, err error) {
+	args := m.Called(id)
+	return args.Get(0).(models.UserModel), args.Error(1)
+}
+
 func (m *userRepoMock) CreateUser(user models.CreateUserModel) error {
 	args := m.Called(user)
 	return args.Error(0)
@@ -28,3 +34,53 @@ func (m *userRepoMock) UpdateUser(id string, user models.UpdateUserModel) (resul
 	args := m.Called(id, user)
 	return args.Get(0).(models.UserModel), args.Error(1)
 }
+
+func (m *userRepoMock) ScheduleDeleteUser(id string, deleteAt *time.Time) (result models.UserModel, err error) {
+	args := m.Called(id, deleteAt)
+	return args.Get(0).(models.UserModel), args.Error(1)
+}
+
+func (m *userRepoMock) GetUsersDueForDeletion(now time.Time) (result []models.UserModel, err error) {
+	args := m.Called(now)
+	return args.Get(0).([]models.UserModel), args.Error(1)
+}
+
+func (m *userRepoMock) DeleteUser(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *userRepoMock) SearchUsers(search string, page int, limit int) (result []models.UserModel, total int64, err error) {
+	args := m.Called(search, page, limit)
+	return args.Get(0).([]models.UserModel), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *userRepoMock) ClearVotes(quoteID string) (cleared int64, err error) {
+	args := m.Called(quoteID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *userRepoMock) ClearVote(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *userRepoMock) MoveVotes(fromQuoteID string, toQuoteID string) (moved int64, err error) {
+	args := m.Called(fromQuoteID, toQuoteID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *userRepoMock) CountVotes(quoteID string) (total int64, err error) {
+	args := m.Called(quoteID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *userRepoMock) GetUsersByIDs(ids []string) (result []models.UserModel, err error) {
+	args := m.Called(ids)
+	return args.Get(0).([]models.UserModel), args.Error(1)
+}
+
+func (m *userRepoMock) GetVoters(quoteIDs []string) (result []models.UserModel, err error) {
+	args := m.Called(quoteIDs)
+	return args.Get(0).([]models.UserModel), args.Error(1)
+}
@@ -3,18 +3,43 @@ package repositories
 import (
 	"backend/core/models"
 	"context"
+	"regexp"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type UserRepository interface {
 	GetUser(email string) (result models.UserModel, err error)
 
+	GetUserByID(id string) (result models.UserModel, err error)
+
 	CreateUser(user models.CreateUserModel) error
 
 	UpdateUser(id string, user models.UpdateUserModel) (result models.UserModel, err error)
+
+	ScheduleDeleteUser(id string, deleteAt *time.Time) (result models.UserModel, err error)
+
+	GetUsersDueForDeletion(now time.Time) (result []models.UserModel, err error)
+
+	DeleteUser(id string) error
+
+	SearchUsers(search string, page int, limit int) (result []models.UserModel, total int64, err error)
+
+	ClearVotes(quoteID string) (cleared int64, err error)
+
+	ClearVote(id string) error
+
+	MoveVotes(fromQuoteID string, toQuoteID string) (moved int64, err error)
+
+	CountVotes(quoteID string) (total int64, err error)
+
+	GetUsersByIDs(ids []string) (result []models.UserModel, err error)
+
+	GetVoters(quoteIDs []string) (result []models.UserModel, err error)
 }
 type userRepo struct {
 	db         *mongo.Database
@@ -39,6 +64,17 @@ func (r *userRepo) GetUser(email string) (result models.UserModel, err error) {
 	return result, nil
 }
 
+func (r *userRepo) GetUserByID(id string) (result models.UserModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	filter := bson.D{{Key: "id", Value: id}}
+	err = r.db.Collection(r.collection).FindOne(ctx, filter).Decode(&result)
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
 func (r *userRepo) CreateUser(user models.CreateUserModel) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -64,3 +100,161 @@ func (r *userRepo) UpdateUser(id string, user models.UpdateUserModel) (result mo
 
 	return result, nil
 }
+
+// ScheduleDeleteUser sets the time the account will be purged, nil cancels it.
+func (r *userRepo) ScheduleDeleteUser(id string, deleteAt *time.Time) (result models.UserModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	filter := bson.D{{Key: "id", Value: id}}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "delete_at", Value: deleteAt},
+		{Key: "update_date", Value: time.Now()},
+	}}}
+	_, err = r.db.Collection(r.collection).UpdateOne(ctx, filter, update)
+	if err != nil {
+		return result, err
+	}
+	err = r.db.Collection(r.collection).FindOne(ctx, filter).Decode(&result)
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+func (r *userRepo) GetUsersDueForDeletion(now time.Time) (result []models.UserModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	filter := bson.D{{Key: "delete_at", Value: bson.D{{Key: "$ne", Value: nil}, {Key: "$lte", Value: now}}}}
+	cursor, err := r.db.Collection(r.collection).Find(ctx, filter)
+	if err != nil {
+		return result, err
+	}
+	if err = cursor.All(ctx, &result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+func (r *userRepo) DeleteUser(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	filter := bson.D{{Key: "id", Value: id}}
+	_, err := r.db.Collection(r.collection).DeleteOne(ctx, filter)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// SearchUsers matches search case-insensitively against email and display name,
+// page starts at 1.
+func (r *userRepo) SearchUsers(search string, page int, limit int) (result []models.UserModel, total int64, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	filter := bson.D{}
+	if search != "" {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(search), Options: "i"}
+		filter = bson.D{{Key: "$or", Value: bson.A{
+			bson.D{{Key: "email", Value: pattern}},
+			bson.D{{Key: "display_name", Value: pattern}},
+		}}}
+	}
+	total, err = r.db.Collection(r.collection).CountDocuments(ctx, filter)
+	if err != nil {
+		return result, total, err
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "create_date", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+	cursor, err := r.db.Collection(r.collection).Find(ctx, filter, opts)
+	if err != nil {
+		return result, total, err
+	}
+	if err = cursor.All(ctx, &result); err != nil {
+		return result, total, err
+	}
+	return result, total, nil
+}
+
+// CountVotes counts the users whose vote is on quoteID.
+func (r *userRepo) CountVotes(quoteID string) (total int64, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return r.db.Collection(r.collection).CountDocuments(ctx, bson.D{{Key: "quote_id", Value: quoteID}})
+}
+
+// ClearVotes lets every user who voted for quoteID vote again.
+func (r *userRepo) ClearVotes(quoteID string) (cleared int64, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	filter := bson.D{{Key: "quote_id", Value: quoteID}}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "quote_id", Value: ""},
+		{Key: "update_date", Value: time.Now()},
+	}}}
+	res, err := r.db.Collection(r.collection).UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
+
+// ClearVote takes back the vote of a single user.
+func (r *userRepo) ClearVote(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	filter := bson.D{{Key: "id", Value: id}}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "quote_id", Value: ""},
+		{Key: "update_date", Value: time.Now()},
+	}}}
+	_, err := r.db.Collection(r.collection).UpdateOne(ctx, filter, update)
+	return err
+}
+
+// MoveVotes points every vote for fromQuoteID at toQuoteID, used when quotes are merged.
+func (r *userRepo) MoveVotes(fromQuoteID string, toQuoteID string) (moved int64, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	filter := bson.D{{Key: "quote_id", Value: fromQuoteID}}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "quote_id", Value: toQuoteID},
+		{Key: "update_date", Value: time.Now()},
+	}}}
+	res, err := r.db.Collection(r.collection).UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
+
+func (r *userRepo) GetUsersByIDs(ids []string) (result []models.UserModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	filter := bson.D{{Key: "id", Value: bson.D{{Key: "$in", Value: ids}}}}
+	cursor, err := r.db.Collection(r.collection).Find(ctx, filter)
+	if err != nil {
+		return result, err
+	}
+	if err = cursor.All(ctx, &result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// GetVoters returns the users whose vote is on one of quoteIDs.
+func (r *userRepo) GetVoters(quoteIDs []string) (result []models.UserModel, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	filter := bson.D{{Key: "quote_id", Value: bson.D{{Key: "$in", Value: quoteIDs}}}}
+	opts := options.Find().SetSort(bson.D{{Key: "update_date", Value: 1}})
+	cursor, err := r.db.Collection(r.collection).Find(ctx, filter, opts)
+	if err != nil {
+		return result, err
+	}
+	if err = cursor.All(ctx, &result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
@@ -0,0 +1,310 @@
+package services
+
+import (
+	"backend/core/models"
+	"backend/utils"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+type AdminService interface {
+	GetUsers(search string, page int, limit int) (result models.ResponseModel)
+
+	GetUser(id string) (result models.ResponseModel)
+
+	SuspendUser(adminID string, id string, reason string) (result models.ResponseModel)
+
+	UnsuspendUser(adminID string, id string) (result models.ResponseModel)
+
+	UpdateRole(adminID string, id string, role string) (result models.ResponseModel)
+
+	SetTrusted(adminID string, id string, trusted bool) (result models.ResponseModel)
+
+	ForcePasswordReset(adminID string, id string) (result models.ResponseModel)
+
+	DeleteUser(adminID string, id string) (result models.ResponseModel)
+}
+
+type AdminSrv struct {
+	repos AccountRepositories
+}
+
+func NewAdminService(repos AccountRepositories) AdminService {
+	return &AdminSrv{
+		repos: repos,
+	}
+}
+
+func (s *AdminSrv) GetUsers(search string, page int, limit int) (result models.ResponseModel) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultPageLimit
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+	res, total, err := s.repos.User.SearchUsers(search, page, limit)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	users := []models.UserResModel{}
+	for _, user := range res {
+		users = append(users, toUserRes(user))
+	}
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "get users success",
+		Result: models.UserListModel{
+			Users: users,
+			Total: total,
+			Page:  page,
+			Limit: limit,
+		},
+	}
+}
+
+func (s *AdminSrv) GetUser(id string) (result models.ResponseModel) {
+	if id == "" {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "id not found",
+			Result:  nil,
+		}
+	}
+	user, err := s.repos.User.GetUserByID(id)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    404,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	quotes, err := s.repos.Quote.GetQuotesByCreator(id)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if quotes == nil {
+		quotes = []models.QuoteModel{}
+	}
+	detail := models.AdminUserDetailModel{
+		User:   toUserRes(user),
+		Quotes: quotes,
+	}
+	if user.QouteID != "" {
+		if vote, err := s.repos.Quote.GetQuote(user.QouteID); err == nil {
+			detail.Vote = &vote
+		}
+	}
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "get user success",
+		Result:  detail,
+	}
+}
+
+func (s *AdminSrv) SuspendUser(adminID string, id string, reason string) (result models.ResponseModel) {
+	if id == "" {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "id not found",
+			Result:  nil,
+		}
+	}
+	if id == adminID {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "cannot suspend yourself",
+			Result:  nil,
+		}
+	}
+	suspended := true
+	payload := models.UpdateUserModel{
+		Suspended:     &suspended,
+		SuspendReason: &reason,
+		UpdateDate:    time.Now(),
+	}
+	return s.updateUser(adminID, id, payload, "suspend", reason, "suspend user success")
+}
+
+func (s *AdminSrv) UnsuspendUser(adminID string, id string) (result models.ResponseModel) {
+	if id == "" {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "id not found",
+			Result:  nil,
+		}
+	}
+	suspended := false
+	reason := ""
+	payload := models.UpdateUserModel{
+		Suspended:     &suspended,
+		SuspendReason: &reason,
+		UpdateDate:    time.Now(),
+	}
+	return s.updateUser(adminID, id, payload, "unsuspend", "", "unsuspend user success")
+}
+
+func (s *AdminSrv) UpdateRole(adminID string, id string, role string) (result models.ResponseModel) {
+	if id == "" {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "id not found",
+			Result:  nil,
+		}
+	}
+	if !utils.StringInSlice([]string{models.RoleUser, models.RoleModerator, models.RoleAdmin}, role) {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "role invalid",
+			Result:  nil,
+		}
+	}
+	if id == adminID {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "cannot change your own role",
+			Result:  nil,
+		}
+	}
+	payload := models.UpdateUserModel{
+		Role:       role,
+		UpdateDate: time.Now(),
+	}
+	return s.updateUser(adminID, id, payload, "update_role", role, "update role success")
+}
+
+// SetTrusted lets a user's quotes skip the moderation queue.
+func (s *AdminSrv) SetTrusted(adminID string, id string, trusted bool) (result models.ResponseModel) {
+	if id == "" {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "id not found",
+			Result:  nil,
+		}
+	}
+	payload := models.UpdateUserModel{
+		Trusted:    &trusted,
+		UpdateDate: time.Now(),
+	}
+	return s.updateUser(adminID, id, payload, "set_trusted", strconv.FormatBool(trusted), "set trusted success")
+}
+
+func (s *AdminSrv) ForcePasswordReset(adminID string, id string) (result models.ResponseModel) {
+	if id == "" {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "id not found",
+			Result:  nil,
+		}
+	}
+	mustChangePassword := true
+	payload := models.UpdateUserModel{
+		MustChangePassword: &mustChangePassword,
+		UpdateDate:         time.Now(),
+	}
+	return s.updateUser(adminID, id, payload, "force_password_reset", "", "force password reset success")
+}
+
+func (s *AdminSrv) DeleteUser(adminID string, id string) (result models.ResponseModel) {
+	if id == "" {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "id not found",
+			Result:  nil,
+		}
+	}
+	if id == adminID {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "cannot delete yourself",
+			Result:  nil,
+		}
+	}
+	user, err := s.repos.User.GetUserByID(id)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    404,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if err := purgeUser(s.repos, user); err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	writeAudit(s.repos.Audit, adminID, "delete_user", id)
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "delete user success",
+		Result:  nil,
+	}
+}
+
+// updateUser applies payload to an existing user and records the action on
+// the user's audit trail.
+func (s *AdminSrv) updateUser(adminID string, id string, payload models.UpdateUserModel, action string, detail string, message string) (result models.ResponseModel) {
+	if _, err := s.repos.User.GetUserByID(id); err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    404,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	res, err := s.repos.User.UpdateUser(id, payload)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if detail != "" {
+		detail = detail + " "
+	}
+	writeAudit(s.repos.Audit, id, action, detail+"by "+adminID)
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: message,
+		Result:  toUserRes(res),
+	}
+}
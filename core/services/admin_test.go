@@ -0,0 +1,209 @@
+package services_test
+
+import (
+	"backend/core/models"
+	"backend/core/repositories"
+	"backend/core/services"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func Test_GetUsers(t *testing.T) {
+	type test struct {
+		Name  string
+		Input struct {
+			Search string
+			Page   int
+			Limit  int
+		}
+		Expect struct {
+			Page  int
+			Limit int
+		}
+	}
+	cases := []test{
+		{
+			Name: "default paging",
+			Input: struct {
+				Search string
+				Page   int
+				Limit  int
+			}{Search: "test", Page: 0, Limit: 0},
+			Expect: struct {
+				Page  int
+				Limit int
+			}{Page: 1, Limit: 20},
+		},
+		{
+			Name: "limit capped",
+			Input: struct {
+				Search string
+				Page   int
+				Limit  int
+			}{Search: "", Page: 3, Limit: 1000},
+			Expect: struct {
+				Page  int
+				Limit int
+			}{Page: 3, Limit: 100},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			userRepo := repositories.NewUserRepositoryMock()
+			userRepo.On("SearchUsers", c.Input.Search, c.Expect.Page, c.Expect.Limit).Return([]models.UserModel{{ID: "1", Password: "hash"}}, int64(1), nil)
+
+			adminService := services.NewAdminService(accountRepos(services.AccountRepositories{User: userRepo}))
+			result := adminService.GetUsers(c.Input.Search, c.Input.Page, c.Input.Limit)
+
+			assert.Equal(t, models.ResponseModel{
+				Status:  true,
+				Code:    200,
+				Message: "get users success",
+				Result: models.UserListModel{
+					Users: []models.UserResModel{{ID: "1", Role: models.RoleUser}},
+					Total: 1,
+					Page:  c.Expect.Page,
+					Limit: c.Expect.Limit,
+				},
+			}, result)
+		})
+	}
+}
+
+func Test_SuspendUser(t *testing.T) {
+	type test struct {
+		Name  string
+		Input struct {
+			AdminID string
+			ID      string
+		}
+		Mock struct {
+			GetUserByID error
+		}
+		Output struct {
+			Code    int
+			Message string
+		}
+	}
+	adminID := uuid.New().String()
+	id := uuid.New().String()
+	cases := []test{
+		{
+			Name: "suspend user success",
+			Input: struct {
+				AdminID string
+				ID      string
+			}{AdminID: adminID, ID: id},
+			Output: struct {
+				Code    int
+				Message string
+			}{Code: 200, Message: "suspend user success"},
+		},
+		{
+			Name: "cannot suspend yourself",
+			Input: struct {
+				AdminID string
+				ID      string
+			}{AdminID: adminID, ID: adminID},
+			Output: struct {
+				Code    int
+				Message string
+			}{Code: 400, Message: "cannot suspend yourself"},
+		},
+		{
+			Name: "user not found",
+			Input: struct {
+				AdminID string
+				ID      string
+			}{AdminID: adminID, ID: id},
+			Mock: struct {
+				GetUserByID error
+			}{GetUserByID: mongo.ErrNoDocuments},
+			Output: struct {
+				Code    int
+				Message string
+			}{Code: 404, Message: mongo.ErrNoDocuments.Error()},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			userRepo := repositories.NewUserRepositoryMock()
+			userRepo.On("GetUserByID", c.Input.ID).Return(models.UserModel{ID: c.Input.ID}, c.Mock.GetUserByID)
+			userRepo.On("UpdateUser", c.Input.ID, mock.MatchedBy(func(payload models.UpdateUserModel) bool {
+				return payload.Suspended != nil && *payload.Suspended && *payload.SuspendReason == "spam"
+			})).Return(models.UserModel{ID: c.Input.ID, Suspended: true, SuspendReason: "spam"}, nil)
+
+			adminService := services.NewAdminService(accountRepos(services.AccountRepositories{User: userRepo}))
+			result := adminService.SuspendUser(c.Input.AdminID, c.Input.ID, "spam")
+
+			assert.Equal(t, c.Output.Code, result.Code)
+			assert.Equal(t, c.Output.Message, result.Message)
+		})
+	}
+}
+
+func Test_UpdateRole(t *testing.T) {
+	type test struct {
+		Name   string
+		Input  string
+		Output struct {
+			Code    int
+			Message string
+		}
+	}
+	adminID := uuid.New().String()
+	id := uuid.New().String()
+	cases := []test{
+		{
+			Name:  "update role success",
+			Input: models.RoleModerator,
+			Output: struct {
+				Code    int
+				Message string
+			}{Code: 200, Message: "update role success"},
+		},
+		{
+			Name:  "role invalid",
+			Input: "root",
+			Output: struct {
+				Code    int
+				Message string
+			}{Code: 400, Message: "role invalid"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			userRepo := repositories.NewUserRepositoryMock()
+			userRepo.On("GetUserByID", id).Return(models.UserModel{ID: id}, nil)
+			userRepo.On("UpdateUser", id, mock.Anything).Return(models.UserModel{ID: id, Role: c.Input}, nil)
+
+			adminService := services.NewAdminService(accountRepos(services.AccountRepositories{User: userRepo}))
+			result := adminService.UpdateRole(adminID, id, c.Input)
+
+			assert.Equal(t, c.Output.Code, result.Code)
+			assert.Equal(t, c.Output.Message, result.Message)
+		})
+	}
+}
+
+func Test_SetTrusted(t *testing.T) {
+	adminID := uuid.New().String()
+	id := uuid.New().String()
+	userRepo := repositories.NewUserRepositoryMock()
+	userRepo.On("GetUserByID", id).Return(models.UserModel{ID: id}, nil)
+	userRepo.On("UpdateUser", id, mock.MatchedBy(func(payload models.UpdateUserModel) bool {
+		return payload.Trusted != nil && *payload.Trusted
+	})).Return(models.UserModel{ID: id, Trusted: true}, nil)
+
+	adminService := services.NewAdminService(accountRepos(services.AccountRepositories{User: userRepo}))
+	result := adminService.SetTrusted(adminID, id, true)
+	assert.Equal(t, "set trusted success", result.Message)
+	assert.True(t, result.Result.(models.UserResModel).Trusted)
+
+	result = adminService.SetTrusted(adminID, "", true)
+	assert.Equal(t, "id not found", result.Message)
+}
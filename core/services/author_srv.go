@@ -0,0 +1,243 @@
+package services
+
+import (
+	"backend/core/models"
+	"backend/core/repositories"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	maxAuthorNameLength = 100
+	maxAuthorBioLength  = 1000
+)
+
+type AuthorService interface {
+	GetAuthors(search string, page int, limit int) (result models.ResponseModel)
+
+	GetAuthor(id string) (result models.ResponseModel)
+
+	UpdateAuthor(id string, name string, bio *string) (result models.ResponseModel)
+}
+
+type AuthorSrv struct {
+	authorRepo repositories.AuthorRepository
+	quoteRepo  repositories.QuoteRepository
+}
+
+func NewAuthorService(authorRepo repositories.AuthorRepository, quoteRepo repositories.QuoteRepository) AuthorService {
+	return &AuthorSrv{
+		authorRepo: authorRepo,
+		quoteRepo:  quoteRepo,
+	}
+}
+
+// authorNameKey is how author names are matched, so "Buddha" and " buddha " are one author.
+func authorNameKey(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// normalizeAuthorName collapses the white space of an author name and checks its length.
+func normalizeAuthorName(name string) (string, error) {
+	name = strings.Join(strings.Fields(name), " ")
+	if utf8.RuneCountInString(name) > maxAuthorNameLength {
+		return "", fmt.Errorf("author must be <= %d characters", maxAuthorNameLength)
+	}
+	return name, nil
+}
+
+// ensureAuthor returns the author called name, creating it on first use.
+func ensureAuthor(authorRepo repositories.AuthorRepository, name string) (models.AuthorModel, error) {
+	name, err := normalizeAuthorName(name)
+	if err != nil {
+		return models.AuthorModel{}, err
+	}
+	if author, err := authorRepo.GetAuthorByNameKey(authorNameKey(name)); err == nil {
+		return author, nil
+	}
+	author, err := authorRepo.CreateAuthor(models.CreateAuthorModel{
+		ID:         uuid.New().String(),
+		Name:       name,
+		NameKey:    authorNameKey(name),
+		CreateDate: time.Now(),
+		UpdateDate: time.Now(),
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		// another request created the author in the meantime
+		return authorRepo.GetAuthorByNameKey(authorNameKey(name))
+	}
+	return author, err
+}
+
+func (s *AuthorSrv) GetAuthors(search string, page int, limit int) (result models.ResponseModel) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultPageLimit
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+	res, total, err := s.authorRepo.GetAuthors(search, page, limit)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if res == nil {
+		res = []models.AuthorModel{}
+	}
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "get authors success",
+		Result: models.AuthorListModel{
+			Authors: res,
+			Total:   total,
+			Page:    page,
+			Limit:   limit,
+		},
+	}
+}
+
+func (s *AuthorSrv) GetAuthor(id string) (result models.ResponseModel) {
+	author, err := s.authorRepo.GetAuthor(id)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    404,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	filter := models.QuoteFilterModel{
+		Limit:      maxPageLimit,
+		Sort:       models.QuoteSortVote,
+		Descending: true,
+		AuthorID:   id,
+	}
+	quotes, err := s.quoteRepo.GetQuotes(filter)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	total, err := s.quoteRepo.CountQuotes(filter)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if quotes == nil {
+		quotes = []models.QuoteModel{}
+	}
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "get author success",
+		Result: models.AuthorPageModel{
+			Author: author,
+			Quotes: quotes,
+			Total:  total,
+		},
+	}
+}
+
+func (s *AuthorSrv) UpdateAuthor(id string, name string, bio *string) (result models.ResponseModel) {
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" && bio == nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "nothing to update",
+			Result:  nil,
+		}
+	}
+	if utf8.RuneCountInString(name) > maxAuthorNameLength {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: fmt.Sprintf("author must be <= %d characters", maxAuthorNameLength),
+			Result:  nil,
+		}
+	}
+	if bio != nil && utf8.RuneCountInString(*bio) > maxAuthorBioLength {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: fmt.Sprintf("bio must be <= %d characters", maxAuthorBioLength),
+			Result:  nil,
+		}
+	}
+	author, err := s.authorRepo.GetAuthor(id)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    404,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	payload := models.UpdateAuthorModel{
+		Bio:        bio,
+		UpdateDate: time.Now(),
+	}
+	if name != "" && name != author.Name {
+		if other, err := s.authorRepo.GetAuthorByNameKey(authorNameKey(name)); err == nil && other.ID != id {
+			return models.ResponseModel{
+				Status:  false,
+				Code:    409,
+				Message: "author already exist",
+				Result:  nil,
+			}
+		}
+		if err := s.quoteRepo.SetAuthorName(id, name); err != nil {
+			return models.ResponseModel{
+				Status:  false,
+				Code:    400,
+				Message: err.Error(),
+				Result:  nil,
+			}
+		}
+		payload.Name = name
+		payload.NameKey = authorNameKey(name)
+	}
+	res, err := s.authorRepo.UpdateAuthor(id, payload)
+	if mongo.IsDuplicateKeyError(err) {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    409,
+			Message: "author already exist",
+			Result:  nil,
+		}
+	}
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "update author success",
+		Result:  res,
+	}
+}
@@ -0,0 +1,76 @@
+package services_test
+
+import (
+	"backend/core/models"
+	"backend/core/repositories"
+	"backend/core/services"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_GetAuthor(t *testing.T) {
+	authorRepo := repositories.NewAuthorRepositoryMock()
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	authorRepo.On("GetAuthor", "buddha").Return(models.AuthorModel{ID: "buddha", Name: "Buddha"}, nil)
+	authorRepo.On("GetAuthor", "unknown").Return(models.AuthorModel{}, errors.New("author not found"))
+	quoteRepo.On("GetQuotes", mock.MatchedBy(func(filter models.QuoteFilterModel) bool {
+		return filter.AuthorID == "buddha" && filter.Sort == models.QuoteSortVote && filter.Descending
+	})).Return([]models.QuoteModel{{ID: "1", AuthorID: "buddha", Vote: 3}}, nil)
+	quoteRepo.On("CountQuotes", mock.Anything).Return(int64(1), nil)
+
+	authorService := services.NewAuthorService(authorRepo, quoteRepo)
+	result := authorService.GetAuthor("buddha")
+	assert.Equal(t, "get author success", result.Message)
+	page := result.Result.(models.AuthorPageModel)
+	assert.Equal(t, "Buddha", page.Author.Name)
+	assert.Equal(t, int64(1), page.Total)
+	assert.Len(t, page.Quotes, 1)
+
+	result = authorService.GetAuthor("unknown")
+	assert.Equal(t, 404, result.Code)
+}
+
+func Test_UpdateAuthor(t *testing.T) {
+	authorRepo := repositories.NewAuthorRepositoryMock()
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	authorRepo.On("GetAuthor", "1").Return(models.AuthorModel{ID: "1", Name: "Budha"}, nil)
+	authorRepo.On("GetAuthorByNameKey", "buddha").Return(models.AuthorModel{}, errors.New("author not found"))
+	authorRepo.On("GetAuthorByNameKey", "lao tzu").Return(models.AuthorModel{ID: "2", Name: "Lao Tzu"}, nil)
+	authorRepo.On("UpdateAuthor", "1", mock.MatchedBy(func(payload models.UpdateAuthorModel) bool {
+		return payload.Name == "Buddha" && payload.NameKey == "buddha"
+	})).Return(models.AuthorModel{ID: "1", Name: "Buddha"}, nil)
+	quoteRepo.On("SetAuthorName", "1", "Buddha").Return(nil)
+
+	authorService := services.NewAuthorService(authorRepo, quoteRepo)
+	cases := []struct {
+		Name     string
+		Input    string
+		Expected string
+	}{
+		{
+			Name:     "rename",
+			Input:    " Buddha ",
+			Expected: "update author success",
+		},
+		{
+			Name:     "name taken",
+			Input:    "Lao  Tzu",
+			Expected: "author already exist",
+		},
+		{
+			Name:     "nothing to update",
+			Input:    "",
+			Expected: "nothing to update",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			result := authorService.UpdateAuthor("1", c.Input, nil)
+			assert.Equal(t, c.Expected, result.Message)
+		})
+	}
+	quoteRepo.AssertNumberOfCalls(t, "SetAuthorName", 1)
+}
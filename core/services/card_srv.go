@@ -0,0 +1,393 @@
+package services
+
+import (
+	"backend/config"
+	"backend/core/models"
+	"backend/core/repositories"
+	"backend/utils"
+	"container/list"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultCardTemplate is the built in look of a quote card, sized for link
+// previews. The "default" entry of the templates file overrides it field by field.
+var defaultCardTemplate = models.CardTemplateModel{
+	Width:         1200,
+	Height:        630,
+	Padding:       80,
+	Background:    "#1f2937",
+	Foreground:    "#f9fafb",
+	Accent:        "#fbbf24",
+	FontSize:      56,
+	MinFontSize:   24,
+	SmallFontSize: 30,
+	LineHeight:    1.5,
+	Align:         utils.CardAlignCenter,
+	VoteFormat:    "%d votes",
+}
+
+var cardContentTypes = map[string]string{
+	models.CardFormatPNG: "image/png",
+	models.CardFormatSVG: "image/svg+xml",
+}
+
+type CardService interface {
+	GetCard(id string, format string, template string) (result models.ResponseModel)
+
+	GetCardTemplates() (result models.ResponseModel)
+
+	ReloadCardTemplates() (result models.ResponseModel)
+}
+
+// cardTemplate is a template checked and ready to render, tag changes
+// whenever the template does so it can be part of the ETag.
+type cardTemplate struct {
+	model      models.CardTemplateModel
+	style      utils.CardStyle
+	voteFormat string
+	tag        string
+}
+
+type CardSrv struct {
+	quoteRepo    repositories.QuoteRepository
+	templateRepo repositories.CardTemplateRepository
+	mu           sync.RWMutex
+	templates    map[string]cardTemplate
+	cache        *cardCache
+}
+
+func NewCardService(quoteRepo repositories.QuoteRepository, templateRepo repositories.CardTemplateRepository) CardService {
+	s := &CardSrv{
+		quoteRepo:    quoteRepo,
+		templateRepo: templateRepo,
+		cache:        newCardCache(config.Env.CardCacheSize),
+	}
+	// the built in default always compiles, so cards work before the first reload
+	s.load(map[string]models.CardTemplateModel{})
+	return s
+}
+
+// mergeCardTemplate fills the zero fields of t from base.
+func mergeCardTemplate(base models.CardTemplateModel, t models.CardTemplateModel) models.CardTemplateModel {
+	if t.Width == 0 {
+		t.Width = base.Width
+	}
+	if t.Height == 0 {
+		t.Height = base.Height
+	}
+	if t.Padding == 0 {
+		t.Padding = base.Padding
+	}
+	if t.Background == "" {
+		t.Background = base.Background
+	}
+	if t.Foreground == "" {
+		t.Foreground = base.Foreground
+	}
+	if t.Accent == "" {
+		t.Accent = base.Accent
+	}
+	if t.FontSize == 0 {
+		t.FontSize = base.FontSize
+	}
+	if t.MinFontSize == 0 {
+		t.MinFontSize = min(base.MinFontSize, t.FontSize)
+	}
+	if t.SmallFontSize == 0 {
+		t.SmallFontSize = base.SmallFontSize
+	}
+	if t.LineHeight == 0 {
+		t.LineHeight = base.LineHeight
+	}
+	if t.Align == "" {
+		t.Align = base.Align
+	}
+	if t.VoteFormat == "" {
+		t.VoteFormat = base.VoteFormat
+	}
+	return t
+}
+
+func compileCardTemplate(name string, t models.CardTemplateModel) (cardTemplate, error) {
+	style := utils.CardStyle{
+		Width:         t.Width,
+		Height:        t.Height,
+		Padding:       t.Padding,
+		FontSize:      t.FontSize,
+		MinFontSize:   t.MinFontSize,
+		SmallFontSize: t.SmallFontSize,
+		LineHeight:    t.LineHeight,
+		Align:         t.Align,
+	}
+	var err error
+	if style.Background, err = utils.ParseHexColor(t.Background); err != nil {
+		return cardTemplate{}, fmt.Errorf("card template %q: %w", name, err)
+	}
+	if style.Foreground, err = utils.ParseHexColor(t.Foreground); err != nil {
+		return cardTemplate{}, fmt.Errorf("card template %q: %w", name, err)
+	}
+	if style.Accent, err = utils.ParseHexColor(t.Accent); err != nil {
+		return cardTemplate{}, fmt.Errorf("card template %q: %w", name, err)
+	}
+	if style.MinFontSize > style.FontSize {
+		return cardTemplate{}, fmt.Errorf("card template %q: min_font_size must be <= font_size", name)
+	}
+	if err := utils.ValidateCardStyle(style); err != nil {
+		return cardTemplate{}, fmt.Errorf("card template %q: %w", name, err)
+	}
+	if strings.Count(t.VoteFormat, "%") != 1 || !strings.Contains(t.VoteFormat, "%d") {
+		return cardTemplate{}, fmt.Errorf("card template %q: vote_format must contain %%d once", name)
+	}
+	data, _ := json.Marshal(t)
+	h := fnv.New64a()
+	h.Write(data)
+	return cardTemplate{
+		model:      t,
+		style:      style,
+		voteFormat: t.VoteFormat,
+		tag:        fmt.Sprintf("%x", h.Sum64()),
+	}, nil
+}
+
+// load compiles every template and swaps them in at once, cached cards of the
+// old templates are dropped.
+func (s *CardSrv) load(raw map[string]models.CardTemplateModel) error {
+	base := mergeCardTemplate(defaultCardTemplate, raw[models.CardTemplateDefault])
+	templates := map[string]cardTemplate{}
+	compiled, err := compileCardTemplate(models.CardTemplateDefault, base)
+	if err != nil {
+		return err
+	}
+	templates[models.CardTemplateDefault] = compiled
+	for name, t := range raw {
+		if name == models.CardTemplateDefault {
+			continue
+		}
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("card template name must not be empty")
+		}
+		compiled, err := compileCardTemplate(name, mergeCardTemplate(base, t))
+		if err != nil {
+			return err
+		}
+		templates[name] = compiled
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.templates = templates
+	s.cache.clear()
+	return nil
+}
+
+func (s *CardSrv) template(name string) (cardTemplate, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t, ok := s.templates[name]
+	return t, ok
+}
+
+// cardAttribution is the line under the quote, "— author, year".
+func cardAttribution(quote models.QuoteModel) string {
+	author := strings.TrimSpace(quote.Author)
+	if author == "" {
+		return ""
+	}
+	if quote.Year != 0 {
+		return fmt.Sprintf("— %s, %d", author, quote.Year)
+	}
+	return "— " + author
+}
+
+// cardETag changes whenever the quote, the template or the format does.
+func cardETag(quote models.QuoteModel, name string, t cardTemplate, format string) string {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s|%d|%s|%s|%s", quote.ID, quote.UpdateDate.UnixNano(), name, t.tag, format)
+	return fmt.Sprintf(`"%x"`, h.Sum64())
+}
+
+func (s *CardSrv) GetCard(id string, format string, template string) (result models.ResponseModel) {
+	contentType, ok := cardContentTypes[format]
+	if !ok {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "format must be png or svg",
+			Result:  nil,
+		}
+	}
+	if template == "" {
+		template = models.CardTemplateDefault
+	}
+	t, ok := s.template(template)
+	if !ok {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    404,
+			Message: "card template not found",
+			Result:  nil,
+		}
+	}
+	quote, err := s.quoteRepo.GetQuote(id)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    404,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if !quoteApproved(quote) {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    404,
+			Message: "quote not found",
+			Result:  nil,
+		}
+	}
+
+	key := cardCacheKey(models.CardModel{QuoteID: quote.ID, Template: template, Format: format})
+	if card, ok := s.cache.get(key, quote.UpdateDate); ok {
+		return models.ResponseModel{
+			Status:  true,
+			Code:    200,
+			Message: "get card success",
+			Result:  card,
+		}
+	}
+	card := utils.Card{
+		Text:        "“" + strings.TrimSpace(quote.Quote) + "”",
+		Attribution: cardAttribution(quote),
+		Footer:      fmt.Sprintf(t.voteFormat, quote.Vote),
+		Style:       t.style,
+	}
+	render := utils.RenderCardPNG
+	if format == models.CardFormatSVG {
+		render = utils.RenderCardSVG
+	}
+	body, err := render(card)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    500,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	res := models.CardModel{
+		QuoteID:     quote.ID,
+		Template:    template,
+		Format:      format,
+		ContentType: contentType,
+		ETag:        cardETag(quote, template, t, format),
+		UpdateDate:  quote.UpdateDate,
+		Body:        body,
+	}
+	s.cache.put(key, res)
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "get card success",
+		Result:  res,
+	}
+}
+
+func (s *CardSrv) GetCardTemplates() (result models.ResponseModel) {
+	s.mu.RLock()
+	res := make(map[string]models.CardTemplateModel, len(s.templates))
+	for name, t := range s.templates {
+		res[name] = t.model
+	}
+	s.mu.RUnlock()
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "get card templates success",
+		Result:  res,
+	}
+}
+
+func (s *CardSrv) ReloadCardTemplates() (result models.ResponseModel) {
+	raw, err := s.templateRepo.GetTemplates()
+	if err == nil {
+		err = s.load(raw)
+	}
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	return s.GetCardTemplates()
+}
+
+// cardCache keeps the most recently served cards, an entry only counts while
+// the quote still has the update_date it was rendered from.
+type cardCache struct {
+	mu    sync.Mutex
+	size  int
+	order *list.List
+	items map[string]*list.Element
+}
+
+func newCardCache(size int) *cardCache {
+	return &cardCache{
+		size:  size,
+		order: list.New(),
+		items: map[string]*list.Element{},
+	}
+}
+
+func (c *cardCache) get(key string, updateDate time.Time) (models.CardModel, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.items[key]
+	if !ok {
+		return models.CardModel{}, false
+	}
+	card := el.Value.(models.CardModel)
+	if !card.UpdateDate.Equal(updateDate) {
+		c.order.Remove(el)
+		delete(c.items, key)
+		return models.CardModel{}, false
+	}
+	c.order.MoveToFront(el)
+	return card, true
+}
+
+func (c *cardCache) put(key string, card models.CardModel) {
+	if c.size < 1 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[key]; ok {
+		el.Value = card
+		c.order.MoveToFront(el)
+		return
+	}
+	c.items[key] = c.order.PushFront(card)
+	for c.order.Len() > c.size {
+		last := c.order.Back()
+		c.order.Remove(last)
+		delete(c.items, cardCacheKey(last.Value.(models.CardModel)))
+	}
+}
+
+func cardCacheKey(card models.CardModel) string {
+	return card.QuoteID + "/" + card.Template + "." + card.Format
+}
+
+func (c *cardCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.order.Init()
+	c.items = map[string]*list.Element{}
+}
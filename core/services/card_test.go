@@ -0,0 +1,126 @@
+package services_test
+
+import (
+	"backend/core/models"
+	"backend/core/repositories"
+	"backend/core/services"
+	"bytes"
+	"errors"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GetCard(t *testing.T) {
+	updated := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	quote := models.QuoteModel{ID: "1", Quote: "น้ำขึ้นให้รีบตัก", Author: "สุนทรภู่", Vote: 12, Status: models.QuoteStatusApproved, UpdateDate: updated}
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	quoteRepo.On("GetQuote", "1").Return(quote, nil).Twice()
+
+	cardService := services.NewCardService(quoteRepo, repositories.NewCardTemplateRepositoryMock())
+	result := cardService.GetCard("1", models.CardFormatPNG, "")
+	assert.Equal(t, "get card success", result.Message)
+	card := result.Result.(models.CardModel)
+	assert.Equal(t, "image/png", card.ContentType)
+	assert.Equal(t, models.CardTemplateDefault, card.Template)
+	img, err := png.Decode(bytes.NewReader(card.Body))
+	assert.NoError(t, err)
+	assert.Equal(t, 1200, img.Bounds().Dx())
+	assert.Equal(t, 630, img.Bounds().Dy())
+
+	// same update_date is served from the cache
+	cached := cardService.GetCard("1", models.CardFormatPNG, "").Result.(models.CardModel)
+	assert.Equal(t, card.ETag, cached.ETag)
+	assert.Equal(t, card.Body, cached.Body)
+
+	// a vote bumps update_date and the card is drawn again
+	quote.Vote = 13
+	quote.UpdateDate = updated.Add(time.Minute)
+	quoteRepo.On("GetQuote", "1").Return(quote, nil)
+	fresh := cardService.GetCard("1", models.CardFormatPNG, "").Result.(models.CardModel)
+	assert.NotEqual(t, card.ETag, fresh.ETag)
+	assert.NotEqual(t, card.Body, fresh.Body)
+}
+
+func Test_GetCardSVG(t *testing.T) {
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	quoteRepo.On("GetQuote", "1").Return(models.QuoteModel{ID: "1", Quote: "Less is more", Author: "Mies van der Rohe", Year: 1947, Status: models.QuoteStatusApproved}, nil)
+
+	cardService := services.NewCardService(quoteRepo, repositories.NewCardTemplateRepositoryMock())
+	result := cardService.GetCard("1", models.CardFormatSVG, "")
+	assert.Equal(t, "get card success", result.Message)
+	card := result.Result.(models.CardModel)
+	assert.Equal(t, "image/svg+xml", card.ContentType)
+	svg := string(card.Body)
+	assert.True(t, strings.HasPrefix(svg, "<svg"))
+	assert.Contains(t, svg, "<title>“Less is more” — Mies van der Rohe, 1947</title>")
+	assert.Contains(t, svg, "<path ")
+	// text is drawn as outlines, the card looks the same without the font
+	assert.NotContains(t, svg, "<text")
+}
+
+func Test_GetCardErrors(t *testing.T) {
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	quoteRepo.On("GetQuote", "1").Return(models.QuoteModel{ID: "1", Quote: "Pending", Status: models.QuoteStatusPending}, nil)
+	quoteRepo.On("GetQuote", "2").Return(models.QuoteModel{}, errors.New("mongo: no documents in result"))
+	cardService := services.NewCardService(quoteRepo, repositories.NewCardTemplateRepositoryMock())
+
+	result := cardService.GetCard("1", "gif", "")
+	assert.Equal(t, 400, result.Code)
+	assert.Equal(t, "format must be png or svg", result.Message)
+
+	result = cardService.GetCard("1", models.CardFormatPNG, "neon")
+	assert.Equal(t, 404, result.Code)
+	assert.Equal(t, "card template not found", result.Message)
+
+	result = cardService.GetCard("1", models.CardFormatPNG, "")
+	assert.Equal(t, 404, result.Code)
+	assert.Equal(t, "quote not found", result.Message)
+
+	result = cardService.GetCard("2", models.CardFormatPNG, "")
+	assert.Equal(t, 404, result.Code)
+}
+
+func Test_ReloadCardTemplates(t *testing.T) {
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	quoteRepo.On("GetQuote", "1").Return(models.QuoteModel{ID: "1", Quote: "ความพยายามอยู่ที่ไหน ความสำเร็จอยู่ที่นั่น", Vote: 3, Status: models.QuoteStatusApproved}, nil)
+	templateRepo := repositories.NewCardTemplateRepositoryMock()
+	templateRepo.On("GetTemplates").Return(map[string]models.CardTemplateModel{
+		models.CardTemplateDefault: {Background: "#ffffff", Foreground: "#111827"},
+		"square":                   {Width: 800, Height: 800, Align: "left", VoteFormat: "%d โหวต"},
+	}, nil).Once()
+	cardService := services.NewCardService(quoteRepo, templateRepo)
+
+	result := cardService.ReloadCardTemplates()
+	assert.Equal(t, "get card templates success", result.Message)
+	templates := result.Result.(map[string]models.CardTemplateModel)
+	// unset fields come from the default template, which comes from the built in one
+	assert.Equal(t, "#ffffff", templates["square"].Background)
+	assert.Equal(t, 1200, templates[models.CardTemplateDefault].Width)
+	assert.Equal(t, "center", templates[models.CardTemplateDefault].Align)
+
+	result = cardService.GetCard("1", models.CardFormatPNG, "square")
+	assert.Equal(t, "get card success", result.Message)
+	img, err := png.Decode(bytes.NewReader(result.Result.(models.CardModel).Body))
+	assert.NoError(t, err)
+	assert.Equal(t, 800, img.Bounds().Dx())
+
+	// a broken file keeps the templates that were loaded
+	templateRepo.On("GetTemplates").Return(map[string]models.CardTemplateModel{
+		"dark": {Background: "black"},
+	}, nil).Once()
+	result = cardService.ReloadCardTemplates()
+	assert.Equal(t, 400, result.Code)
+	assert.Contains(t, result.Message, `card template "dark"`)
+	assert.Equal(t, "get card success", cardService.GetCard("1", models.CardFormatSVG, "square").Message)
+
+	templateRepo.On("GetTemplates").Return(map[string]models.CardTemplateModel{
+		"votes": {VoteFormat: "%s votes"},
+	}, nil).Once()
+	result = cardService.ReloadCardTemplates()
+	assert.Equal(t, 400, result.Code)
+	assert.Contains(t, result.Message, "vote_format")
+}
@@ -0,0 +1,288 @@
+package services
+
+import (
+	"backend/core/models"
+	"backend/core/repositories"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const maxCategoryNameLength = 50
+
+type CategoryService interface {
+	GetCategories() (result models.ResponseModel)
+
+	CreateCategory(name string, parentID string) (result models.ResponseModel)
+
+	UpdateCategory(id string, name string, parentID *string) (result models.ResponseModel)
+
+	DeleteCategory(id string) (result models.ResponseModel)
+}
+
+type CategorySrv struct {
+	categoryRepo repositories.CategoryRepository
+	quoteRepo    repositories.QuoteRepository
+}
+
+func NewCategoryService(categoryRepo repositories.CategoryRepository, quoteRepo repositories.QuoteRepository) CategoryService {
+	return &CategorySrv{
+		categoryRepo: categoryRepo,
+		quoteRepo:    quoteRepo,
+	}
+}
+
+// categoryDescendants returns id together with every category below it.
+func categoryDescendants(categories []models.CategoryModel, id string) []string {
+	children := map[string][]string{}
+	for _, category := range categories {
+		children[category.ParentID] = append(children[category.ParentID], category.ID)
+	}
+	result := []string{}
+	queue := []string{id}
+	seen := map[string]bool{}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if seen[current] {
+			continue
+		}
+		seen[current] = true
+		result = append(result, current)
+		queue = append(queue, children[current]...)
+	}
+	return result
+}
+
+func (s *CategorySrv) GetCategories() (result models.ResponseModel) {
+	categories, err := s.categoryRepo.GetCategories()
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	counts, err := s.quoteRepo.CountByCategory()
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	res := []models.CategoryCountModel{}
+	for _, category := range categories {
+		// a category counts the quotes of its subcategories as well
+		var count int64
+		for _, id := range categoryDescendants(categories, category.ID) {
+			count += counts[id]
+		}
+		res = append(res, models.CategoryCountModel{
+			ID:       category.ID,
+			Name:     category.Name,
+			ParentID: category.ParentID,
+			Count:    count,
+		})
+	}
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "get categories success",
+		Result:  res,
+	}
+}
+
+func (s *CategorySrv) CreateCategory(name string, parentID string) (result models.ResponseModel) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "category name not found",
+			Result:  nil,
+		}
+	}
+	if utf8.RuneCountInString(name) > maxCategoryNameLength {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: fmt.Sprintf("category name must be <= %d characters", maxCategoryNameLength),
+			Result:  nil,
+		}
+	}
+	if parentID != "" {
+		if _, err := s.categoryRepo.GetCategory(parentID); err != nil {
+			return models.ResponseModel{
+				Status:  false,
+				Code:    400,
+				Message: "parent category not found",
+				Result:  nil,
+			}
+		}
+	}
+	res, err := s.categoryRepo.CreateCategory(models.CreateCategoryModel{
+		ID:         uuid.New().String(),
+		Name:       name,
+		ParentID:   parentID,
+		CreateDate: time.Now(),
+		UpdateDate: time.Now(),
+	})
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	return models.ResponseModel{
+		Status:  true,
+		Code:    201,
+		Message: "create category success",
+		Result:  res,
+	}
+}
+
+func (s *CategorySrv) UpdateCategory(id string, name string, parentID *string) (result models.ResponseModel) {
+	name = strings.TrimSpace(name)
+	if name == "" && parentID == nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "nothing to update",
+			Result:  nil,
+		}
+	}
+	if utf8.RuneCountInString(name) > maxCategoryNameLength {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: fmt.Sprintf("category name must be <= %d characters", maxCategoryNameLength),
+			Result:  nil,
+		}
+	}
+	categories, err := s.categoryRepo.GetCategories()
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	found := false
+	parentFound := parentID == nil || *parentID == ""
+	for _, category := range categories {
+		found = found || category.ID == id
+		parentFound = parentFound || category.ID == *parentID
+	}
+	if !found {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    404,
+			Message: "category not found",
+			Result:  nil,
+		}
+	}
+	if !parentFound {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "parent category not found",
+			Result:  nil,
+		}
+	}
+	if parentID != nil && *parentID != "" {
+		for _, descendant := range categoryDescendants(categories, id) {
+			if descendant == *parentID {
+				return models.ResponseModel{
+					Status:  false,
+					Code:    400,
+					Message: "category cannot be moved under itself",
+					Result:  nil,
+				}
+			}
+		}
+	}
+	res, err := s.categoryRepo.UpdateCategory(id, models.UpdateCategoryModel{
+		Name:       name,
+		ParentID:   parentID,
+		UpdateDate: time.Now(),
+	})
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "update category success",
+		Result:  res,
+	}
+}
+
+func (s *CategorySrv) DeleteCategory(id string) (result models.ResponseModel) {
+	categories, err := s.categoryRepo.GetCategories()
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if len(categoryDescendants(categories, id)) > 1 {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "cannot delete category with subcategories",
+			Result:  nil,
+		}
+	}
+	// quotes waiting for moderation, rejected ones and translations point at the category too
+	total, err := s.quoteRepo.CountQuotes(models.QuoteFilterModel{
+		CategoryIDs:  []string{id},
+		Statuses:     []string{models.QuoteStatusApproved, models.QuoteStatusPending, models.QuoteStatusScheduled, models.QuoteStatusRejected},
+		Translations: true,
+	})
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if total > 0 {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "cannot delete category with quotes",
+			Result:  nil,
+		}
+	}
+	if err := s.categoryRepo.DeleteCategory(id); err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "delete category success",
+		Result:  nil,
+	}
+}
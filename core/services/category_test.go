@@ -0,0 +1,110 @@
+package services_test
+
+import (
+	"backend/core/models"
+	"backend/core/repositories"
+	"backend/core/services"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_GetCategories(t *testing.T) {
+	categoryRepo := repositories.NewCategoryRepositoryMock()
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	categoryRepo.On("GetCategories").Return([]models.CategoryModel{
+		{ID: "life", Name: "Life"},
+		{ID: "love", Name: "Love", ParentID: "life"},
+	}, nil)
+	quoteRepo.On("CountByCategory").Return(map[string]int64{"life": 1, "love": 2}, nil)
+
+	categoryService := services.NewCategoryService(categoryRepo, quoteRepo)
+	result := categoryService.GetCategories()
+
+	assert.Equal(t, []models.CategoryCountModel{
+		{ID: "life", Name: "Life", Count: 3},
+		{ID: "love", Name: "Love", ParentID: "life", Count: 2},
+	}, result.Result)
+}
+
+func Test_UpdateCategory(t *testing.T) {
+	type test struct {
+		Name  string
+		Input struct {
+			ID       string
+			ParentID string
+		}
+		Output struct {
+			Code    int
+			Message string
+		}
+	}
+	cases := []test{
+		{
+			Name: "move category success",
+			Input: struct {
+				ID       string
+				ParentID string
+			}{ID: "love", ParentID: "poem"},
+			Output: struct {
+				Code    int
+				Message string
+			}{Code: 200, Message: "update category success"},
+		},
+		{
+			Name: "move under descendant",
+			Input: struct {
+				ID       string
+				ParentID string
+			}{ID: "life", ParentID: "love"},
+			Output: struct {
+				Code    int
+				Message string
+			}{Code: 400, Message: "category cannot be moved under itself"},
+		},
+		{
+			Name: "parent not found",
+			Input: struct {
+				ID       string
+				ParentID string
+			}{ID: "love", ParentID: "unknown"},
+			Output: struct {
+				Code    int
+				Message string
+			}{Code: 400, Message: "parent category not found"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			categoryRepo := repositories.NewCategoryRepositoryMock()
+			categoryRepo.On("GetCategories").Return([]models.CategoryModel{
+				{ID: "life", Name: "Life"},
+				{ID: "love", Name: "Love", ParentID: "life"},
+				{ID: "poem", Name: "Poem"},
+			}, nil)
+			categoryRepo.On("UpdateCategory", c.Input.ID, mock.Anything).Return(models.CategoryModel{ID: c.Input.ID, ParentID: c.Input.ParentID}, nil)
+
+			categoryService := services.NewCategoryService(categoryRepo, repositories.NewQuoteRepositoryMock())
+			result := categoryService.UpdateCategory(c.Input.ID, "", &c.Input.ParentID)
+
+			assert.Equal(t, c.Output.Code, result.Code)
+			assert.Equal(t, c.Output.Message, result.Message)
+		})
+	}
+}
+
+func Test_DeleteCategory(t *testing.T) {
+	categoryRepo := repositories.NewCategoryRepositoryMock()
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	categoryRepo.On("GetCategories").Return([]models.CategoryModel{{ID: "life", Name: "Life"}}, nil)
+	// a pending translation still counts
+	quoteRepo.On("CountQuotes", mock.MatchedBy(func(filter models.QuoteFilterModel) bool {
+		return filter.Translations && len(filter.Statuses) == 4 && filter.CategoryIDs[0] == "life"
+	})).Return(int64(1), nil)
+
+	categoryService := services.NewCategoryService(categoryRepo, quoteRepo)
+	result := categoryService.DeleteCategory("life")
+	assert.Equal(t, "cannot delete category with quotes", result.Message)
+	categoryRepo.AssertNotCalled(t, "DeleteCategory", mock.Anything)
+}
@@ -0,0 +1,283 @@
+package services
+
+import (
+	"backend/core/models"
+	"backend/core/repositories"
+	"backend/utils"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const maxCommentLength = 2000
+
+type CommentService interface {
+	GetComments(quoteID string, page int, limit int) (result models.ResponseModel)
+
+	CreateComment(userID string, quoteID string, body models.HandCreateCommentBodyModel) (result models.ResponseModel)
+
+	UpdateComment(userID string, id string, body models.HandUpdateCommentBodyModel) (result models.ResponseModel)
+
+	DeleteComment(userID string, role string, id string) (result models.ResponseModel)
+}
+
+type CommentSrv struct {
+	commentRepo repositories.CommentRepository
+	quoteRepo   repositories.QuoteRepository
+}
+
+func NewCommentService(commentRepo repositories.CommentRepository, quoteRepo repositories.QuoteRepository) CommentService {
+	return &CommentSrv{
+		commentRepo: commentRepo,
+		quoteRepo:   quoteRepo,
+	}
+}
+
+// commentBody normalizes a comment the same way quote text is normalized.
+func commentBody(body string) (string, error) {
+	body = utils.NormalizeQuote(body)
+	if body == "" {
+		return "", errors.New("comment not found")
+	}
+	if utf8.RuneCountInString(body) > maxCommentLength {
+		return "", fmt.Errorf("comment must be <= %d characters", maxCommentLength)
+	}
+	return body, nil
+}
+
+func (s *CommentSrv) GetComments(quoteID string, page int, limit int) (result models.ResponseModel) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultPageLimit
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+	if _, err := s.quoteRepo.GetQuote(quoteID); err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    404,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	comments, total, err := s.commentRepo.GetComments(quoteID, page, limit)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	threads := make([]models.CommentThreadModel, len(comments))
+	parentIDs := []string{}
+	for i, comment := range comments {
+		threads[i] = models.CommentThreadModel{CommentModel: comment, Replies: []models.CommentModel{}}
+		if comment.ReplyCount > 0 {
+			parentIDs = append(parentIDs, comment.ID)
+		}
+	}
+	if len(parentIDs) > 0 {
+		replies, err := s.commentRepo.GetReplies(parentIDs)
+		if err != nil {
+			return models.ResponseModel{
+				Status:  false,
+				Code:    400,
+				Message: err.Error(),
+				Result:  nil,
+			}
+		}
+		for _, reply := range replies {
+			for i := range threads {
+				if threads[i].ID == reply.ParentID {
+					threads[i].Replies = append(threads[i].Replies, reply)
+					break
+				}
+			}
+		}
+	}
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "get comments success",
+		Result: models.CommentListModel{
+			Comments: threads,
+			Total:    total,
+			Page:     page,
+			Limit:    limit,
+		},
+	}
+}
+
+func (s *CommentSrv) CreateComment(userID string, quoteID string, body models.HandCreateCommentBodyModel) (result models.ResponseModel) {
+	text, err := commentBody(body.Body)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	quote, err := s.quoteRepo.GetQuote(quoteID)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    404,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if !quoteApproved(quote) {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "quote not approved",
+			Result:  nil,
+		}
+	}
+	if body.ParentID != "" {
+		parent, err := s.commentRepo.GetComment(body.ParentID)
+		if err != nil || parent.QuoteID != quote.ID {
+			return models.ResponseModel{
+				Status:  false,
+				Code:    404,
+				Message: "parent comment not found",
+				Result:  nil,
+			}
+		}
+		if parent.ParentID != "" {
+			return models.ResponseModel{
+				Status:  false,
+				Code:    400,
+				Message: "reply can not be replied to",
+				Result:  nil,
+			}
+		}
+	}
+	res, err := s.commentRepo.CreateComment(models.CreateCommentModel{
+		ID:         uuid.New().String(),
+		QuoteID:    quote.ID,
+		ParentID:   body.ParentID,
+		UserID:     userID,
+		Body:       text,
+		CreateDate: time.Now(),
+	})
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	s.count(res, 1)
+	return models.ResponseModel{
+		Status:  true,
+		Code:    201,
+		Message: "create comment success",
+		Result:  res,
+	}
+}
+
+// count keeps the comment count of the quote and the reply count of the
+// parent in step, a failed count is logged and never fails the request.
+func (s *CommentSrv) count(comment models.CommentModel, delta int) {
+	if err := s.quoteRepo.IncrementCommentCount(comment.QuoteID, delta); err != nil {
+		log.Printf("count comments of quote %s failed: %v", comment.QuoteID, err)
+	}
+	if comment.ParentID == "" {
+		return
+	}
+	if err := s.commentRepo.IncrementReplyCount(comment.ParentID, delta); err != nil {
+		log.Printf("count replies of comment %s failed: %v", comment.ParentID, err)
+	}
+}
+
+func (s *CommentSrv) UpdateComment(userID string, id string, body models.HandUpdateCommentBodyModel) (result models.ResponseModel) {
+	text, err := commentBody(body.Body)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	comment, err := s.commentRepo.GetComment(id)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    404,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if comment.UserID != userID {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    403,
+			Message: "forbidden",
+			Result:  nil,
+		}
+	}
+	res, err := s.commentRepo.UpdateComment(id, text)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "update comment success",
+		Result:  res,
+	}
+}
+
+// DeleteComment removes a comment for its author or a moderator.
+func (s *CommentSrv) DeleteComment(userID string, role string, id string) (result models.ResponseModel) {
+	comment, err := s.commentRepo.GetComment(id)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    404,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if comment.UserID != userID && role != models.RoleModerator && role != models.RoleAdmin {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    403,
+			Message: "forbidden",
+			Result:  nil,
+		}
+	}
+	res, err := s.commentRepo.DeleteComment(id, userID)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	s.count(res, -1)
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "delete comment success",
+		Result:  nil,
+	}
+}
@@ -0,0 +1,124 @@
+package services_test
+
+import (
+	"backend/core/models"
+	"backend/core/repositories"
+	"backend/core/services"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_CreateComment(t *testing.T) {
+	commentRepo := repositories.NewCommentRepositoryMock()
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	quoteRepo.On("GetQuote", "1").Return(models.QuoteModel{ID: "1", Status: models.QuoteStatusApproved}, nil)
+	quoteRepo.On("GetQuote", "pending").Return(models.QuoteModel{ID: "pending", Status: models.QuoteStatusPending}, nil)
+	quoteRepo.On("IncrementCommentCount", "1", 1).Return(nil)
+	var created []models.CreateCommentModel
+	commentRepo.On("GetComment", "top").Return(models.CommentModel{ID: "top", QuoteID: "1"}, nil)
+	commentRepo.On("GetComment", "reply").Return(models.CommentModel{ID: "reply", QuoteID: "1", ParentID: "top"}, nil)
+	commentRepo.On("GetComment", "other").Return(models.CommentModel{ID: "other", QuoteID: "2"}, nil)
+	commentRepo.On("CreateComment", mock.Anything).Run(func(args mock.Arguments) {
+		created = append(created, args.Get(0).(models.CreateCommentModel))
+	}).Return(models.CommentModel{ID: "new", QuoteID: "1", ParentID: "top"}, nil)
+	commentRepo.On("IncrementReplyCount", "top", 1).Return(nil)
+
+	commentService := services.NewCommentService(commentRepo, quoteRepo)
+	cases := []struct {
+		Name     string
+		QuoteID  string
+		Body     models.HandCreateCommentBodyModel
+		Expected string
+	}{
+		{Name: "reply", QuoteID: "1", Body: models.HandCreateCommentBodyModel{Body: "  so  true ", ParentID: "top"}, Expected: "create comment success"},
+		{Name: "empty", QuoteID: "1", Body: models.HandCreateCommentBodyModel{Body: " \u200b "}, Expected: "comment not found"},
+		{Name: "too long", QuoteID: "1", Body: models.HandCreateCommentBodyModel{Body: strings.Repeat("a", 2001)}, Expected: "comment must be <= 2000 characters"},
+		{Name: "quote not approved", QuoteID: "pending", Body: models.HandCreateCommentBodyModel{Body: "hi"}, Expected: "quote not approved"},
+		{Name: "reply to reply", QuoteID: "1", Body: models.HandCreateCommentBodyModel{Body: "hi", ParentID: "reply"}, Expected: "reply can not be replied to"},
+		{Name: "parent on other quote", QuoteID: "1", Body: models.HandCreateCommentBodyModel{Body: "hi", ParentID: "other"}, Expected: "parent comment not found"},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			result := commentService.CreateComment("user", c.QuoteID, c.Body)
+			assert.Equal(t, c.Expected, result.Message)
+		})
+	}
+	assert.Len(t, created, 1)
+	assert.Equal(t, "so true", created[0].Body)
+	quoteRepo.AssertNumberOfCalls(t, "IncrementCommentCount", 1)
+	commentRepo.AssertNumberOfCalls(t, "IncrementReplyCount", 1)
+}
+
+func Test_GetComments(t *testing.T) {
+	commentRepo := repositories.NewCommentRepositoryMock()
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	quoteRepo.On("GetQuote", "1").Return(models.QuoteModel{ID: "1"}, nil)
+	quoteRepo.On("GetQuote", "unknown").Return(models.QuoteModel{}, errors.New("mongo: no documents in result"))
+	commentRepo.On("GetComments", "1", 1, 20).Return([]models.CommentModel{
+		{ID: "a", QuoteID: "1", ReplyCount: 2},
+		{ID: "b", QuoteID: "1"},
+	}, int64(2), nil)
+	commentRepo.On("GetReplies", []string{"a"}).Return([]models.CommentModel{
+		{ID: "a1", ParentID: "a"},
+		{ID: "a2", ParentID: "a"},
+	}, nil)
+
+	commentService := services.NewCommentService(commentRepo, quoteRepo)
+	result := commentService.GetComments("1", 0, 0)
+	assert.Equal(t, "get comments success", result.Message)
+	list := result.Result.(models.CommentListModel)
+	assert.Len(t, list.Comments, 2)
+	assert.Len(t, list.Comments[0].Replies, 2)
+	assert.Empty(t, list.Comments[1].Replies)
+
+	result = commentService.GetComments("unknown", 1, 10)
+	assert.Equal(t, 404, result.Code)
+}
+
+func Test_UpdateComment(t *testing.T) {
+	commentRepo := repositories.NewCommentRepositoryMock()
+	commentRepo.On("GetComment", "1").Return(models.CommentModel{ID: "1", UserID: "author"}, nil)
+	commentRepo.On("UpdateComment", "1", "edited").Return(models.CommentModel{ID: "1", UserID: "author", Body: "edited"}, nil)
+
+	commentService := services.NewCommentService(commentRepo, repositories.NewQuoteRepositoryMock())
+	result := commentService.UpdateComment("author", "1", models.HandUpdateCommentBodyModel{Body: "edited"})
+	assert.Equal(t, "update comment success", result.Message)
+
+	result = commentService.UpdateComment("someone", "1", models.HandUpdateCommentBodyModel{Body: "edited"})
+	assert.Equal(t, 403, result.Code)
+
+	result = commentService.UpdateComment("author", "1", models.HandUpdateCommentBodyModel{Body: ""})
+	assert.Equal(t, "comment not found", result.Message)
+}
+
+func Test_DeleteComment(t *testing.T) {
+	commentRepo := repositories.NewCommentRepositoryMock()
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	commentRepo.On("GetComment", "1").Return(models.CommentModel{ID: "1", QuoteID: "q", ParentID: "top", UserID: "author"}, nil)
+	commentRepo.On("DeleteComment", "1", mock.Anything).Return(models.CommentModel{ID: "1", QuoteID: "q", ParentID: "top"}, nil)
+	commentRepo.On("IncrementReplyCount", "top", -1).Return(nil)
+	quoteRepo.On("IncrementCommentCount", "q", -1).Return(nil)
+
+	commentService := services.NewCommentService(commentRepo, quoteRepo)
+	cases := []struct {
+		Name   string
+		UserID string
+		Role   string
+		Code   int
+	}{
+		{Name: "not author", UserID: "someone", Role: models.RoleUser, Code: 403},
+		{Name: "author", UserID: "author", Role: models.RoleUser, Code: 200},
+		{Name: "moderator", UserID: "mod", Role: models.RoleModerator, Code: 200},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			result := commentService.DeleteComment(c.UserID, c.Role, "1")
+			assert.Equal(t, c.Code, result.Code)
+		})
+	}
+	quoteRepo.AssertNumberOfCalls(t, "IncrementCommentCount", 2)
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"backend/core/models"
+	"backend/utils"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// linkPattern finds URLs and bare domains, spam often leaves out the scheme.
+var linkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+|\b[a-z0-9-]+(?:\.[a-z0-9-]+)*\.(?:com|net|org|info|biz|io|co|me|ly|xyz|top|th)\b(?:/\S*)?`)
+
+type filterPhrase struct {
+	language string
+	text     string
+	action   string
+	// substring phrases match anywhere instead of on word boundaries
+	substring bool
+}
+
+type filterPattern struct {
+	re     *regexp.Regexp
+	action string
+	reason string
+}
+
+// contentFilter is the compiled form of the content filter rules, reload swaps
+// everything at once so a check never sees half of a rule set.
+type contentFilter struct {
+	mu       sync.RWMutex
+	rules    models.FilterRulesModel
+	phrases  []filterPhrase
+	patterns []filterPattern
+}
+
+func validFilterAction(action string) bool {
+	return action == models.FilterActionFlag || action == models.FilterActionReject
+}
+
+func foldFilterText(text string) string {
+	return strings.ToLower(utils.NormalizeQuote(text))
+}
+
+func (f *contentFilter) load(rules models.FilterRulesModel) error {
+	if rules.MaxLength < 0 || rules.MaxLinks < 0 {
+		return fmt.Errorf("max_length and max_links must be >= 0")
+	}
+	if rules.LinkAction != "" && rules.LinkAction != models.FilterActionAccept && !validFilterAction(rules.LinkAction) {
+		return fmt.Errorf("link_action %q invalid", rules.LinkAction)
+	}
+	languages := make([]string, 0, len(rules.Phrases))
+	for language := range rules.Phrases {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	phrases := []filterPhrase{}
+	for _, language := range languages {
+		list := rules.Phrases[language]
+		for _, group := range []struct {
+			action string
+			texts  []string
+		}{{models.FilterActionReject, list.Reject}, {models.FilterActionFlag, list.Flag}} {
+			for _, text := range group.texts {
+				text = foldFilterText(text)
+				if text == "" {
+					continue
+				}
+				phrases = append(phrases, filterPhrase{
+					language:  language,
+					text:      text,
+					action:    group.action,
+					substring: strings.IndexFunc(text, utils.IsThai) >= 0,
+				})
+			}
+		}
+	}
+	patterns := []filterPattern{}
+	for _, p := range rules.Patterns {
+		if !validFilterAction(p.Action) {
+			return fmt.Errorf("pattern %q action %q invalid", p.Pattern, p.Action)
+		}
+		re, err := regexp.Compile(p.Pattern)
+		if err != nil {
+			return fmt.Errorf("pattern %q: %w", p.Pattern, err)
+		}
+		reason := p.Reason
+		if reason == "" {
+			reason = fmt.Sprintf("matches pattern %q", p.Pattern)
+		}
+		patterns = append(patterns, filterPattern{re: re, action: p.Action, reason: reason})
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.rules = rules
+	f.phrases = phrases
+	f.patterns = patterns
+	return nil
+}
+
+func (f *contentFilter) getRules() models.FilterRulesModel {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.rules
+}
+
+// containsWord reports whether phrase occurs in text with no letter or digit
+// directly before or after it.
+func containsWord(text string, phrase string) bool {
+	for from := 0; from < len(text); {
+		i := strings.Index(text[from:], phrase)
+		if i < 0 {
+			return false
+		}
+		start, end := from+i, from+i+len(phrase)
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if !isWordRune(before) && !isWordRune(after) {
+			return true
+		}
+		_, size := utf8.DecodeRuneInString(text[start:])
+		from = start + size
+	}
+	return false
+}
+
+func isWordRune(r rune) bool {
+	return r != utf8.RuneError && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+// check screens a quote, the strictest action of all matching rules wins. The
+// phrases of language and "*" apply, a quote without language is checked
+// against every list.
+func (f *contentFilter) check(text string, language string) models.FilterDecisionModel {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	decision := models.FilterDecisionModel{Action: models.FilterActionAccept, Reasons: []string{}}
+	add := func(action string, reason string) {
+		if action == models.FilterActionReject || decision.Action == models.FilterActionAccept {
+			decision.Action = action
+		}
+		decision.Reasons = append(decision.Reasons, reason)
+	}
+
+	if f.rules.MaxLength > 0 && utf8.RuneCountInString(text) > f.rules.MaxLength {
+		add(models.FilterActionReject, fmt.Sprintf("quote must be <= %d characters", f.rules.MaxLength))
+	}
+	links := len(linkPattern.FindAllStringIndex(text, -1))
+	if f.rules.MaxLinks > 0 && links > f.rules.MaxLinks {
+		add(models.FilterActionReject, fmt.Sprintf("quote must have <= %d links", f.rules.MaxLinks))
+	} else if links > 0 && validFilterAction(f.rules.LinkAction) {
+		add(f.rules.LinkAction, "quote contains a link")
+	}
+
+	folded := foldFilterText(text)
+	for _, p := range f.phrases {
+		if language != "" && p.language != "*" && p.language != language {
+			continue
+		}
+		matched := false
+		if p.substring {
+			matched = strings.Contains(folded, p.text)
+		} else {
+			matched = containsWord(folded, p.text)
+		}
+		if matched {
+			add(p.action, fmt.Sprintf("contains %q", p.text))
+		}
+	}
+	for _, p := range f.patterns {
+		if p.re.MatchString(text) {
+			add(p.action, p.reason)
+		}
+	}
+	return decision
+}
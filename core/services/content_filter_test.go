@@ -0,0 +1,118 @@
+package services_test
+
+import (
+	"backend/core/models"
+	"backend/core/repositories"
+	"backend/core/services"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newFilteredQuoteService(quoteRepo repositories.QuoteRepository, revisionRepo repositories.RevisionRepository, rules models.FilterRulesModel) services.QuoteService {
+	filterRepo := repositories.NewFilterRepositoryMock()
+	filterRepo.On("GetRules").Return(rules, nil)
+	quoteService := services.NewQuoteService(quoteRepos(services.QuoteRepositories{Quote: quoteRepo, Revision: revisionRepo, Filter: filterRepo}))
+	quoteService.ReloadContentFilter()
+	return quoteService
+}
+
+func Test_ContentFilter(t *testing.T) {
+	rules := models.FilterRulesModel{
+		MaxLength:  50,
+		LinkAction: models.FilterActionFlag,
+		MaxLinks:   1,
+		Phrases: map[string]models.FilterPhrasesModel{
+			"*":  {Reject: []string{"Damn"}},
+			"en": {Flag: []string{"stupid"}},
+			"th": {Reject: []string{"ควาย"}},
+		},
+		Patterns: []models.FilterPatternModel{
+			{Pattern: `(?i)buy now`, Action: models.FilterActionReject, Reason: "advertising"},
+		},
+	}
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	var created models.CreateQuoteModel
+	quoteRepo.On("CreateQuote", mock.Anything).Run(func(args mock.Arguments) {
+		created = args.Get(0).(models.CreateQuoteModel)
+	}).Return(models.QuoteModel{}, nil)
+	quoteService := newFilteredQuoteService(quoteRepo, repositories.NewRevisionRepositoryMock(), rules)
+
+	cases := []struct {
+		Name     string
+		Quote    string
+		Language string
+		Status   string
+		Reasons  []string
+	}{
+		{Name: "clean", Quote: "be kind", Status: models.QuoteStatusApproved},
+		{Name: "banned word", Quote: "damn it", Reasons: []string{`contains "damn"`}},
+		{Name: "word boundary", Quote: "the damnation of faust", Status: models.QuoteStatusApproved},
+		{Name: "flagged word", Quote: "stupid is as stupid does", Language: "en", Status: models.QuoteStatusPending, Reasons: []string{`contains "stupid"`}},
+		{Name: "other language list", Quote: "only the stupid hurry", Language: "th", Status: models.QuoteStatusApproved},
+		{Name: "thai substring", Quote: "อย่าโง่เหมือนควายนะ", Reasons: []string{`contains "ควาย"`}},
+		{Name: "regex", Quote: "Buy Now and be happy", Reasons: []string{"advertising"}},
+		{Name: "link", Quote: "read more at example.com", Status: models.QuoteStatusPending, Reasons: []string{"quote contains a link"}},
+		{Name: "too many links", Quote: "http://a.example www.b.example", Reasons: []string{"quote must have <= 1 links"}},
+		{Name: "too long", Quote: "this quote is a little bit longer than fifty characters", Reasons: []string{"quote must be <= 50 characters"}},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			result := quoteService.CreateQuote("trusted", true, models.HandCreateQuoteBodyModel{Quote: c.Quote, Language: c.Language})
+			if c.Status == "" {
+				assert.Equal(t, "quote rejected by content filter", result.Message)
+				decision := result.Result.(models.FilterDecisionModel)
+				assert.Equal(t, models.FilterActionReject, decision.Action)
+				assert.Equal(t, c.Reasons, decision.Reasons)
+				return
+			}
+			assert.Equal(t, "create quote success", result.Message)
+			assert.Equal(t, c.Status, created.Status)
+			assert.Equal(t, c.Reasons, created.FlagReasons)
+		})
+	}
+}
+
+func Test_UpdateQuoteContentFilter(t *testing.T) {
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	revisionRepo := repositories.NewRevisionRepositoryMock()
+	quoteRepo.On("GetQuote", "1").Return(models.QuoteModel{ID: "1", Quote: "be kind", Status: models.QuoteStatusApproved, CreatedBy: "owner"}, nil)
+	quoteRepo.On("UpdateQuote", "1", mock.MatchedBy(func(payload models.UpdateQuoteModel) bool {
+		return payload.Status == models.QuoteStatusPending && len(payload.FlagReasons) == 1
+	})).Return(models.QuoteModel{ID: "1", Quote: "be kind, see example.com", Status: models.QuoteStatusPending}, nil)
+	revisionRepo.On("GetRevisions", "1").Return([]models.RevisionModel{{QuoteID: "1", Rev: 1, Quote: "be kind"}}, nil)
+	revisionRepo.On("CreateRevision", mock.Anything).Return(nil)
+
+	quoteService := newFilteredQuoteService(quoteRepo, revisionRepo, models.FilterRulesModel{
+		LinkAction: models.FilterActionFlag,
+		Phrases:    map[string]models.FilterPhrasesModel{"*": {Reject: []string{"damn"}}},
+	})
+	result := quoteService.UpdateQuote("owner", models.RoleUser, true, "1", models.HandUpdateQuoteBodyModel{Quote: "be kind, damn it"})
+	assert.Equal(t, "quote rejected by content filter", result.Message)
+
+	result = quoteService.UpdateQuote("owner", models.RoleUser, true, "1", models.HandUpdateQuoteBodyModel{Quote: "be kind, see example.com"})
+	assert.Equal(t, models.QuoteStatusPending, result.Result.(models.QuoteModel).Status)
+	quoteRepo.AssertNumberOfCalls(t, "UpdateQuote", 1)
+}
+
+func Test_ReloadContentFilter(t *testing.T) {
+	filterRepo := repositories.NewFilterRepositoryMock()
+	filterRepo.On("GetRules").Return(models.FilterRulesModel{MaxLength: 10}, nil).Once()
+	filterRepo.On("GetRules").Return(models.FilterRulesModel{Patterns: []models.FilterPatternModel{{Pattern: "(", Action: models.FilterActionReject}}}, nil).Once()
+	filterRepo.On("GetRules").Return(models.FilterRulesModel{}, errors.New("content filter: unexpected end of JSON input")).Once()
+
+	quoteService := services.NewQuoteService(quoteRepos(services.QuoteRepositories{Filter: filterRepo}))
+	result := quoteService.ReloadContentFilter()
+	assert.Equal(t, "reload content filter success", result.Message)
+
+	result = quoteService.ReloadContentFilter()
+	assert.Equal(t, 400, result.Code)
+	result = quoteService.ReloadContentFilter()
+	assert.Equal(t, 400, result.Code)
+
+	// invalid rules keep the rules in use
+	result = quoteService.GetContentFilter()
+	assert.Equal(t, 10, result.Result.(models.FilterRulesModel).MaxLength)
+}
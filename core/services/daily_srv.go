@@ -0,0 +1,291 @@
+package services
+
+import (
+	"backend/config"
+	"backend/core/models"
+	"backend/core/repositories"
+	"errors"
+	"hash/fnv"
+	"log"
+	"math/rand"
+	"sort"
+	"time"
+	_ "time/tzdata"
+)
+
+type DailyQuoteService interface {
+	GetDailyQuote() (result models.ResponseModel)
+
+	SetDailyQuote(adminID string, body models.HandSetDailyQuoteBodyModel) (result models.ResponseModel)
+
+	GetDailyHistory(page int, limit int) (result models.ResponseModel)
+}
+
+type DailyQuoteSrv struct {
+	dailyRepo repositories.DailyQuoteRepository
+	quoteRepo repositories.QuoteRepository
+}
+
+func NewDailyQuoteService(dailyRepo repositories.DailyQuoteRepository, quoteRepo repositories.QuoteRepository) DailyQuoteService {
+	return &DailyQuoteSrv{
+		dailyRepo: dailyRepo,
+		quoteRepo: quoteRepo,
+	}
+}
+
+// dailyToday is the current calendar day in the daily quote timezone.
+func dailyToday() time.Time {
+	location, err := time.LoadLocation(config.Env.DailyQuoteTimezone)
+	if err != nil {
+		log.Printf("daily quote timezone %q invalid, using UTC: %v", config.Env.DailyQuoteTimezone, err)
+		location = time.UTC
+	}
+	now := time.Now().In(location)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// pickDailyQuote draws one quote weighted by its votes, seeded by the date so
+// every instance draws the same quote for the same day and candidates.
+func pickDailyQuote(date string, candidates []models.QuoteModel) models.QuoteModel {
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].ID < candidates[j].ID })
+	total := int64(0)
+	for _, quote := range candidates {
+		total += voteWeight(quote)
+	}
+	h := fnv.New64a()
+	h.Write([]byte(date))
+	n := rand.New(rand.NewSource(int64(h.Sum64()))).Int63n(total)
+	for _, quote := range candidates {
+		if n -= voteWeight(quote); n < 0 {
+			return quote
+		}
+	}
+	return candidates[len(candidates)-1]
+}
+
+func voteWeight(quote models.QuoteModel) int64 {
+	if quote.Vote < 0 {
+		return 1
+	}
+	return int64(quote.Vote) + 1
+}
+
+// pick chooses the quote of date from the best voted approved quotes, leaving
+// out quotes featured within the cooldown unless nothing else is left.
+func (s *DailyQuoteSrv) pick(date time.Time) (result models.QuoteModel, err error) {
+	candidates, err := s.quoteRepo.GetQuotes(models.QuoteFilterModel{
+		Sort:       models.QuoteSortVote,
+		Descending: true,
+		Limit:      config.Env.DailyQuoteCandidates,
+	})
+	if err != nil {
+		return result, err
+	}
+	if len(candidates) == 0 {
+		return result, errors.New("no quote to feature")
+	}
+	since := date.AddDate(0, 0, -config.Env.DailyQuoteCooldownDays).Format(models.DailyDateFormat)
+	recent, err := s.dailyRepo.GetDailyQuotesSince(since)
+	if err != nil {
+		return result, err
+	}
+	featured := map[string]bool{}
+	for _, day := range recent {
+		featured[day.QuoteID] = true
+	}
+	fresh := []models.QuoteModel{}
+	for _, quote := range candidates {
+		if !featured[quote.ID] {
+			fresh = append(fresh, quote)
+		}
+	}
+	if len(fresh) > 0 {
+		candidates = fresh
+	}
+	return pickDailyQuote(date.Format(models.DailyDateFormat), candidates), nil
+}
+
+func (s *DailyQuoteSrv) GetDailyQuote() (result models.ResponseModel) {
+	today := dailyToday()
+	date := today.Format(models.DailyDateFormat)
+	daily, err := s.dailyRepo.GetDailyQuote(date)
+	if err == nil {
+		quote, err := s.quoteRepo.GetQuote(daily.QuoteID)
+		if err == nil && quoteApproved(quote) {
+			daily.Quote = quote
+			return models.ResponseModel{
+				Status:  true,
+				Code:    200,
+				Message: "get daily quote success",
+				Result:  daily,
+			}
+		}
+	}
+
+	// nothing picked yet, or the pick was deleted or hidden since
+	quote, err := s.pick(today)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    404,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	payload := models.CreateDailyQuoteModel{
+		Date:       date,
+		QuoteID:    quote.ID,
+		PickedBy:   models.DailyPickedAuto,
+		CreateDate: time.Now(),
+	}
+	if daily.QuoteID != "" {
+		daily, err = s.dailyRepo.SetDailyQuote(payload)
+	} else {
+		daily, err = s.dailyRepo.CreateDailyQuote(payload)
+	}
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if daily.QuoteID != quote.ID {
+		// another instance stored its pick first
+		if quote, err = s.quoteRepo.GetQuote(daily.QuoteID); err != nil {
+			return models.ResponseModel{
+				Status:  false,
+				Code:    404,
+				Message: err.Error(),
+				Result:  nil,
+			}
+		}
+	}
+	daily.Quote = quote
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "get daily quote success",
+		Result:  daily,
+	}
+}
+
+func (s *DailyQuoteSrv) SetDailyQuote(adminID string, body models.HandSetDailyQuoteBodyModel) (result models.ResponseModel) {
+	if body.QuoteID == "" {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "quote_id not found",
+			Result:  nil,
+		}
+	}
+	date := body.Date
+	if date == "" {
+		date = dailyToday().Format(models.DailyDateFormat)
+	}
+	if _, err := time.Parse(models.DailyDateFormat, date); err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "date must be YYYY-MM-DD",
+			Result:  nil,
+		}
+	}
+	quote, err := s.quoteRepo.GetQuote(body.QuoteID)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    404,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if !quoteApproved(quote) {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "quote not approved",
+			Result:  nil,
+		}
+	}
+	daily, err := s.dailyRepo.SetDailyQuote(models.CreateDailyQuoteModel{
+		Date:       date,
+		QuoteID:    quote.ID,
+		PickedBy:   adminID,
+		CreateDate: time.Now(),
+	})
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	daily.Quote = quote
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "set daily quote success",
+		Result:  daily,
+	}
+}
+
+// GetDailyHistory lists past quotes of the day, newest first. Overrides set
+// for days still to come stay hidden until their day.
+func (s *DailyQuoteSrv) GetDailyHistory(page int, limit int) (result models.ResponseModel) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultPageLimit
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+	days, total, err := s.dailyRepo.GetDailyQuotes(dailyToday().Format(models.DailyDateFormat), page, limit)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	ids := make([]string, len(days))
+	for i, day := range days {
+		ids[i] = day.QuoteID
+	}
+	quotes, err := s.quoteRepo.GetQuotesByIDs(ids)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	byID := map[string]models.QuoteModel{}
+	for _, quote := range quotes {
+		byID[quote.ID] = quote
+	}
+	res := []models.DailyQuoteModel{}
+	for _, day := range days {
+		if quote, ok := byID[day.QuoteID]; ok && quoteApproved(quote) {
+			day.Quote = quote
+			res = append(res, day)
+		}
+	}
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "get daily history success",
+		Result: models.DailyQuoteHistoryModel{
+			Days:  res,
+			Total: total,
+			Page:  page,
+			Limit: limit,
+		},
+	}
+}
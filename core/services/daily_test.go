@@ -0,0 +1,128 @@
+package services_test
+
+import (
+	"backend/config"
+	"backend/core/models"
+	"backend/core/repositories"
+	"backend/core/services"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func dailyToday(t *testing.T) string {
+	location, err := time.LoadLocation(config.Env.DailyQuoteTimezone)
+	assert.NoError(t, err)
+	return time.Now().In(location).Format(models.DailyDateFormat)
+}
+
+func Test_GetDailyQuote(t *testing.T) {
+	today := dailyToday(t)
+	candidates := []models.QuoteModel{
+		{ID: "1", Quote: "Stay hungry, stay foolish", Vote: 50},
+		{ID: "2", Quote: "Less is more", Vote: 3},
+		{ID: "3", Quote: "Simplicity is the ultimate sophistication", Vote: 0},
+	}
+
+	picks := []string{}
+	for i := 0; i < 2; i++ {
+		dailyRepo := repositories.NewDailyQuoteRepositoryMock()
+		quoteRepo := repositories.NewQuoteRepositoryMock()
+		dailyRepo.On("GetDailyQuote", today).Return(models.DailyQuoteModel{}, errors.New("mongo: no documents in result"))
+		dailyRepo.On("GetDailyQuotesSince", mock.Anything).Return([]models.DailyQuoteModel{{QuoteID: "1"}}, nil)
+		quoteRepo.On("GetQuotes", mock.MatchedBy(func(filter models.QuoteFilterModel) bool {
+			return filter.Sort == models.QuoteSortVote && filter.Descending
+		})).Return(append([]models.QuoteModel{}, candidates...), nil)
+		dailyRepo.On("CreateDailyQuote", mock.Anything).Run(func(args mock.Arguments) {
+			payload := args.Get(0).(models.CreateDailyQuoteModel)
+			assert.Equal(t, today, payload.Date)
+			assert.Equal(t, models.DailyPickedAuto, payload.PickedBy)
+			picks = append(picks, payload.QuoteID)
+		}).Return(models.DailyQuoteModel{Date: today, QuoteID: "2", PickedBy: models.DailyPickedAuto}, nil)
+		quoteRepo.On("GetQuote", "2").Return(candidates[1], nil)
+
+		dailyService := services.NewDailyQuoteService(dailyRepo, quoteRepo)
+		result := dailyService.GetDailyQuote()
+		assert.Equal(t, "get daily quote success", result.Message)
+		daily := result.Result.(models.DailyQuoteModel)
+		// the stored pick wins over the local draw
+		assert.Equal(t, "2", daily.Quote.ID)
+	}
+	assert.Len(t, picks, 2)
+	assert.Equal(t, picks[0], picks[1])
+	// quote 1 was featured recently
+	assert.NotEqual(t, "1", picks[0])
+}
+
+func Test_GetDailyQuoteStored(t *testing.T) {
+	today := dailyToday(t)
+	dailyRepo := repositories.NewDailyQuoteRepositoryMock()
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	dailyRepo.On("GetDailyQuote", today).Return(models.DailyQuoteModel{Date: today, QuoteID: "1", PickedBy: "admin"}, nil)
+	quoteRepo.On("GetQuote", "1").Return(models.QuoteModel{ID: "1", Status: models.QuoteStatusApproved}, nil)
+
+	dailyService := services.NewDailyQuoteService(dailyRepo, quoteRepo)
+	result := dailyService.GetDailyQuote()
+	assert.Equal(t, "get daily quote success", result.Message)
+	assert.Equal(t, "admin", result.Result.(models.DailyQuoteModel).PickedBy)
+	quoteRepo.AssertNotCalled(t, "GetQuotes", mock.Anything)
+
+	dailyRepo = repositories.NewDailyQuoteRepositoryMock()
+	quoteRepo = repositories.NewQuoteRepositoryMock()
+	dailyRepo.On("GetDailyQuote", today).Return(models.DailyQuoteModel{Date: today, QuoteID: "1"}, nil)
+	dailyRepo.On("GetDailyQuotesSince", mock.Anything).Return([]models.DailyQuoteModel{}, nil)
+	dailyRepo.On("SetDailyQuote", mock.MatchedBy(func(payload models.CreateDailyQuoteModel) bool {
+		return payload.QuoteID == "2"
+	})).Return(models.DailyQuoteModel{Date: today, QuoteID: "2"}, nil)
+	quoteRepo.On("GetQuote", "1").Return(models.QuoteModel{ID: "1", Status: models.QuoteStatusPending}, nil)
+	quoteRepo.On("GetQuotes", mock.Anything).Return([]models.QuoteModel{{ID: "2"}}, nil)
+
+	dailyService = services.NewDailyQuoteService(dailyRepo, quoteRepo)
+	result = dailyService.GetDailyQuote()
+	assert.Equal(t, "get daily quote success", result.Message)
+	assert.Equal(t, "2", result.Result.(models.DailyQuoteModel).Quote.ID)
+}
+
+func Test_SetDailyQuote(t *testing.T) {
+	dailyRepo := repositories.NewDailyQuoteRepositoryMock()
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	quoteRepo.On("GetQuote", "1").Return(models.QuoteModel{ID: "1"}, nil)
+	quoteRepo.On("GetQuote", "pending").Return(models.QuoteModel{ID: "pending", Status: models.QuoteStatusPending}, nil)
+	dailyRepo.On("SetDailyQuote", mock.MatchedBy(func(payload models.CreateDailyQuoteModel) bool {
+		return payload.Date == "2026-12-25" && payload.QuoteID == "1" && payload.PickedBy == "admin"
+	})).Return(models.DailyQuoteModel{Date: "2026-12-25", QuoteID: "1", PickedBy: "admin"}, nil)
+
+	dailyService := services.NewDailyQuoteService(dailyRepo, quoteRepo)
+	result := dailyService.SetDailyQuote("admin", models.HandSetDailyQuoteBodyModel{QuoteID: "1", Date: "2026-12-25"})
+	assert.Equal(t, "set daily quote success", result.Message)
+
+	result = dailyService.SetDailyQuote("admin", models.HandSetDailyQuoteBodyModel{QuoteID: "1", Date: "25/12/2026"})
+	assert.Equal(t, "date must be YYYY-MM-DD", result.Message)
+
+	result = dailyService.SetDailyQuote("admin", models.HandSetDailyQuoteBodyModel{QuoteID: "pending"})
+	assert.Equal(t, "quote not approved", result.Message)
+
+	result = dailyService.SetDailyQuote("admin", models.HandSetDailyQuoteBodyModel{})
+	assert.Equal(t, "quote_id not found", result.Message)
+}
+
+func Test_GetDailyHistory(t *testing.T) {
+	today := dailyToday(t)
+	dailyRepo := repositories.NewDailyQuoteRepositoryMock()
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	dailyRepo.On("GetDailyQuotes", today, 1, 20).Return([]models.DailyQuoteModel{
+		{Date: today, QuoteID: "1"},
+		{Date: "2026-01-01", QuoteID: "2"},
+	}, int64(2), nil)
+	quoteRepo.On("GetQuotesByIDs", []string{"1", "2"}).Return([]models.QuoteModel{{ID: "1"}, {ID: "2", Status: models.QuoteStatusPending}}, nil)
+
+	dailyService := services.NewDailyQuoteService(dailyRepo, quoteRepo)
+	result := dailyService.GetDailyHistory(0, 0)
+	assert.Equal(t, "get daily history success", result.Message)
+	res := result.Result.(models.DailyQuoteHistoryModel)
+	assert.Len(t, res.Days, 1)
+	assert.Equal(t, "1", res.Days[0].Quote.ID)
+}
@@ -0,0 +1,115 @@
+package services_test
+
+import (
+	"backend/core/models"
+	"backend/core/repositories"
+	"backend/core/services"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_CreateQuoteDuplicate(t *testing.T) {
+	existing := []models.QuoteModel{
+		{ID: "1", Quote: `"Love the life you live, and live the life you love."`, Vote: 4},
+		{ID: "2", Quote: "น้ำขึ้นให้รีบตัก"},
+	}
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	quoteRepo.On("GetQuotes", mock.Anything).Return(existing, nil)
+	quoteRepo.On("GetQuote", "1").Return(existing[0], nil)
+	quoteRepo.On("GetQuote", "2").Return(existing[1], nil)
+	quoteRepo.On("CreateQuote", mock.MatchedBy(func(payload models.CreateQuoteModel) bool {
+		return payload.Quote == "Be yourself; everyone else is already taken."
+	})).Return(models.QuoteModel{ID: "3", Quote: "Be yourself; everyone else is already taken."}, nil)
+
+	quoteService := services.NewQuoteService(quoteRepos(services.QuoteRepositories{Quote: quoteRepo}))
+	assert.NoError(t, quoteService.ReindexQuotes())
+
+	cases := []struct {
+		Name     string
+		Input    string
+		Expected string
+		Existing string
+	}{
+		{
+			Name:     "punctuation and spacing",
+			Input:    "“Love the  life you live  and live the life you love”",
+			Expected: "quote already exist",
+			Existing: "1",
+		},
+		{
+			Name:     "thai tone mark typed before nikhahit",
+			Input:    "นํ้าขึ้นให้รีบตัก",
+			Expected: "quote already exist",
+			Existing: "2",
+		},
+		{
+			Name:     "new quote",
+			Input:    "  Be yourself;   everyone else is already taken. ",
+			Expected: "create quote success",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			result := quoteService.CreateQuote("user", true, models.HandCreateQuoteBodyModel{Quote: c.Input})
+			assert.Equal(t, c.Expected, result.Message)
+			if c.Existing != "" {
+				assert.Equal(t, 409, result.Code)
+				assert.Equal(t, c.Existing, result.Result.(models.DuplicateQuoteModel).Existing.ID)
+			}
+		})
+	}
+}
+
+func Test_GetDuplicates(t *testing.T) {
+	quotes := []models.QuoteModel{
+		{ID: "1", Quote: "Love the life you live, and live the life you love."},
+		{ID: "2", Quote: "Love the life you live and live the life you love!"},
+		{ID: "3", Quote: "love the life you live; and live the life you love"},
+		{ID: "4", Quote: "Be yourself; everyone else is already taken."},
+	}
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	quoteRepo.On("GetQuotes", mock.Anything).Return(quotes, nil)
+	quoteRepo.On("GetQuotesByIDs", []string{"1", "2", "3"}).Return(quotes[:3], nil)
+
+	quoteService := services.NewQuoteService(quoteRepos(services.QuoteRepositories{Quote: quoteRepo}))
+	assert.NoError(t, quoteService.ReindexQuotes())
+
+	result := quoteService.GetDuplicates()
+	assert.Equal(t, "get duplicates success", result.Message)
+	clusters := result.Result.([]models.DuplicateClusterModel)
+	assert.Len(t, clusters, 1)
+	assert.Equal(t, []string{"1", "2", "3"}, clusters[0].IDs)
+	assert.Len(t, clusters[0].Quotes, 3)
+}
+
+func Test_MergeQuotes(t *testing.T) {
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	userRepo := repositories.NewUserRepositoryMock()
+	quoteRepo.On("GetQuote", "1").Return(models.QuoteModel{ID: "1", Vote: 4}, nil).Once()
+	quoteRepo.On("GetQuote", "1").Return(models.QuoteModel{ID: "1", Vote: 7}, nil).Once()
+	quoteRepo.On("GetQuote", "2").Return(models.QuoteModel{ID: "2", Vote: 3}, nil)
+	quoteRepo.On("GetQuote", "3").Return(models.QuoteModel{ID: "3", Vote: 0}, nil)
+	quoteRepo.On("GetQuote", "9").Return(models.QuoteModel{}, errors.New("mongo: no documents in result"))
+	quoteRepo.On("IncrementVote", "1", 3).Return(nil)
+	quoteRepo.On("SoftDeleteQuote", mock.Anything, "admin").Return(models.QuoteModel{}, nil)
+	userRepo.On("MoveVotes", mock.Anything, "1").Return(int64(0), nil)
+
+	quoteService := services.NewQuoteService(quoteRepos(services.QuoteRepositories{Quote: quoteRepo, User: userRepo}))
+	result := quoteService.MergeQuotes("admin", "1", []string{"2", "3"})
+	assert.Equal(t, "merge quotes success", result.Message)
+	res := result.Result.(models.MergeQuotesResultModel)
+	assert.Equal(t, 7, res.Quote.Vote)
+	assert.Equal(t, []string{"2", "3"}, res.Merged)
+	quoteRepo.AssertNumberOfCalls(t, "IncrementVote", 1)
+	userRepo.AssertCalled(t, "MoveVotes", "2", "1")
+	userRepo.AssertCalled(t, "MoveVotes", "3", "1")
+
+	result = quoteService.MergeQuotes("admin", "1", []string{"1"})
+	assert.Equal(t, "cannot merge a quote into itself", result.Message)
+
+	result = quoteService.MergeQuotes("admin", "9", []string{"2"})
+	assert.Equal(t, "target quote not found", result.Message)
+}
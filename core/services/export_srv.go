@@ -0,0 +1,411 @@
+package services
+
+import (
+	"backend/core/models"
+	"backend/core/repositories"
+	"backend/utils"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// exportPageSize is how many quotes an export reads from the database at a time.
+const exportPageSize = 500
+
+var exportContentTypes = map[string]string{
+	models.ExportFormatCSV:    "text/csv; charset=utf-8",
+	models.ExportFormatNDJSON: "application/x-ndjson",
+	models.ExportFormatXLSX:   "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+var (
+	exportQuoteColumns  = []string{"id", "quote", "author", "author_id", "tags", "category_id", "language", "year", "source_type", "source_title", "source_url", "votes", "favorites", "comments", "create_date", "update_date"}
+	exportResultColumns = []string{"rank", "quote_id", "quote", "author", "language", "votes", "favorites", "comments", "reactions"}
+	exportVoterColumns  = []string{"voter_id", "voter_email", "voter_name"}
+)
+
+type ExportService interface {
+	ExportQuotes(role string, query models.HandExportQueryModel) (result models.ResponseModel)
+
+	ExportResults(role string, query models.HandExportQueryModel) (result models.ResponseModel)
+}
+
+type ExportSrv struct {
+	quoteRepo    repositories.QuoteRepository
+	userRepo     repositories.UserRepository
+	categoryRepo repositories.CategoryRepository
+}
+
+func NewExportService(quoteRepo repositories.QuoteRepository, userRepo repositories.UserRepository, categoryRepo repositories.CategoryRepository) ExportService {
+	return &ExportSrv{
+		quoteRepo:    quoteRepo,
+		userRepo:     userRepo,
+		categoryRepo: categoryRepo,
+	}
+}
+
+// exportTable writes the rows of an export in one of the export formats.
+type exportTable interface {
+	header(columns []string) error
+	row(values []interface{}) error
+	close() error
+}
+
+func newExportTable(format string, w io.Writer) (exportTable, error) {
+	switch format {
+	case models.ExportFormatNDJSON:
+		return &ndjsonExportTable{w: w}, nil
+	case models.ExportFormatXLSX:
+		x, err := utils.NewXLSXWriter(w, "export")
+		if err != nil {
+			return nil, err
+		}
+		return xlsxExportTable{x}, nil
+	default:
+		// the byte order mark makes spreadsheet programs read Thai text as UTF-8
+		if _, err := io.WriteString(w, "\ufeff"); err != nil {
+			return nil, err
+		}
+		return csvExportTable{csv.NewWriter(w)}, nil
+	}
+}
+
+type csvExportTable struct {
+	w *csv.Writer
+}
+
+func (t csvExportTable) header(columns []string) error {
+	return t.w.Write(columns)
+}
+
+func (t csvExportTable) row(values []interface{}) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		if text, ok := value.(string); ok {
+			record[i] = csvSafe(text)
+		} else if value != nil {
+			record[i] = fmt.Sprint(value)
+		}
+	}
+	return t.w.Write(record)
+}
+
+func (t csvExportTable) close() error {
+	t.w.Flush()
+	return t.w.Error()
+}
+
+// csvSafe keeps a spreadsheet from running user text as a formula.
+func csvSafe(text string) string {
+	if text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
+
+// ndjsonExportTable writes a JSON object per row with the keys in column order.
+type ndjsonExportTable struct {
+	w       io.Writer
+	columns []string
+}
+
+func (t *ndjsonExportTable) header(columns []string) error {
+	t.columns = columns
+	return nil
+}
+
+func (t *ndjsonExportTable) row(values []interface{}) error {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, value := range values {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, _ := json.Marshal(t.columns[i])
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(data)
+	}
+	b.WriteString("}\n")
+	_, err := t.w.Write(b.Bytes())
+	return err
+}
+
+func (t *ndjsonExportTable) close() error {
+	return nil
+}
+
+type xlsxExportTable struct {
+	x *utils.XLSXWriter
+}
+
+func (t xlsxExportTable) header(columns []string) error {
+	return t.x.WriteHeader(columns)
+}
+
+func (t xlsxExportTable) row(values []interface{}) error {
+	return t.x.WriteRow(values)
+}
+
+func (t xlsxExportTable) close() error {
+	return t.x.Close()
+}
+
+func exportDate(date time.Time) string {
+	return date.UTC().Format(time.RFC3339)
+}
+
+func exportQuoteRow(quote models.QuoteModel) []interface{} {
+	return []interface{}{
+		quote.ID,
+		quote.Quote,
+		quote.Author,
+		quote.AuthorID,
+		strings.Join(quote.Tags, ","),
+		quote.CategoryID,
+		quote.Language,
+		quote.Year,
+		quote.Source.Type,
+		quote.Source.Title,
+		quote.Source.URL,
+		quote.Vote,
+		quote.FavoriteCount,
+		quote.CommentCount,
+		exportDate(quote.CreateDate),
+		exportDate(quote.UpdateDate),
+	}
+}
+
+func exportResultRow(rank int, quote models.QuoteModel) []interface{} {
+	reactions := 0
+	for _, count := range quote.Reactions {
+		reactions += count
+	}
+	return []interface{}{
+		rank,
+		quote.ID,
+		quote.Quote,
+		quote.Author,
+		quote.Language,
+		quote.Vote,
+		quote.FavoriteCount,
+		quote.CommentCount,
+		reactions,
+	}
+}
+
+func exportVoterRow(voter models.UserModel) []interface{} {
+	return []interface{}{voter.ID, voter.Email, voter.DisplayName}
+}
+
+// exportFilter checks the format and the filters of an export, they are the
+// filters of GET /quote.
+func (s *ExportSrv) exportFilter(role string, query models.HandExportQueryModel) (filter models.QuoteFilterModel, failure models.ResponseModel, ok bool) {
+	if _, ok := exportContentTypes[query.Format]; query.Format != "" && !ok {
+		return filter, models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "format must be csv, ndjson or xlsx",
+			Result:  nil,
+		}, false
+	}
+	if query.Voters && role != models.RoleAdmin {
+		return filter, models.ResponseModel{
+			Status:  false,
+			Code:    403,
+			Message: "voters can only be exported by admins",
+			Result:  nil,
+		}, false
+	}
+	if query.Limit < 0 {
+		return filter, models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "limit must be >= 0",
+			Result:  nil,
+		}, false
+	}
+	filter, err := quoteFilterFromQuery(models.HandGetQuotesQueryModel{
+		Sort:        query.Sort,
+		Order:       query.Order,
+		MinVotes:    query.MinVotes,
+		CreatedFrom: query.CreatedFrom,
+		CreatedTo:   query.CreatedTo,
+		Tag:         query.Tag,
+		Author:      query.Author,
+		Language:    query.Language,
+	})
+	if err == nil && query.Category != "" {
+		var categories []models.CategoryModel
+		if categories, err = s.categoryRepo.GetCategories(); err == nil {
+			filter.CategoryIDs = categoryDescendants(categories, query.Category)
+		}
+	}
+	if err != nil {
+		return filter, models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}, false
+	}
+	return filter, models.ResponseModel{}, true
+}
+
+// voters returns the voters of each quote. When a translation group is listed
+// once the voters of its other variants count for the quote listed.
+func (s *ExportSrv) voters(quotes []models.QuoteModel, grouped bool) (map[string][]models.UserModel, error) {
+	owner := map[string]string{}
+	groupIDs := []string{}
+	groups := map[string]string{}
+	for _, quote := range quotes {
+		owner[quote.ID] = quote.ID
+		if grouped && quote.GroupID != "" {
+			groupIDs = append(groupIDs, quote.GroupID)
+			groups[quote.GroupID] = quote.ID
+		}
+	}
+	if len(groupIDs) > 0 {
+		members, err := s.quoteRepo.GetGroups(groupIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, member := range members {
+			if _, listed := owner[member.ID]; !listed {
+				owner[member.ID] = groups[member.GroupID]
+			}
+		}
+	}
+	ids := make([]string, 0, len(owner))
+	for id := range owner {
+		ids = append(ids, id)
+	}
+	users, err := s.userRepo.GetVoters(ids)
+	if err != nil {
+		return nil, err
+	}
+	res := map[string][]models.UserModel{}
+	for _, user := range users {
+		res[owner[user.QouteID]] = append(res[owner[user.QouteID]], user)
+	}
+	return res, nil
+}
+
+func (s *ExportSrv) export(kind string, query models.HandExportQueryModel, filter models.QuoteFilterModel) models.ResponseModel {
+	if query.Format == "" {
+		query.Format = models.ExportFormatCSV
+	}
+	columns := exportQuoteColumns
+	if kind == models.ExportKindResults {
+		columns = exportResultColumns
+	}
+	if query.Voters {
+		columns = append(append([]string{}, columns...), exportVoterColumns...)
+	}
+	write := func(w io.Writer) error {
+		table, err := newExportTable(query.Format, w)
+		if err != nil {
+			return err
+		}
+		if err := table.header(columns); err != nil {
+			return err
+		}
+		written, rank, lastVote := 0, 0, 0
+		for {
+			page := filter
+			page.Limit = exportPageSize
+			if query.Limit > 0 && query.Limit-written < page.Limit {
+				page.Limit = query.Limit - written
+			}
+			quotes, err := s.quoteRepo.GetQuotes(page)
+			if err != nil {
+				return err
+			}
+			var voters map[string][]models.UserModel
+			if query.Voters && len(quotes) > 0 {
+				if voters, err = s.voters(quotes, !filter.Translations); err != nil {
+					return err
+				}
+			}
+			for i, quote := range quotes {
+				var values []interface{}
+				if kind == models.ExportKindResults {
+					// quotes with the same votes share a rank, the next rank skips ahead
+					if written+i == 0 || quote.Vote != lastVote {
+						rank = written + i + 1
+					}
+					lastVote = quote.Vote
+					values = exportResultRow(rank, quote)
+				} else {
+					values = exportQuoteRow(quote)
+				}
+				if !query.Voters {
+					if err := table.row(values); err != nil {
+						return err
+					}
+					continue
+				}
+				if len(voters[quote.ID]) == 0 {
+					if err := table.row(append(values, nil, nil, nil)); err != nil {
+						return err
+					}
+				}
+				for _, voter := range voters[quote.ID] {
+					if err := table.row(append(append([]interface{}{}, values...), exportVoterRow(voter)...)); err != nil {
+						return err
+					}
+				}
+			}
+			written += len(quotes)
+			if len(quotes) < page.Limit || (query.Limit > 0 && written >= query.Limit) {
+				break
+			}
+			after := quoteCursor(filter.Sort, quotes[len(quotes)-1])
+			filter.After = &after
+		}
+		return table.close()
+	}
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "export " + kind + " success",
+		Result: models.ExportModel{
+			Kind:        kind,
+			Format:      query.Format,
+			ContentType: exportContentTypes[query.Format],
+			Filename:    fmt.Sprintf("%s-%s.%s", kind, time.Now().Format("20060102"), query.Format),
+			Write:       write,
+		},
+	}
+}
+
+// ExportQuotes exports every variant of the approved quotes that match the filters.
+func (s *ExportSrv) ExportQuotes(role string, query models.HandExportQueryModel) (result models.ResponseModel) {
+	filter, failure, ok := s.exportFilter(role, query)
+	if !ok {
+		return failure
+	}
+	filter.Translations = true
+	return s.export(models.ExportKindQuotes, query, filter)
+}
+
+// ExportResults ranks the approved quotes from the most votes down, a
+// translation group shares its tally so it is ranked once unless a language
+// is asked for.
+func (s *ExportSrv) ExportResults(role string, query models.HandExportQueryModel) (result models.ResponseModel) {
+	query.Sort = models.QuoteSortVote
+	query.Order = "desc"
+	filter, failure, ok := s.exportFilter(role, query)
+	if !ok {
+		return failure
+	}
+	return s.export(models.ExportKindResults, query, filter)
+}
@@ -0,0 +1,191 @@
+package services_test
+
+import (
+	"archive/zip"
+	"backend/core/models"
+	"backend/core/repositories"
+	"backend/core/services"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func runExport(t *testing.T, result models.ResponseModel) []byte {
+	export, ok := result.Result.(models.ExportModel)
+	if !assert.True(t, ok, result.Message) {
+		return nil
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, export.Write(&buf))
+	return buf.Bytes()
+}
+
+func Test_ExportQuotesCSV(t *testing.T) {
+	date := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	quotes := []models.QuoteModel{
+		{ID: "1", Quote: "น้ำขึ้นให้รีบตัก", Author: "Thai proverb", Tags: []string{"time", "luck"}, Language: "th", Vote: 3, CreateDate: date, UpdateDate: date},
+		{ID: "2", Quote: "=HYPERLINK(\"http://evil\")", Vote: 1, CreateDate: date, UpdateDate: date},
+	}
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	quoteRepo.On("GetQuotes", mock.MatchedBy(func(filter models.QuoteFilterModel) bool {
+		return filter.Translations && filter.Sort == models.QuoteSortCreateDate && len(filter.Tags) == 1 && filter.Tags[0] == "time"
+	})).Return(quotes, nil)
+
+	exportService := services.NewExportService(quoteRepo, repositories.NewUserRepositoryMock(), repositories.NewCategoryRepositoryMock())
+	result := exportService.ExportQuotes(models.RoleUser, models.HandExportQueryModel{Tag: "Time"})
+	assert.Equal(t, "export quotes success", result.Message)
+	export := result.Result.(models.ExportModel)
+	assert.Equal(t, "text/csv; charset=utf-8", export.ContentType)
+	assert.True(t, strings.HasSuffix(export.Filename, ".csv"))
+
+	body := runExport(t, result)
+	assert.True(t, bytes.HasPrefix(body, []byte("\ufeff")))
+	records, err := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(body, []byte("\ufeff")))).ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, records, 3)
+	assert.Equal(t, "id", records[0][0])
+	assert.Equal(t, []string{"1", "น้ำขึ้นให้รีบตัก", "Thai proverb", "", "time,luck", "", "th", "0", "", "", "", "3", "0", "0", "2024-05-01T08:00:00Z", "2024-05-01T08:00:00Z"}, records[1])
+	// text from users never reaches a spreadsheet as a formula
+	assert.Equal(t, "'=HYPERLINK(\"http://evil\")", records[2][1])
+}
+
+func Test_ExportResultsPages(t *testing.T) {
+	first := []models.QuoteModel{}
+	for i := 0; i < 500; i++ {
+		first = append(first, models.QuoteModel{ID: fmt.Sprint("q", i), Vote: 1000 - i/2})
+	}
+	second := []models.QuoteModel{{ID: "last", Vote: 5, Reactions: map[string]int{"👍": 2, "❤️": 1}}}
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	quoteRepo.On("GetQuotes", mock.MatchedBy(func(filter models.QuoteFilterModel) bool {
+		return filter.After == nil && filter.Sort == models.QuoteSortVote && filter.Descending && filter.Limit == 500
+	})).Return(first, nil).Once()
+	quoteRepo.On("GetQuotes", mock.MatchedBy(func(filter models.QuoteFilterModel) bool {
+		return filter.After != nil && filter.After.ID == "q499" && filter.After.Vote == 751
+	})).Return(second, nil).Once()
+
+	exportService := services.NewExportService(quoteRepo, repositories.NewUserRepositoryMock(), repositories.NewCategoryRepositoryMock())
+	// results are always ranked from the most votes down
+	result := exportService.ExportResults(models.RoleUser, models.HandExportQueryModel{Format: "ndjson", Sort: "create_date", Order: "asc"})
+	body := runExport(t, result)
+
+	lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+	assert.Len(t, lines, 501)
+	assert.True(t, strings.HasPrefix(lines[0], `{"rank":1,"quote_id":"q0",`))
+	type resultRow struct {
+		Rank      int    `json:"rank"`
+		QuoteID   string `json:"quote_id"`
+		Reactions int    `json:"reactions"`
+	}
+	rows := []resultRow{}
+	for _, line := range lines {
+		row := resultRow{}
+		assert.NoError(t, json.Unmarshal([]byte(line), &row))
+		rows = append(rows, row)
+	}
+	// quotes with the same votes share a rank
+	assert.Equal(t, 1, rows[1].Rank)
+	assert.Equal(t, 3, rows[2].Rank)
+	assert.Equal(t, "last", rows[500].QuoteID)
+	assert.Equal(t, 501, rows[500].Rank)
+	assert.Equal(t, 3, rows[500].Reactions)
+	quoteRepo.AssertNumberOfCalls(t, "GetQuotes", 2)
+}
+
+func Test_ExportResultsVoters(t *testing.T) {
+	quotes := []models.QuoteModel{
+		{ID: "1", Quote: "Peace comes from within.", Vote: 3, GroupID: "1"},
+		{ID: "2", Quote: "The mind is everything.", Vote: 0},
+	}
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	quoteRepo.On("GetQuotes", mock.Anything).Return(quotes, nil)
+	quoteRepo.On("GetGroups", []string{"1"}).Return([]models.QuoteModel{
+		{ID: "1", GroupID: "1"},
+		{ID: "1-th", GroupID: "1", Language: "th"},
+	}, nil)
+	userRepo := repositories.NewUserRepositoryMock()
+	userRepo.On("GetVoters", mock.MatchedBy(func(ids []string) bool {
+		return len(ids) == 3
+	})).Return([]models.UserModel{
+		{ID: "u1", Email: "a@example.com", DisplayName: "A", QouteID: "1"},
+		{ID: "u2", Email: "b@example.com", DisplayName: "B", QouteID: "1-th"},
+	}, nil)
+
+	exportService := services.NewExportService(quoteRepo, userRepo, repositories.NewCategoryRepositoryMock())
+	result := exportService.ExportResults(models.RoleAdmin, models.HandExportQueryModel{Format: "xlsx", Voters: true})
+	assert.Equal(t, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", result.Result.(models.ExportModel).ContentType)
+	body := runExport(t, result)
+
+	archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	assert.NoError(t, err)
+	sheet := ""
+	for _, file := range archive.File {
+		if file.Name == "xl/worksheets/sheet1.xml" {
+			f, _ := file.Open()
+			data, _ := io.ReadAll(f)
+			sheet = string(data)
+		}
+	}
+	assert.Equal(t, 4, strings.Count(sheet, "<row "))
+	assert.Contains(t, sheet, ">voter_email<")
+	// the voter of the Thai variant counts for the group
+	assert.Equal(t, 2, strings.Count(sheet, ">Peace comes from within.<"))
+	assert.Contains(t, sheet, ">a@example.com<")
+	assert.Contains(t, sheet, ">b@example.com<")
+	assert.Equal(t, 1, strings.Count(sheet, ">The mind is everything.<"))
+}
+
+func Test_ExportErrors(t *testing.T) {
+	exportService := services.NewExportService(repositories.NewQuoteRepositoryMock(), repositories.NewUserRepositoryMock(), repositories.NewCategoryRepositoryMock())
+
+	cases := []struct {
+		Name     string
+		Role     string
+		Query    models.HandExportQueryModel
+		Code     int
+		Expected string
+	}{
+		{
+			Name:     "unknown format",
+			Role:     models.RoleAdmin,
+			Query:    models.HandExportQueryModel{Format: "pdf"},
+			Code:     400,
+			Expected: "format must be csv, ndjson or xlsx",
+		},
+		{
+			Name:     "voters for non admins",
+			Role:     models.RoleModerator,
+			Query:    models.HandExportQueryModel{Voters: true},
+			Code:     403,
+			Expected: "voters can only be exported by admins",
+		},
+		{
+			Name:     "negative limit",
+			Role:     models.RoleUser,
+			Query:    models.HandExportQueryModel{Limit: -1},
+			Code:     400,
+			Expected: "limit must be >= 0",
+		},
+		{
+			Name:     "unknown sort",
+			Role:     models.RoleUser,
+			Query:    models.HandExportQueryModel{Sort: "author"},
+			Code:     400,
+			Expected: "sort must be votes, create_date or update_date",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			result := exportService.ExportQuotes(c.Role, c.Query)
+			assert.Equal(t, c.Code, result.Code)
+			assert.Equal(t, c.Expected, result.Message)
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"backend/core/models"
+	"backend/core/repositories"
+	"log"
+	"time"
+)
+
+type FavoriteService interface {
+	AddFavorite(userID string, id string) (result models.ResponseModel)
+
+	RemoveFavorite(userID string, id string) (result models.ResponseModel)
+
+	GetFavorites(userID string, page int, limit int) (result models.ResponseModel)
+}
+
+type FavoriteSrv struct {
+	favoriteRepo repositories.FavoriteRepository
+	quoteRepo    repositories.QuoteRepository
+}
+
+func NewFavoriteService(favoriteRepo repositories.FavoriteRepository, quoteRepo repositories.QuoteRepository) FavoriteService {
+	return &FavoriteSrv{
+		favoriteRepo: favoriteRepo,
+		quoteRepo:    quoteRepo,
+	}
+}
+
+func (s *FavoriteSrv) AddFavorite(userID string, id string) (result models.ResponseModel) {
+	quote, err := s.quoteRepo.GetQuote(id)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    404,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if !quoteApproved(quote) {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "quote not approved",
+			Result:  nil,
+		}
+	}
+	created, err := s.favoriteRepo.AddFavorite(models.CreateFavoriteModel{
+		UserID:     userID,
+		QuoteID:    quote.ID,
+		CreateDate: time.Now(),
+	})
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if !created {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    409,
+			Message: "quote already favorited",
+			Result:  nil,
+		}
+	}
+	if err := s.quoteRepo.IncrementFavoriteCount(quote.ID, 1); err != nil {
+		log.Printf("count favorite of quote %s failed: %v", quote.ID, err)
+	}
+	quote.FavoriteCount++
+	quote.IsFavorited = true
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "favorite quote success",
+		Result:  quote,
+	}
+}
+
+func (s *FavoriteSrv) RemoveFavorite(userID string, id string) (result models.ResponseModel) {
+	removed, err := s.favoriteRepo.RemoveFavorite(userID, id)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if !removed {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    404,
+			Message: "favorite not found",
+			Result:  nil,
+		}
+	}
+	if err := s.quoteRepo.IncrementFavoriteCount(id, -1); err != nil {
+		log.Printf("count favorite of quote %s failed: %v", id, err)
+	}
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "unfavorite quote success",
+		Result:  nil,
+	}
+}
+
+// GetFavorites lists the saved quotes of a user, saved quotes that were deleted
+// or are waiting for moderation are left out of the page.
+func (s *FavoriteSrv) GetFavorites(userID string, page int, limit int) (result models.ResponseModel) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultPageLimit
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+	favorites, total, err := s.favoriteRepo.GetFavorites(userID, page, limit)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	ids := make([]string, len(favorites))
+	for i, favorite := range favorites {
+		ids[i] = favorite.QuoteID
+	}
+	quotes, err := s.quoteRepo.GetQuotesByIDs(ids)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	byID := map[string]models.QuoteModel{}
+	for _, quote := range quotes {
+		byID[quote.ID] = quote
+	}
+	res := []models.QuoteModel{}
+	for _, id := range ids {
+		if quote, ok := byID[id]; ok && quoteApproved(quote) {
+			quote.IsFavorited = true
+			res = append(res, quote)
+		}
+	}
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "get favorites success",
+		Result: models.FavoriteListModel{
+			Quotes: res,
+			Total:  total,
+			Page:   page,
+			Limit:  limit,
+		},
+	}
+}
@@ -0,0 +1,94 @@
+package services_test
+
+import (
+	"backend/core/models"
+	"backend/core/repositories"
+	"backend/core/services"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_AddFavorite(t *testing.T) {
+	favoriteRepo := repositories.NewFavoriteRepositoryMock()
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	quoteRepo.On("GetQuote", "1").Return(models.QuoteModel{ID: "1", Status: models.QuoteStatusApproved, FavoriteCount: 2}, nil)
+	quoteRepo.On("GetQuote", "pending").Return(models.QuoteModel{ID: "pending", Status: models.QuoteStatusPending}, nil)
+	quoteRepo.On("GetQuote", "unknown").Return(models.QuoteModel{}, errors.New("mongo: no documents in result"))
+	quoteRepo.On("IncrementFavoriteCount", "1", 1).Return(nil)
+	favoriteRepo.On("AddFavorite", mock.MatchedBy(func(favorite models.CreateFavoriteModel) bool {
+		return favorite.UserID == "user" && favorite.QuoteID == "1"
+	})).Return(true, nil).Once()
+	favoriteRepo.On("AddFavorite", mock.Anything).Return(false, nil)
+
+	favoriteService := services.NewFavoriteService(favoriteRepo, quoteRepo)
+	result := favoriteService.AddFavorite("user", "1")
+	assert.Equal(t, "favorite quote success", result.Message)
+	quote := result.Result.(models.QuoteModel)
+	assert.Equal(t, 3, quote.FavoriteCount)
+	assert.True(t, quote.IsFavorited)
+
+	result = favoriteService.AddFavorite("user", "1")
+	assert.Equal(t, "quote already favorited", result.Message)
+	quoteRepo.AssertNumberOfCalls(t, "IncrementFavoriteCount", 1)
+
+	result = favoriteService.AddFavorite("user", "pending")
+	assert.Equal(t, "quote not approved", result.Message)
+
+	result = favoriteService.AddFavorite("user", "unknown")
+	assert.Equal(t, 404, result.Code)
+}
+
+func Test_RemoveFavorite(t *testing.T) {
+	favoriteRepo := repositories.NewFavoriteRepositoryMock()
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	favoriteRepo.On("RemoveFavorite", "user", "1").Return(true, nil)
+	favoriteRepo.On("RemoveFavorite", "user", "2").Return(false, nil)
+	quoteRepo.On("IncrementFavoriteCount", "1", -1).Return(nil)
+
+	favoriteService := services.NewFavoriteService(favoriteRepo, quoteRepo)
+	result := favoriteService.RemoveFavorite("user", "1")
+	assert.Equal(t, "unfavorite quote success", result.Message)
+
+	result = favoriteService.RemoveFavorite("user", "2")
+	assert.Equal(t, "favorite not found", result.Message)
+	quoteRepo.AssertNumberOfCalls(t, "IncrementFavoriteCount", 1)
+}
+
+func Test_GetFavorites(t *testing.T) {
+	favoriteRepo := repositories.NewFavoriteRepositoryMock()
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	favoriteRepo.On("GetFavorites", "user", 1, 20).Return([]models.FavoriteModel{
+		{UserID: "user", QuoteID: "2"},
+		{UserID: "user", QuoteID: "deleted"},
+		{UserID: "user", QuoteID: "1"},
+	}, int64(3), nil)
+	quoteRepo.On("GetQuotesByIDs", []string{"2", "deleted", "1"}).Return([]models.QuoteModel{
+		{ID: "1", Status: models.QuoteStatusApproved},
+		{ID: "2", Status: models.QuoteStatusApproved},
+	}, nil)
+
+	favoriteService := services.NewFavoriteService(favoriteRepo, quoteRepo)
+	result := favoriteService.GetFavorites("user", 0, 0)
+	assert.Equal(t, "get favorites success", result.Message)
+	list := result.Result.(models.FavoriteListModel)
+	assert.Equal(t, int64(3), list.Total)
+	assert.Len(t, list.Quotes, 2)
+	assert.Equal(t, "2", list.Quotes[0].ID)
+	assert.True(t, list.Quotes[0].IsFavorited)
+}
+
+func Test_GetQuotesFavorited(t *testing.T) {
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	favoriteRepo := repositories.NewFavoriteRepositoryMock()
+	quoteRepo.On("GetQuotes", mock.Anything).Return([]models.QuoteModel{{ID: "1"}, {ID: "2"}}, nil)
+	favoriteRepo.On("GetFavoritedIDs", "user", []string{"1", "2"}).Return([]string{"2"}, nil)
+
+	quoteService := services.NewQuoteService(quoteRepos(services.QuoteRepositories{Quote: quoteRepo, Favorite: favoriteRepo}))
+	result := quoteService.GetQuotes("user", models.HandGetQuotesQueryModel{})
+	quotes := result.Result.(models.QuoteListModel).Quotes
+	assert.False(t, quotes[0].IsFavorited)
+	assert.True(t, quotes[1].IsFavorited)
+}
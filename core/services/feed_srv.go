@@ -0,0 +1,264 @@
+package services
+
+import (
+	"backend/config"
+	"backend/core/models"
+	"backend/core/repositories"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// feedTitleLength is where an entry title cuts the quote, feed readers show
+// the whole quote as the entry content.
+const feedTitleLength = 80
+
+var feedContentTypes = map[string]string{
+	models.FeedFormatAtom: "application/atom+xml; charset=utf-8",
+	models.FeedFormatRSS:  "application/rss+xml; charset=utf-8",
+	models.FeedFormatJSON: "application/feed+json; charset=utf-8",
+}
+
+type FeedService interface {
+	GetFeed(kind string, format string, query models.HandFeedQueryModel) (result models.ResponseModel)
+}
+
+type FeedSrv struct {
+	quoteRepo repositories.QuoteRepository
+}
+
+func NewFeedService(quoteRepo repositories.QuoteRepository) FeedService {
+	return &FeedSrv{
+		quoteRepo: quoteRepo,
+	}
+}
+
+// feedSiteURL is where quotes are shown, the API's own address unless a site is configured.
+func feedSiteURL(query models.HandFeedQueryModel) string {
+	if config.Env.FeedSiteURL != "" {
+		return strings.TrimRight(config.Env.FeedSiteURL, "/")
+	}
+	return strings.TrimRight(query.BaseURL, "/")
+}
+
+func feedTitle(kind string, tags []string) string {
+	title := config.Env.FeedTitle + " - newest quotes"
+	if kind == models.FeedKindTop {
+		title = config.Env.FeedTitle + " - top quotes"
+	}
+	if len(tags) > 0 {
+		title += " tagged " + strings.Join(tags, ", ")
+	}
+	return title
+}
+
+func feedEntryTitle(quote models.QuoteModel) string {
+	text := strings.TrimSpace(quote.Quote)
+	if utf8.RuneCountInString(text) <= feedTitleLength {
+		return text
+	}
+	return strings.TrimSpace(string([]rune(text)[:feedTitleLength-1])) + "…"
+}
+
+func feedEntryContent(quote models.QuoteModel) string {
+	if quote.Author == "" {
+		return quote.Quote
+	}
+	return quote.Quote + "\n— " + quote.Author
+}
+
+// feedUpdated is the newest update_date of the quotes, a feed without quotes
+// never changes so it gets a fixed time.
+func feedUpdated(quotes []models.QuoteModel) time.Time {
+	updated := time.Unix(0, 0).UTC()
+	for _, quote := range quotes {
+		if quote.UpdateDate.After(updated) {
+			updated = quote.UpdateDate
+		}
+	}
+	return updated.UTC()
+}
+
+func atomFeed(title string, site string, self string, updated time.Time, quotes []models.QuoteModel) ([]byte, error) {
+	feed := models.AtomFeedModel{
+		Title:   title,
+		ID:      self,
+		Updated: updated.Format(time.RFC3339),
+		Links: []models.AtomLinkModel{
+			{Rel: "self", Type: feedContentTypes[models.FeedFormatAtom], Href: self},
+			{Rel: "alternate", Href: site},
+		},
+		Author:  models.AtomPersonModel{Name: config.Env.FeedTitle},
+		Entries: []models.AtomEntryModel{},
+	}
+	for _, quote := range quotes {
+		entry := models.AtomEntryModel{
+			Title:     feedEntryTitle(quote),
+			ID:        "urn:uuid:" + quote.ID,
+			Updated:   quote.UpdateDate.UTC().Format(time.RFC3339),
+			Published: quote.CreateDate.UTC().Format(time.RFC3339),
+			Links:     []models.AtomLinkModel{{Rel: "alternate", Href: site + "/quote/" + quote.ID}},
+			Content:   models.AtomTextModel{Type: "text", Text: feedEntryContent(quote)},
+		}
+		if quote.Author != "" {
+			entry.Authors = []models.AtomPersonModel{{Name: quote.Author}}
+		}
+		for _, tag := range quote.Tags {
+			entry.Categories = append(entry.Categories, models.AtomCategoryModel{Term: tag})
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+	data, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+func rssFeed(title string, site string, self string, updated time.Time, quotes []models.QuoteModel) ([]byte, error) {
+	feed := models.RSSFeedModel{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		DCNS:    "http://purl.org/dc/elements/1.1/",
+		Channel: models.RSSChannelModel{
+			Title:         title,
+			Link:          site,
+			Description:   title,
+			LastBuildDate: updated.Format(time.RFC1123Z),
+			SelfLink:      models.AtomLinkModel{Rel: "self", Type: feedContentTypes[models.FeedFormatRSS], Href: self},
+			Items:         []models.RSSItemModel{},
+		},
+	}
+	for _, quote := range quotes {
+		feed.Channel.Items = append(feed.Channel.Items, models.RSSItemModel{
+			Title:       feedEntryTitle(quote),
+			Link:        site + "/quote/" + quote.ID,
+			GUID:        models.RSSGUIDModel{IsPermaLink: false, GUID: quote.ID},
+			PubDate:     quote.CreateDate.UTC().Format(time.RFC1123Z),
+			Creator:     quote.Author,
+			Categories:  quote.Tags,
+			Description: feedEntryContent(quote),
+		})
+	}
+	data, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+func jsonFeed(title string, site string, self string, quotes []models.QuoteModel) ([]byte, error) {
+	feed := models.JSONFeedModel{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       title,
+		HomePageURL: site,
+		FeedURL:     self,
+		Items:       []models.JSONFeedItemModel{},
+	}
+	for _, quote := range quotes {
+		item := models.JSONFeedItemModel{
+			ID:            quote.ID,
+			URL:           site + "/quote/" + quote.ID,
+			Title:         feedEntryTitle(quote),
+			ContentText:   feedEntryContent(quote),
+			DatePublished: quote.CreateDate.UTC().Format(time.RFC3339),
+			DateModified:  quote.UpdateDate.UTC().Format(time.RFC3339),
+			Tags:          quote.Tags,
+			Language:      quote.Language,
+		}
+		if quote.Author != "" {
+			item.Authors = []models.JSONFeedAuthorModel{{Name: quote.Author}}
+		}
+		feed.Items = append(feed.Items, item)
+	}
+	return json.MarshalIndent(feed, "", "  ")
+}
+
+func (s *FeedSrv) GetFeed(kind string, format string, query models.HandFeedQueryModel) (result models.ResponseModel) {
+	if kind != models.FeedKindNew && kind != models.FeedKindTop {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    404,
+			Message: "feed must be new or top",
+			Result:  nil,
+		}
+	}
+	contentType, ok := feedContentTypes[format]
+	if !ok {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    404,
+			Message: "feed format must be atom, rss or json",
+			Result:  nil,
+		}
+	}
+	filter := models.QuoteFilterModel{
+		Limit:      query.Limit,
+		Sort:       models.QuoteSortCreateDate,
+		Descending: true,
+		AuthorID:   query.Author,
+	}
+	if kind == models.FeedKindTop {
+		filter.Sort = models.QuoteSortVote
+	}
+	if filter.Limit < 1 {
+		filter.Limit = defaultPageLimit
+	}
+	if filter.Limit > maxPageLimit {
+		filter.Limit = maxPageLimit
+	}
+	for _, tag := range strings.Split(query.Tag, ",") {
+		if tag = normalizeTag(tag); tag != "" {
+			filter.Tags = append(filter.Tags, tag)
+		}
+	}
+	quotes, err := s.quoteRepo.GetQuotes(filter)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+
+	title := feedTitle(kind, filter.Tags)
+	site := feedSiteURL(query)
+	updated := feedUpdated(quotes)
+	var body []byte
+	switch format {
+	case models.FeedFormatAtom:
+		body, err = atomFeed(title, site, query.SelfURL, updated, quotes)
+	case models.FeedFormatRSS:
+		body, err = rssFeed(title, site, query.SelfURL, updated, quotes)
+	default:
+		body, err = jsonFeed(title, site, query.SelfURL, quotes)
+	}
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    500,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	h := fnv.New64a()
+	h.Write(body)
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "get feed success",
+		Result: models.FeedModel{
+			Kind:        kind,
+			Format:      format,
+			ContentType: contentType,
+			ETag:        fmt.Sprintf(`"%x"`, h.Sum64()),
+			Updated:     updated,
+			Body:        body,
+		},
+	}
+}
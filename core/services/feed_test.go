@@ -0,0 +1,115 @@
+package services_test
+
+import (
+	"backend/core/models"
+	"backend/core/repositories"
+	"backend/core/services"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var feedQuotes = []models.QuoteModel{
+	{ID: "1", Quote: "Less is more", Author: "Mies van der Rohe", Tags: []string{"design"}, Language: "en", Vote: 3,
+		CreateDate: time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC), UpdateDate: time.Date(2024, 5, 3, 8, 0, 0, 0, time.UTC)},
+	{ID: "2", Quote: "น้ำขึ้นให้รีบตัก", Tags: []string{"thai"}, Language: "th", Vote: 9,
+		CreateDate: time.Date(2024, 4, 1, 8, 0, 0, 0, time.UTC), UpdateDate: time.Date(2024, 5, 4, 9, 30, 0, 0, time.UTC)},
+}
+
+var feedQuery = models.HandFeedQueryModel{
+	BaseURL: "https://api.example.com",
+	SelfURL: "https://api.example.com/feeds/new.atom",
+}
+
+func Test_GetFeedAtom(t *testing.T) {
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	quoteRepo.On("GetQuotes", mock.MatchedBy(func(filter models.QuoteFilterModel) bool {
+		return filter.Sort == models.QuoteSortCreateDate && filter.Descending && filter.Limit == 20
+	})).Return(feedQuotes, nil)
+
+	feedService := services.NewFeedService(quoteRepo)
+	result := feedService.GetFeed(models.FeedKindNew, models.FeedFormatAtom, feedQuery)
+	assert.Equal(t, "get feed success", result.Message)
+	feed := result.Result.(models.FeedModel)
+	assert.Equal(t, "application/atom+xml; charset=utf-8", feed.ContentType)
+	// the feed is as new as its most recently updated quote
+	assert.Equal(t, time.Date(2024, 5, 4, 9, 30, 0, 0, time.UTC), feed.Updated)
+
+	doc := models.AtomFeedModel{}
+	assert.NoError(t, xml.Unmarshal(feed.Body, &doc))
+	assert.Equal(t, "2024-05-04T09:30:00Z", doc.Updated)
+	assert.Equal(t, feedQuery.SelfURL, doc.ID)
+	assert.Len(t, doc.Entries, 2)
+	assert.Equal(t, "urn:uuid:1", doc.Entries[0].ID)
+	assert.Equal(t, "2024-05-03T08:00:00Z", doc.Entries[0].Updated)
+	assert.Equal(t, "2024-05-01T08:00:00Z", doc.Entries[0].Published)
+	assert.Equal(t, "https://api.example.com/quote/1", doc.Entries[0].Links[0].Href)
+	assert.Equal(t, "Less is more\n— Mies van der Rohe", doc.Entries[0].Content.Text)
+	assert.Equal(t, "design", doc.Entries[0].Categories[0].Term)
+
+	// the same quotes give the same ETag, so a reader can revalidate
+	again := feedService.GetFeed(models.FeedKindNew, models.FeedFormatAtom, feedQuery).Result.(models.FeedModel)
+	assert.Equal(t, feed.ETag, again.ETag)
+}
+
+func Test_GetFeedRSS(t *testing.T) {
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	quoteRepo.On("GetQuotes", mock.MatchedBy(func(filter models.QuoteFilterModel) bool {
+		return filter.AuthorID == "author-1" && len(filter.Tags) == 1 && filter.Tags[0] == "design"
+	})).Return(feedQuotes[:1], nil)
+
+	query := feedQuery
+	query.Tag = "Design"
+	query.Author = "author-1"
+	result := services.NewFeedService(quoteRepo).GetFeed(models.FeedKindNew, models.FeedFormatRSS, query)
+	assert.Equal(t, "get feed success", result.Message)
+	body := string(result.Result.(models.FeedModel).Body)
+	assert.Contains(t, body, `<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom" xmlns:dc="http://purl.org/dc/elements/1.1/">`)
+	assert.Contains(t, body, "<title>Quotes - newest quotes tagged design</title>")
+	assert.Contains(t, body, "<lastBuildDate>Fri, 03 May 2024 08:00:00 +0000</lastBuildDate>")
+	assert.Contains(t, body, `<guid isPermaLink="false">1</guid>`)
+	assert.Contains(t, body, "<dc:creator>Mies van der Rohe</dc:creator>")
+}
+
+func Test_GetFeedJSON(t *testing.T) {
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	quoteRepo.On("GetQuotes", mock.MatchedBy(func(filter models.QuoteFilterModel) bool {
+		return filter.Sort == models.QuoteSortVote && filter.Descending && filter.Limit == 100
+	})).Return([]models.QuoteModel{feedQuotes[1], feedQuotes[0]}, nil)
+
+	query := feedQuery
+	query.Limit = 1000
+	query.SelfURL = "https://api.example.com/feeds/top.json"
+	result := services.NewFeedService(quoteRepo).GetFeed(models.FeedKindTop, models.FeedFormatJSON, query)
+	assert.Equal(t, "get feed success", result.Message)
+	feed := result.Result.(models.FeedModel)
+	assert.True(t, strings.HasPrefix(feed.ContentType, "application/feed+json"))
+
+	doc := models.JSONFeedModel{}
+	assert.NoError(t, json.Unmarshal(feed.Body, &doc))
+	assert.Equal(t, "https://jsonfeed.org/version/1.1", doc.Version)
+	assert.Equal(t, "https://api.example.com/feeds/top.json", doc.FeedURL)
+	assert.Equal(t, "2", doc.Items[0].ID)
+	assert.Equal(t, "2024-05-04T09:30:00Z", doc.Items[0].DateModified)
+	assert.Equal(t, "th", doc.Items[0].Language)
+	// a quote without author has no authors rather than an empty name
+	assert.Empty(t, doc.Items[0].Authors)
+	assert.Equal(t, "Mies van der Rohe", doc.Items[1].Authors[0].Name)
+}
+
+func Test_GetFeedErrors(t *testing.T) {
+	feedService := services.NewFeedService(repositories.NewQuoteRepositoryMock())
+
+	result := feedService.GetFeed("hot", models.FeedFormatAtom, feedQuery)
+	assert.Equal(t, 404, result.Code)
+	assert.Equal(t, "feed must be new or top", result.Message)
+
+	result = feedService.GetFeed(models.FeedKindNew, "xml", feedQuery)
+	assert.Equal(t, 404, result.Code)
+	assert.Equal(t, "feed format must be atom, rss or json", result.Message)
+}
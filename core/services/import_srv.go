@@ -0,0 +1,429 @@
+package services
+
+import (
+	"backend/config"
+	"backend/core/models"
+	"backend/core/repositories"
+	"backend/utils"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// importBatchSize is how many checked rows are stored with one insert.
+const importBatchSize = 100
+
+// maxImportLineLength bounds one NDJSON line, a quote is far shorter.
+const maxImportLineLength = 1 << 20
+
+var importExtensions = map[string]string{
+	".csv":    models.ImportFormatCSV,
+	".json":   models.ImportFormatJSON,
+	".ndjson": models.ImportFormatNDJSON,
+	".jsonl":  models.ImportFormatNDJSON,
+}
+
+var importContentTypes = map[string]string{
+	"text/csv":             models.ImportFormatCSV,
+	"application/json":     models.ImportFormatJSON,
+	"application/x-ndjson": models.ImportFormatNDJSON,
+	"application/jsonl":    models.ImportFormatNDJSON,
+}
+
+// importColumns are the CSV columns an import understands, tags are comma
+// separated inside their cell.
+var importColumns = []string{"quote", "author", "tags", "category_id", "language", "year", "source_type", "source_title", "source_url", "publish_at"}
+
+// importFormat is the format asked for, else the one the file name or the
+// content type gives away.
+func importFormat(format string, file models.UploadFileModel) (string, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		format = importExtensions[strings.ToLower(filepath.Ext(file.Filename))]
+	}
+	if format == "" {
+		if mediaType, _, err := mime.ParseMediaType(file.ContentType); err == nil {
+			format = importContentTypes[mediaType]
+		}
+	}
+	switch format {
+	case models.ImportFormatCSV, models.ImportFormatJSON, models.ImportFormatNDJSON:
+		return format, nil
+	}
+	return "", errors.New("format must be csv, json or ndjson")
+}
+
+// importRowError is a row that can not be read, the rows after it still can.
+type importRowError struct {
+	err error
+}
+
+func (e importRowError) Error() string {
+	return e.err.Error()
+}
+
+// importReader reads an upload one row at a time so a file is never held in
+// memory whole. next returns io.EOF after the last row, an importRowError for
+// a broken row and any other error when the rest of the file is unreadable.
+type importReader interface {
+	next() (models.HandCreateQuoteBodyModel, error)
+}
+
+func newImportReader(format string, r io.Reader) (importReader, error) {
+	switch format {
+	case models.ImportFormatCSV:
+		return newCSVImportReader(r)
+	case models.ImportFormatJSON:
+		return newJSONImportReader(r)
+	default:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), maxImportLineLength)
+		return &ndjsonImportReader{scanner: scanner}, nil
+	}
+}
+
+type csvImportReader struct {
+	reader  *csv.Reader
+	columns []string
+}
+
+func newCSVImportReader(r io.Reader) (*csvImportReader, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("csv header not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns := make([]string, len(header))
+	found := false
+	for i, column := range header {
+		column = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))
+		if !utils.StringInSlice(importColumns, column) {
+			return nil, fmt.Errorf("unknown column %q", column)
+		}
+		found = found || column == "quote"
+		columns[i] = column
+	}
+	if !found {
+		return nil, errors.New("quote column not found")
+	}
+	return &csvImportReader{reader: reader, columns: columns}, nil
+}
+
+func (r *csvImportReader) next() (body models.HandCreateQuoteBodyModel, err error) {
+	record, err := r.reader.Read()
+	if err == io.EOF {
+		return body, err
+	}
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return body, importRowError{err}
+	}
+	if err != nil {
+		return body, err
+	}
+	for i, value := range record {
+		switch r.columns[i] {
+		case "quote":
+			body.Quote = value
+		case "author":
+			body.Author = value
+		case "tags":
+			for _, tag := range strings.Split(value, ",") {
+				if tag = strings.TrimSpace(tag); tag != "" {
+					body.Tags = append(body.Tags, tag)
+				}
+			}
+		case "category_id":
+			body.CategoryID = strings.TrimSpace(value)
+		case "language":
+			body.Language = value
+		case "year":
+			if value = strings.TrimSpace(value); value != "" {
+				if body.Year, err = strconv.Atoi(value); err != nil {
+					return body, importRowError{errors.New("year must be a number")}
+				}
+			}
+		case "source_type":
+			body.Source.Type = value
+		case "source_title":
+			body.Source.Title = value
+		case "source_url":
+			body.Source.URL = value
+		case "publish_at":
+			body.PublishAt = value
+		}
+	}
+	return body, nil
+}
+
+type jsonImportReader struct {
+	decoder *json.Decoder
+}
+
+func newJSONImportReader(r io.Reader) (*jsonImportReader, error) {
+	decoder := json.NewDecoder(r)
+	token, err := decoder.Token()
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return nil, errors.New("json import must be an array of quotes")
+	}
+	return &jsonImportReader{decoder: decoder}, nil
+}
+
+func (r *jsonImportReader) next() (body models.HandCreateQuoteBodyModel, err error) {
+	if !r.decoder.More() {
+		if _, err := r.decoder.Token(); err != nil {
+			return body, err
+		}
+		return body, io.EOF
+	}
+	err = r.decoder.Decode(&body)
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		// the decoder has read past the whole element, only this row is lost
+		return body, importRowError{err}
+	}
+	return body, err
+}
+
+type ndjsonImportReader struct {
+	scanner *bufio.Scanner
+}
+
+func (r *ndjsonImportReader) next() (body models.HandCreateQuoteBodyModel, err error) {
+	for r.scanner.Scan() {
+		line := strings.TrimSpace(r.scanner.Text())
+		if line == "" {
+			continue
+		}
+		if err := json.Unmarshal([]byte(line), &body); err != nil {
+			return body, importRowError{err}
+		}
+		return body, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return body, err
+	}
+	return body, io.EOF
+}
+
+// quoteImport is one run of ImportQuotes. Rows that pass the checks wait in
+// batch until there are enough of them to store with one insert.
+type quoteImport struct {
+	s      *QuoteSrv
+	userID string
+	report models.ImportReportModel
+	batch  []importedQuote
+	// seen holds the rows read so far, so the file can not repeat a quote either
+	seen    repositories.DuplicateRepository
+	tags    map[string]bool
+	authors map[string]models.AuthorModel
+}
+
+type importedQuote struct {
+	row     int
+	payload models.CreateQuoteModel
+}
+
+func (run *quoteImport) mark(row int, status string, quoteID string, duplicateOf string, message string) {
+	res := &run.report.Rows[row-1]
+	res.Status = status
+	res.QuoteID = quoteID
+	res.DuplicateOf = duplicateOf
+	res.Message = message
+	switch status {
+	case models.ImportRowCreated:
+		run.report.Created++
+	case models.ImportRowSkipped:
+		run.report.Skipped++
+	default:
+		run.report.Failed++
+	}
+}
+
+// read adds the next row to the report, err is the reason it could not be read.
+func (run *quoteImport) read(body models.HandCreateQuoteBodyModel, err error) {
+	run.report.Total++
+	row := run.report.Total
+	run.report.Rows = append(run.report.Rows, models.ImportRowModel{Row: row})
+	if err != nil {
+		run.mark(row, models.ImportRowFailed, "", "", err.Error())
+		return
+	}
+	payload, failure, ok := run.s.draftQuote(run.userID, true, body)
+	if !ok {
+		if duplicate, isDuplicate := failure.Result.(models.DuplicateQuoteModel); isDuplicate {
+			run.mark(row, models.ImportRowSkipped, "", duplicate.Existing.ID, failure.Message)
+			return
+		}
+		run.mark(row, models.ImportRowFailed, "", "", failure.Message)
+		return
+	}
+	if matches, err := run.seen.FindSimilar(payload.Quote, config.Env.DuplicateSimilarity); err == nil && len(matches) > 0 {
+		run.mark(row, models.ImportRowSkipped, "", "", "quote already exist in row "+matches[0].ID)
+		return
+	}
+	run.seen.Index(strconv.Itoa(row), payload.Quote)
+	run.batch = append(run.batch, importedQuote{row: row, payload: payload})
+	if len(run.batch) >= importBatchSize {
+		run.flush()
+	}
+}
+
+// resolve is resolveQuote that remembers the tags and authors it has seen,
+// a file tends to repeat them on many rows.
+func (run *quoteImport) resolve(payload *models.CreateQuoteModel) error {
+	names := []string{}
+	for _, tag := range payload.Tags {
+		if !run.tags[tag] {
+			names = append(names, tag)
+		}
+	}
+	if err := ensureTags(run.s.tagRepo, names); err != nil {
+		return err
+	}
+	for _, name := range names {
+		run.tags[name] = true
+	}
+	if payload.Author == "" {
+		return nil
+	}
+	key := authorNameKey(payload.Author)
+	author, ok := run.authors[key]
+	if !ok {
+		var err error
+		if author, err = ensureAuthor(run.s.authorRepo, payload.Author); err != nil {
+			return err
+		}
+		run.authors[key] = author
+	}
+	payload.AuthorID, payload.Author = author.ID, author.Name
+	return nil
+}
+
+// flush stores the waiting rows, a dry run only reports them as created.
+func (run *quoteImport) flush() {
+	batch := run.batch
+	run.batch = nil
+	if run.report.DryRun {
+		for _, item := range batch {
+			run.mark(item.row, models.ImportRowCreated, "", "", "")
+		}
+		return
+	}
+	ready := []importedQuote{}
+	payloads := []models.CreateQuoteModel{}
+	for _, item := range batch {
+		if err := run.resolve(&item.payload); err != nil {
+			run.mark(item.row, models.ImportRowFailed, "", "", err.Error())
+			continue
+		}
+		ready = append(ready, item)
+		payloads = append(payloads, item.payload)
+	}
+	if len(payloads) == 0 {
+		return
+	}
+	inserted, err := run.s.quoteRepo.CreateQuotes(payloads)
+	ids := []string{}
+	for i, item := range ready {
+		if i < inserted {
+			run.mark(item.row, models.ImportRowCreated, item.payload.ID, "", "")
+			ids = append(ids, item.payload.ID)
+		} else {
+			run.mark(item.row, models.ImportRowFailed, "", "", err.Error())
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+	quotes, err := run.s.quoteRepo.GetQuotesByIDs(ids)
+	if err != nil {
+		return
+	}
+	for _, quote := range quotes {
+		run.s.index(quote)
+	}
+}
+
+// ImportQuotes creates quotes from a CSV, JSON or NDJSON file with the checks
+// of CreateQuote, quotes that already exist are skipped. The file is read as
+// a stream and stored in batches, a dry run reports without writing anything.
+func (s *QuoteSrv) ImportQuotes(userID string, format string, dryRun bool, file models.UploadFileModel) (result models.ResponseModel) {
+	format, err := importFormat(format, file)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if file.Content == nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "file not found",
+			Result:  nil,
+		}
+	}
+	rows, err := newImportReader(format, file.Content)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	run := &quoteImport{
+		s:      s,
+		userID: userID,
+		report: models.ImportReportModel{
+			Format: format,
+			DryRun: dryRun,
+			Rows:   []models.ImportRowModel{},
+		},
+		seen:    repositories.NewMemoryDuplicateRepository(),
+		tags:    map[string]bool{},
+		authors: map[string]models.AuthorModel{},
+	}
+	for {
+		body, err := rows.next()
+		if err == io.EOF {
+			break
+		}
+		var rowErr importRowError
+		if err != nil && !errors.As(err, &rowErr) {
+			// the rows before the broken part are kept, the report says which
+			run.flush()
+			return models.ResponseModel{
+				Status:  false,
+				Code:    400,
+				Message: fmt.Sprintf("row %d: %s", run.report.Total+1, err.Error()),
+				Result:  run.report,
+			}
+		}
+		run.read(body, err)
+	}
+	run.flush()
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "import quotes success",
+		Result:  run.report,
+	}
+}
@@ -0,0 +1,201 @@
+package services_test
+
+import (
+	"backend/core/models"
+	"backend/core/repositories"
+	"backend/core/services"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newImportService(quoteRepo repositories.QuoteRepository, tagRepo repositories.TagRepository, authorRepo repositories.AuthorRepository) services.QuoteService {
+	return services.NewQuoteService(quoteRepos(services.QuoteRepositories{Quote: quoteRepo, Tag: tagRepo, Author: authorRepo}))
+}
+
+func importFile(filename string, content string) models.UploadFileModel {
+	return models.UploadFileModel{Filename: filename, Size: int64(len(content)), Content: strings.NewReader(content)}
+}
+
+func Test_ImportQuotesCSV(t *testing.T) {
+	existing := models.QuoteModel{ID: "1", Quote: "Love the life you live, and live the life you love.", Status: models.QuoteStatusApproved}
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	quoteRepo.On("GetQuotes", mock.Anything).Return([]models.QuoteModel{existing}, nil)
+	quoteRepo.On("GetQuote", "1").Return(existing, nil)
+	quoteRepo.On("CreateQuotes", mock.MatchedBy(func(payloads []models.CreateQuoteModel) bool {
+		return len(payloads) == 2 &&
+			payloads[0].Quote == "Peace comes from within." && payloads[0].AuthorID == "a1" && payloads[0].Status == models.QuoteStatusApproved &&
+			payloads[1].Quote == "The mind is everything." && payloads[1].AuthorID == "a1" && payloads[1].Year == 1990
+	})).Return(2, nil)
+	quoteRepo.On("GetQuotesByIDs", mock.Anything).Return([]models.QuoteModel{}, nil)
+	tagRepo := repositories.NewTagRepositoryMock()
+	tagRepo.On("GetTagByName", "peace").Return(models.TagModel{}, errors.New("tag not found"))
+	tagRepo.On("CreateTag", mock.Anything).Return(models.TagModel{Name: "peace"}, nil)
+	authorRepo := repositories.NewAuthorRepositoryMock()
+	authorRepo.On("GetAuthorByNameKey", "buddha").Return(models.AuthorModel{ID: "a1", Name: "Buddha"}, nil)
+
+	quoteService := newImportService(quoteRepo, tagRepo, authorRepo)
+	assert.NoError(t, quoteService.ReindexQuotes())
+
+	csv := "quote,author,tags,year\n" +
+		"Peace comes from within.,Buddha,\"Peace, peace\",\n" +
+		"\"Love the life you live and live the life you love!\",,,\n" +
+		"peace comes from within,buddha,,\n" +
+		"The mind is everything.,Buddha,peace,1990\n" +
+		"Bad year,,,long ago\n" +
+		",,,\n"
+	result := quoteService.ImportQuotes("admin", "", false, importFile("quotes.csv", csv))
+	assert.Equal(t, "import quotes success", result.Message)
+	report := result.Result.(models.ImportReportModel)
+	assert.Equal(t, models.ImportFormatCSV, report.Format)
+	assert.Equal(t, 6, report.Total)
+	assert.Equal(t, 2, report.Created)
+	assert.Equal(t, 2, report.Skipped)
+	assert.Equal(t, 2, report.Failed)
+
+	statuses := []string{}
+	for _, row := range report.Rows {
+		statuses = append(statuses, row.Status)
+	}
+	assert.Equal(t, []string{"created", "skipped", "skipped", "created", "failed", "failed"}, statuses)
+	assert.NotEmpty(t, report.Rows[0].QuoteID)
+	assert.Equal(t, "1", report.Rows[1].DuplicateOf)
+	assert.Equal(t, "quote already exist in row 1", report.Rows[2].Message)
+	assert.Equal(t, "year must be a number", report.Rows[4].Message)
+	assert.Equal(t, "quote not found", report.Rows[5].Message)
+	// the tag and the author are looked up once for the whole file
+	tagRepo.AssertNumberOfCalls(t, "GetTagByName", 1)
+	authorRepo.AssertNumberOfCalls(t, "GetAuthorByNameKey", 1)
+}
+
+func Test_ImportQuotesDryRun(t *testing.T) {
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	quoteRepo.On("GetQuotes", mock.Anything).Return([]models.QuoteModel{}, nil)
+	tagRepo := repositories.NewTagRepositoryMock()
+	authorRepo := repositories.NewAuthorRepositoryMock()
+
+	quoteService := newImportService(quoteRepo, tagRepo, authorRepo)
+	assert.NoError(t, quoteService.ReindexQuotes())
+
+	body := `[
+		{"quote": "Peace comes from within.", "author": "Buddha", "tags": ["peace"]},
+		{"quote": "The mind is everything.", "year": "1990"},
+		{"quote": "Peace comes from within!"}
+	]`
+	result := quoteService.ImportQuotes("admin", "json", true, importFile("", body))
+	assert.Equal(t, "import quotes success", result.Message)
+	report := result.Result.(models.ImportReportModel)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 3, report.Total)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 1, report.Skipped)
+	assert.Equal(t, 1, report.Failed)
+	assert.Empty(t, report.Rows[0].QuoteID)
+	quoteRepo.AssertNotCalled(t, "CreateQuotes", mock.Anything)
+	tagRepo.AssertNotCalled(t, "CreateTag", mock.Anything)
+	authorRepo.AssertNotCalled(t, "GetAuthorByNameKey", mock.Anything)
+}
+
+// importWord spells n in letters, so every row of a generated file has words of its own.
+func importWord(n int) string {
+	word := ""
+	for n >= 0 {
+		word = string(rune('a'+n%26)) + word
+		n = n/26 - 1
+	}
+	return word
+}
+
+func Test_ImportQuotesNDJSONBatches(t *testing.T) {
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	quoteRepo.On("GetQuotes", mock.Anything).Return([]models.QuoteModel{}, nil)
+	quoteRepo.On("CreateQuotes", mock.MatchedBy(func(payloads []models.CreateQuoteModel) bool {
+		return len(payloads) == 100
+	})).Return(100, nil).Twice()
+	quoteRepo.On("CreateQuotes", mock.MatchedBy(func(payloads []models.CreateQuoteModel) bool {
+		return len(payloads) == 50
+	})).Return(20, errors.New("connection reset")).Once()
+	quoteRepo.On("GetQuotesByIDs", mock.Anything).Return([]models.QuoteModel{}, nil)
+
+	quoteService := newImportService(quoteRepo, repositories.NewTagRepositoryMock(), repositories.NewAuthorRepositoryMock())
+	assert.NoError(t, quoteService.ReindexQuotes())
+
+	lines := []string{}
+	for i := 0; i < 250; i++ {
+		words := []string{}
+		for k := 0; k < 6; k++ {
+			words = append(words, importWord(1000+i*6+k))
+		}
+		lines = append(lines, fmt.Sprintf(`{"quote": %q}`, strings.Join(words, " ")))
+		if i == 10 {
+			lines = append(lines, "", `{"quote": `)
+		}
+	}
+	result := quoteService.ImportQuotes("admin", "", false, importFile("quotes.ndjson", strings.Join(lines, "\n")))
+	assert.Equal(t, "import quotes success", result.Message)
+	report := result.Result.(models.ImportReportModel)
+	assert.Equal(t, 251, report.Total)
+	assert.Equal(t, 220, report.Created)
+	assert.Equal(t, 31, report.Failed)
+	assert.Equal(t, models.ImportRowFailed, report.Rows[11].Status)
+	assert.Equal(t, "connection reset", report.Rows[250].Message)
+	quoteRepo.AssertNumberOfCalls(t, "CreateQuotes", 3)
+}
+
+func Test_ImportQuotesErrors(t *testing.T) {
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	quoteRepo.On("GetQuotes", mock.Anything).Return([]models.QuoteModel{}, nil)
+	quoteRepo.On("CreateQuotes", mock.Anything).Return(1, nil)
+	quoteRepo.On("GetQuotesByIDs", mock.Anything).Return([]models.QuoteModel{}, nil)
+
+	quoteService := newImportService(quoteRepo, repositories.NewTagRepositoryMock(), repositories.NewAuthorRepositoryMock())
+	assert.NoError(t, quoteService.ReindexQuotes())
+
+	cases := []struct {
+		Name     string
+		Format   string
+		File     models.UploadFileModel
+		Expected string
+	}{
+		{
+			Name:     "unknown format",
+			File:     importFile("quotes.txt", "quote\nhello"),
+			Expected: "format must be csv, json or ndjson",
+		},
+		{
+			Name:     "unknown column",
+			File:     importFile("quotes.csv", "quote,mood\nhello,happy"),
+			Expected: `unknown column "mood"`,
+		},
+		{
+			Name:     "missing quote column",
+			File:     importFile("quotes.csv", "author\nBuddha"),
+			Expected: "quote column not found",
+		},
+		{
+			Name:     "json object",
+			Format:   "json",
+			File:     importFile("", `{"quote": "hello"}`),
+			Expected: "json import must be an array of quotes",
+		},
+		{
+			Name:     "broken json",
+			File:     importFile("quotes.json", `[{"quote": "hello there"}, {"quote": `),
+			Expected: "row 2: unexpected EOF",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			result := quoteService.ImportQuotes("admin", c.Format, false, c.File)
+			assert.Equal(t, 400, result.Code)
+			assert.Equal(t, c.Expected, result.Message)
+		})
+	}
+	// the rows before a broken part of the file are still stored
+	result := quoteService.ImportQuotes("admin", "", false, importFile("quotes.json", `[{"quote": "good morning"}, {"quote": `))
+	assert.Equal(t, 1, result.Result.(models.ImportReportModel).Created)
+}
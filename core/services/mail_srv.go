@@ -0,0 +1,183 @@
+package services
+
+import (
+	"backend/core/models"
+	"backend/core/repositories"
+	"backend/utils"
+	"bytes"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxMailSize is the largest message that is read, forwards with big
+// attachments are refused rather than held in memory.
+const maxMailSize = 10 * 1024 * 1024
+
+// mailAttribution is a last line naming who said the quote, "— Buddha".
+var mailAttribution = regexp.MustCompile(`^(?:[—–~]|-{1,2})\s*(\S.*)$`)
+
+type MailService interface {
+	IngestMail(raw io.Reader) (result models.ResponseModel)
+
+	IngestMaildir() (result models.ResponseModel)
+}
+
+type MailSrv struct {
+	quoteService QuoteService
+	userRepo     repositories.UserRepository
+	mailboxRepo  repositories.MailboxRepository
+}
+
+func NewMailService(quoteService QuoteService, userRepo repositories.UserRepository, mailboxRepo repositories.MailboxRepository) MailService {
+	return &MailSrv{
+		quoteService: quoteService,
+		userRepo:     userRepo,
+		mailboxRepo:  mailboxRepo,
+	}
+}
+
+// sender finds the user a mail comes from, addresses are stored as the user
+// typed them so a lower case lookup is the fallback.
+func (s *MailSrv) sender(address string) (user models.UserModel, err error) {
+	if address == "" {
+		return user, errors.New("sender not found")
+	}
+	user, err = s.userRepo.GetUser(address)
+	if err != nil && strings.ToLower(address) != address {
+		user, err = s.userRepo.GetUser(strings.ToLower(address))
+	}
+	return user, err
+}
+
+// mailQuote splits the text of a mail into the quote and the author named on
+// its last line, a one line mail is all quote.
+func mailQuote(text string) (quote string, author string) {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	if len(lines) < 2 {
+		return text, ""
+	}
+	last := strings.TrimSpace(lines[len(lines)-1])
+	match := mailAttribution.FindStringSubmatch(last)
+	if match == nil || utf8.RuneCountInString(match[1]) > maxAuthorNameLength {
+		return text, ""
+	}
+	return strings.Join(lines[:len(lines)-1], "\n"), match[1]
+}
+
+// IngestMail creates a quote from a raw RFC 822 message sent or forwarded by a
+// user. The From header is easy to forge so the quote always waits for a
+// moderator, even when the sender is trusted.
+func (s *MailSrv) IngestMail(raw io.Reader) (result models.ResponseModel) {
+	data, err := io.ReadAll(io.LimitReader(raw, maxMailSize+1))
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	_, result = s.ingest(data)
+	return result
+}
+
+// ingest also returns the sender of the mail for the Maildir report.
+func (s *MailSrv) ingest(data []byte) (from string, result models.ResponseModel) {
+	if len(data) > maxMailSize {
+		return "", models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "mail is too large",
+			Result:  nil,
+		}
+	}
+	mail, err := utils.ParseMail(bytes.NewReader(data))
+	if err != nil {
+		return "", models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "mail invalid: " + err.Error(),
+			Result:  nil,
+		}
+	}
+	user, err := s.sender(mail.From)
+	if err != nil {
+		return mail.From, models.ResponseModel{
+			Status:  false,
+			Code:    403,
+			Message: "sender is not a user",
+			Result:  nil,
+		}
+	}
+	if user.Suspended {
+		return mail.From, models.ResponseModel{
+			Status:  false,
+			Code:    403,
+			Message: "sender is suspended",
+			Result:  nil,
+		}
+	}
+	quote, author := mailQuote(utils.ExtractMailDescription(mail.Text))
+	return mail.From, s.quoteService.CreateQuote(user.ID, false, models.HandCreateQuoteBodyModel{
+		Quote:  quote,
+		Author: author,
+	})
+}
+
+// IngestMaildir creates quotes from the messages waiting in the Maildir. Each
+// message is claimed before it is read so instances sharing the Maildir never
+// read one twice, failed messages stay in cur flagged for an admin to look at.
+func (s *MailSrv) IngestMaildir() (result models.ResponseModel) {
+	names, err := s.mailboxRepo.List()
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    500,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	report := models.MailIngestReportModel{Rows: []models.MailIngestRowModel{}}
+	for _, name := range names {
+		raw, err := s.mailboxRepo.Claim(name)
+		if errors.Is(err, os.ErrNotExist) {
+			// another instance claimed it first
+			continue
+		}
+		row := models.MailIngestRowModel{File: name, Status: models.ImportRowCreated}
+		if err != nil {
+			row.Status, row.Message = models.ImportRowFailed, err.Error()
+		} else {
+			var res models.ResponseModel
+			row.From, res = s.ingest(raw)
+			if !res.Status {
+				row.Status, row.Message = models.ImportRowFailed, res.Message
+			} else if quote, ok := res.Result.(models.QuoteModel); ok {
+				row.QuoteID = quote.ID
+			}
+		}
+
+		report.Processed++
+		if row.Status == models.ImportRowFailed {
+			report.Failed++
+			log.Printf("ingest mail %s failed: %s", name, row.Message)
+		} else {
+			report.Created++
+		}
+		if err := s.mailboxRepo.Finish(name, row.Status == models.ImportRowFailed); err != nil {
+			log.Printf("finish mail %s failed: %v", name, err)
+		}
+		report.Rows = append(report.Rows, row)
+	}
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "ingest maildir success",
+		Result:  report,
+	}
+}
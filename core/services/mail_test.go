@@ -0,0 +1,163 @@
+package services_test
+
+import (
+	"backend/core/models"
+	"backend/core/repositories"
+	"backend/core/services"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func readMailFixture(t *testing.T, name string) []byte {
+	data, err := os.ReadFile(filepath.Join("testdata", "mail", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func Test_IngestMail(t *testing.T) {
+	cases := []struct {
+		Name   string
+		File   string
+		Sender string
+		Quote  string
+		Author string
+	}{
+		{
+			Name:   "outlook forward",
+			File:   "outlook_forward.eml",
+			Sender: "malee@example.com",
+			Quote:  "The best way to predict the future is to create it.",
+			Author: "Peter Drucker",
+		},
+		{
+			// the address is matched case insensitively, the signature and the attachment are dropped
+			Name:   "gmail forward",
+			File:   "gmail_forward.eml",
+			Sender: "john.doe@example.com",
+			Quote:  "Do what you can, with what you have, where you are.",
+			Author: "Theodore Roosevelt",
+		},
+		{
+			Name:   "thai outlook forward in windows-874",
+			File:   "outlook_thai.eml",
+			Sender: "malee@example.com",
+			Quote:  "น้ำขึ้นให้รีบตัก",
+			Author: "สุภาษิตไทย",
+		},
+		{
+			Name:   "outlook on the web html only",
+			File:   "html_only.eml",
+			Sender: "malee@example.com",
+			Quote:  "Patience is bitter, but its fruit is sweet.",
+			Author: "Jean-Jacques Rousseau",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			quoteRepo := repositories.NewQuoteRepositoryMock()
+			quoteRepo.On("CreateQuote", mock.MatchedBy(func(payload models.CreateQuoteModel) bool {
+				return payload.Quote == c.Quote && payload.Author == c.Author && payload.AuthorID == "a1" &&
+					payload.CreatedBy == "u1" && payload.Status == models.QuoteStatusPending
+			})).Return(models.QuoteModel{ID: "q1", Quote: c.Quote, Status: models.QuoteStatusPending}, nil)
+			authorRepo := repositories.NewAuthorRepositoryMock()
+			authorRepo.On("GetAuthorByNameKey", mock.Anything).Return(models.AuthorModel{}, errors.New("author not found"))
+			authorRepo.On("CreateAuthor", mock.MatchedBy(func(payload models.CreateAuthorModel) bool {
+				return payload.Name == c.Author
+			})).Return(models.AuthorModel{ID: "a1", Name: c.Author}, nil)
+			userRepo := repositories.NewUserRepositoryMock()
+			userRepo.On("GetUser", c.Sender).Return(models.UserModel{ID: "u1", Email: c.Sender, Trusted: true}, nil)
+			userRepo.On("GetUser", mock.Anything).Return(models.UserModel{}, errors.New("mongo: no documents in result"))
+
+			mailService := services.NewMailService(newImportService(quoteRepo, repositories.NewTagRepositoryMock(), authorRepo), userRepo, repositories.NewMailboxRepositoryMock())
+			result := mailService.IngestMail(strings.NewReader(string(readMailFixture(t, c.File))))
+			assert.Equal(t, "create quote success", result.Message)
+			assert.Equal(t, 201, result.Code)
+			quoteRepo.AssertNumberOfCalls(t, "CreateQuote", 1)
+		})
+	}
+}
+
+func Test_IngestMailErrors(t *testing.T) {
+	userRepo := repositories.NewUserRepositoryMock()
+	userRepo.On("GetUser", "malee@example.com").Return(models.UserModel{ID: "u1", Suspended: true}, nil)
+	userRepo.On("GetUser", mock.Anything).Return(models.UserModel{}, errors.New("mongo: no documents in result"))
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	mailService := services.NewMailService(newImportService(quoteRepo, repositories.NewTagRepositoryMock(), repositories.NewAuthorRepositoryMock()), userRepo, repositories.NewMailboxRepositoryMock())
+
+	cases := []struct {
+		Name     string
+		Mail     string
+		Code     int
+		Expected string
+	}{
+		{
+			Name:     "unknown sender",
+			Mail:     string(readMailFixture(t, "gmail_forward.eml")),
+			Code:     403,
+			Expected: "sender is not a user",
+		},
+		{
+			Name:     "suspended sender",
+			Mail:     string(readMailFixture(t, "outlook_forward.eml")),
+			Code:     403,
+			Expected: "sender is suspended",
+		},
+		{
+			Name:     "no from header",
+			Mail:     "Subject: hello\r\n\r\nbody\r\n",
+			Code:     400,
+			Expected: "mail invalid: from address invalid",
+		},
+		{
+			Name:     "too large",
+			Mail:     "From: a@example.com\r\n\r\n" + strings.Repeat("a", 10*1024*1024),
+			Code:     400,
+			Expected: "mail is too large",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			result := mailService.IngestMail(strings.NewReader(c.Mail))
+			assert.Equal(t, c.Code, result.Code)
+			assert.Equal(t, c.Expected, result.Message)
+		})
+	}
+	quoteRepo.AssertNotCalled(t, "CreateQuote", mock.Anything)
+}
+
+func Test_IngestMaildir(t *testing.T) {
+	mailboxRepo := repositories.NewMailboxRepositoryMock()
+	mailboxRepo.On("List").Return([]string{"1715000000.1.host", "1715000001.2.host", "1715000002.3.host"}, nil)
+	mailboxRepo.On("Claim", "1715000000.1.host").Return(readMailFixture(t, "outlook_thai.eml"), nil)
+	// another instance got to it first
+	mailboxRepo.On("Claim", "1715000001.2.host").Return([]byte(nil), os.ErrNotExist)
+	mailboxRepo.On("Claim", "1715000002.3.host").Return(readMailFixture(t, "gmail_forward.eml"), nil)
+	mailboxRepo.On("Finish", "1715000000.1.host", false).Return(nil)
+	mailboxRepo.On("Finish", "1715000002.3.host", true).Return(nil)
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	quoteRepo.On("CreateQuote", mock.Anything).Return(models.QuoteModel{ID: "q1", Status: models.QuoteStatusPending}, nil)
+	authorRepo := repositories.NewAuthorRepositoryMock()
+	authorRepo.On("GetAuthorByNameKey", mock.Anything).Return(models.AuthorModel{ID: "a1", Name: "สุภาษิตไทย"}, nil)
+	userRepo := repositories.NewUserRepositoryMock()
+	userRepo.On("GetUser", "malee@example.com").Return(models.UserModel{ID: "u1"}, nil)
+	userRepo.On("GetUser", mock.Anything).Return(models.UserModel{}, errors.New("mongo: no documents in result"))
+
+	mailService := services.NewMailService(newImportService(quoteRepo, repositories.NewTagRepositoryMock(), authorRepo), userRepo, mailboxRepo)
+	result := mailService.IngestMaildir()
+	assert.Equal(t, "ingest maildir success", result.Message)
+	report := result.Result.(models.MailIngestReportModel)
+	assert.Equal(t, 2, report.Processed)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 1, report.Failed)
+	assert.Equal(t, models.MailIngestRowModel{File: "1715000000.1.host", From: "malee@example.com", Status: models.ImportRowCreated, QuoteID: "q1"}, report.Rows[0])
+	assert.Equal(t, models.MailIngestRowModel{File: "1715000002.3.host", From: "John.Doe@Example.com", Status: models.ImportRowFailed, Message: "sender is not a user"}, report.Rows[1])
+	mailboxRepo.AssertExpectations(t)
+}
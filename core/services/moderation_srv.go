@@ -0,0 +1,523 @@
+package services
+
+import (
+	"backend/config"
+	"backend/core/models"
+	"backend/core/repositories"
+	"backend/utils"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxRejectReasonLength = 500
+	maxReportTextLength   = 1000
+)
+
+// publishLease guards PublishScheduledQuotes when several instances run, it
+// outlives a run so a crashed instance frees it on its own.
+const (
+	publishLease    = "publish_scheduled_quotes"
+	publishLeaseTTL = 5 * time.Minute
+)
+
+var reportReasons = []string{
+	models.ReportReasonSpam,
+	models.ReportReasonOffensive,
+	models.ReportReasonMisattributed,
+	models.ReportReasonCopyright,
+	models.ReportReasonOther,
+}
+
+type ModerationService interface {
+	GetQueue(page int, limit int) (result models.ResponseModel)
+
+	ApproveQuote(moderatorID string, id string) (result models.ResponseModel)
+
+	RejectQuote(moderatorID string, id string, reason string) (result models.ResponseModel)
+
+	ReportQuote(userID string, id string, body models.HandReportQuoteBodyModel) (result models.ResponseModel)
+
+	GetReports(page int, limit int) (result models.ResponseModel)
+
+	DismissReports(moderatorID string, id string) (result models.ResponseModel)
+
+	ActOnReports(moderatorID string, id string, body models.HandActOnReportsBodyModel) (result models.ResponseModel)
+
+	PublishScheduledQuotes() (result models.ResponseModel)
+}
+
+type ModerationSrv struct {
+	quoteRepo        repositories.QuoteRepository
+	searchRepo       repositories.SearchRepository
+	duplicateRepo    repositories.DuplicateRepository
+	notificationRepo repositories.NotificationRepository
+	reportRepo       repositories.ReportRepository
+	userRepo         repositories.UserRepository
+	leaseRepo        repositories.LeaseRepository
+	instanceID       string
+}
+
+func NewModerationService(quoteRepo repositories.QuoteRepository, searchRepo repositories.SearchRepository, duplicateRepo repositories.DuplicateRepository, notificationRepo repositories.NotificationRepository, reportRepo repositories.ReportRepository, userRepo repositories.UserRepository, leaseRepo repositories.LeaseRepository) ModerationService {
+	return &ModerationSrv{
+		quoteRepo:        quoteRepo,
+		searchRepo:       searchRepo,
+		duplicateRepo:    duplicateRepo,
+		notificationRepo: notificationRepo,
+		reportRepo:       reportRepo,
+		userRepo:         userRepo,
+		leaseRepo:        leaseRepo,
+		instanceID:       uuid.New().String(),
+	}
+}
+
+func rejectReason(reason string) (string, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return "", errors.New("reason not found")
+	}
+	if utf8.RuneCountInString(reason) > maxRejectReasonLength {
+		return "", fmt.Errorf("reason must be <= %d characters", maxRejectReasonLength)
+	}
+	return reason, nil
+}
+
+// notify tells userID about something that happened to one of their quotes,
+// a failed notification never fails the action that caused it.
+func notify(notificationRepo repositories.NotificationRepository, userID string, kind string, quoteID string, message string) {
+	if userID == "" {
+		return
+	}
+	err := notificationRepo.CreateNotification(models.CreateNotificationModel{
+		ID:         uuid.New().String(),
+		UserID:     userID,
+		Type:       kind,
+		QuoteID:    quoteID,
+		Message:    message,
+		CreateDate: time.Now(),
+	})
+	if err != nil {
+		log.Printf("notify user %s failed: %v", userID, err)
+	}
+}
+
+func (s *ModerationSrv) GetQueue(page int, limit int) (result models.ResponseModel) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultPageLimit
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+	res, total, err := s.quoteRepo.GetModerationQueue(page, limit)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if res == nil {
+		res = []models.QuoteModel{}
+	}
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "get moderation queue success",
+		Result: models.ModerationQueueModel{
+			Quotes: res,
+			Total:  total,
+			Page:   page,
+			Limit:  limit,
+		},
+	}
+}
+
+func (s *ModerationSrv) ApproveQuote(moderatorID string, id string) (result models.ResponseModel) {
+	res, err := s.quoteRepo.ModerateQuote(id, models.QuoteStatusApproved, moderatorID, "")
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    404,
+			Message: "quote not found in moderation queue",
+			Result:  nil,
+		}
+	}
+	indexQuote(s.searchRepo, s.duplicateRepo, res)
+	s.resolveReports(res.ID, models.ReportStatusDismissed, moderatorID)
+	message := "your quote was approved"
+	if res.Status == models.QuoteStatusScheduled && res.PublishAt != nil {
+		message += ", it will be published at " + res.PublishAt.Format(time.RFC3339)
+	}
+	notify(s.notificationRepo, res.CreatedBy, models.NotificationQuoteApproved, res.ID, message)
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "approve quote success",
+		Result:  res,
+	}
+}
+
+func (s *ModerationSrv) RejectQuote(moderatorID string, id string, reason string) (result models.ResponseModel) {
+	reason, err := rejectReason(reason)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	res, err := s.quoteRepo.ModerateQuote(id, models.QuoteStatusRejected, moderatorID, reason)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    404,
+			Message: "quote not found in moderation queue",
+			Result:  nil,
+		}
+	}
+	indexQuote(s.searchRepo, s.duplicateRepo, res)
+	s.resolveReports(res.ID, models.ReportStatusActioned, moderatorID)
+	notify(s.notificationRepo, res.CreatedBy, models.NotificationQuoteRejected, res.ID, "your quote was rejected: "+reason)
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "reject quote success",
+		Result:  res,
+	}
+}
+
+// resolveReports closes the open reports of a quote a moderator has just decided on.
+func (s *ModerationSrv) resolveReports(id string, status string, moderatorID string) {
+	if _, err := s.reportRepo.ResolveReports(id, status, moderatorID); err != nil {
+		log.Printf("resolve reports of quote %s failed: %v", id, err)
+	}
+}
+
+// ReportQuote records a report, a quote reported by ReportHideThreshold users
+// is hidden in the moderation queue until a moderator reviews it.
+func (s *ModerationSrv) ReportQuote(userID string, id string, body models.HandReportQuoteBodyModel) (result models.ResponseModel) {
+	body.Reason = strings.ToLower(strings.TrimSpace(body.Reason))
+	body.Text = strings.TrimSpace(body.Text)
+	if !utils.StringInSlice(reportReasons, body.Reason) {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "reason must be one of " + strings.Join(reportReasons, ", "),
+			Result:  nil,
+		}
+	}
+	if body.Reason == models.ReportReasonOther && body.Text == "" {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "text not found",
+			Result:  nil,
+		}
+	}
+	if utf8.RuneCountInString(body.Text) > maxReportTextLength {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: fmt.Sprintf("text must be <= %d characters", maxReportTextLength),
+			Result:  nil,
+		}
+	}
+	quote, err := s.quoteRepo.GetQuote(id)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    404,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if !quoteApproved(quote) {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "quote not approved",
+			Result:  nil,
+		}
+	}
+	payload := models.CreateReportModel{
+		ID:         uuid.New().String(),
+		QuoteID:    quote.ID,
+		UserID:     userID,
+		Reason:     body.Reason,
+		Text:       body.Text,
+		Status:     models.ReportStatusOpen,
+		CreateDate: time.Now(),
+	}
+	created, err := s.reportRepo.CreateReport(payload)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if !created {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    409,
+			Message: "quote already reported",
+			Result:  nil,
+		}
+	}
+	if config.Env.ReportHideThreshold > 0 {
+		count, err := s.reportRepo.CountOpenReports(quote.ID)
+		if err != nil {
+			log.Printf("count reports of quote %s failed: %v", quote.ID, err)
+		} else if count >= int64(config.Env.ReportHideThreshold) {
+			hidden, err := s.quoteRepo.HideQuote(quote.ID, fmt.Sprintf("reported by %d users", count))
+			if err == nil {
+				indexQuote(s.searchRepo, s.duplicateRepo, hidden)
+				notify(s.notificationRepo, hidden.CreatedBy, models.NotificationQuoteHidden, hidden.ID, "your quote was hidden until a moderator reviews the reports")
+			}
+		}
+	}
+	return models.ResponseModel{
+		Status:  true,
+		Code:    201,
+		Message: "report quote success",
+		Result:  payload,
+	}
+}
+
+func (s *ModerationSrv) GetReports(page int, limit int) (result models.ResponseModel) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultPageLimit
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+	groups, total, err := s.reportRepo.GetReportGroups(page, limit)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	ids := make([]string, len(groups))
+	for i, group := range groups {
+		ids[i] = group.QuoteID
+	}
+	quotes, err := s.quoteRepo.GetQuotesByIDs(ids)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	byID := map[string]models.QuoteModel{}
+	for _, quote := range quotes {
+		byID[quote.ID] = quote
+	}
+	res := make([]models.ReportedQuoteModel, 0, len(groups))
+	for _, group := range groups {
+		quote, ok := byID[group.QuoteID]
+		if !ok {
+			// the quote was deleted after it was reported
+			quote = models.QuoteModel{ID: group.QuoteID}
+		}
+		reasons := map[string]int{}
+		for _, report := range group.Reports {
+			reasons[report.Reason]++
+		}
+		res = append(res, models.ReportedQuoteModel{
+			Quote:        quote,
+			Count:        group.Count,
+			Reasons:      reasons,
+			LastReported: group.LastReported,
+			Reports:      group.Reports,
+		})
+	}
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "get reports success",
+		Result: models.ReportListModel{
+			Quotes: res,
+			Total:  total,
+			Page:   page,
+			Limit:  limit,
+		},
+	}
+}
+
+// DismissReports closes the reports of a quote without action and shows the
+// quote again if the reports had hidden it.
+func (s *ModerationSrv) DismissReports(moderatorID string, id string) (result models.ResponseModel) {
+	count, err := s.reportRepo.ResolveReports(id, models.ReportStatusDismissed, moderatorID)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if count == 0 {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    404,
+			Message: "reports not found",
+			Result:  nil,
+		}
+	}
+	quote, err := s.quoteRepo.GetQuote(id)
+	if err == nil && quote.Status == models.QuoteStatusPending {
+		if quote, err = s.quoteRepo.ModerateQuote(id, models.QuoteStatusApproved, moderatorID, ""); err == nil {
+			indexQuote(s.searchRepo, s.duplicateRepo, quote)
+		}
+	}
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "dismiss reports success",
+		Result:  count,
+	}
+}
+
+// ActOnReports rejects or deletes a reported quote and closes its reports.
+func (s *ModerationSrv) ActOnReports(moderatorID string, id string, body models.HandActOnReportsBodyModel) (result models.ResponseModel) {
+	if body.Action != models.ReportActionReject && body.Action != models.ReportActionDelete {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "action must be reject or delete",
+			Result:  nil,
+		}
+	}
+	reason, err := rejectReason(body.Reason)
+	if err != nil && body.Action == models.ReportActionReject {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	count, err := s.reportRepo.CountOpenReports(id)
+	if err == nil && count == 0 {
+		err = errors.New("reports not found")
+	}
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    404,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if body.Action == models.ReportActionReject {
+		// a quote under the threshold is still approved, it has to be hidden before it can be rejected
+		s.quoteRepo.HideQuote(id, "reported")
+		quote, err := s.quoteRepo.ModerateQuote(id, models.QuoteStatusRejected, moderatorID, reason)
+		if err != nil {
+			return models.ResponseModel{
+				Status:  false,
+				Code:    400,
+				Message: err.Error(),
+				Result:  nil,
+			}
+		}
+		indexQuote(s.searchRepo, s.duplicateRepo, quote)
+		notify(s.notificationRepo, quote.CreatedBy, models.NotificationQuoteRejected, quote.ID, "your quote was rejected: "+reason)
+	} else {
+		if _, err := s.quoteRepo.SoftDeleteQuote(id, moderatorID); err != nil {
+			return models.ResponseModel{
+				Status:  false,
+				Code:    400,
+				Message: err.Error(),
+				Result:  nil,
+			}
+		}
+		if _, err := s.userRepo.ClearVotes(id); err != nil {
+			log.Printf("release votes of quote %s failed: %v", id, err)
+		}
+		s.searchRepo.Remove(id)
+		s.duplicateRepo.Remove(id)
+	}
+	s.resolveReports(id, models.ReportStatusActioned, moderatorID)
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "act on reports success",
+		Result:  count,
+	}
+}
+
+// PublishScheduledQuotes publishes the scheduled quotes that are due and tells
+// their submitters. Only the instance holding the lease does the work.
+func (s *ModerationSrv) PublishScheduledQuotes() (result models.ResponseModel) {
+	acquired, err := s.leaseRepo.AcquireLease(publishLease, s.instanceID, publishLeaseTTL)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    500,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if !acquired {
+		return models.ResponseModel{
+			Status:  true,
+			Code:    200,
+			Message: "publish scheduled quotes running elsewhere",
+			Result:  0,
+		}
+	}
+	defer func() {
+		if err := s.leaseRepo.ReleaseLease(publishLease, s.instanceID); err != nil {
+			log.Printf("release lease %s failed: %v", publishLease, err)
+		}
+	}()
+
+	quotes, err := s.quoteRepo.GetDueQuotes(time.Now())
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    500,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	published := 0
+	for _, quote := range quotes {
+		res, err := s.quoteRepo.PublishQuote(quote.ID)
+		if err != nil {
+			log.Printf("publish quote %s failed: %v", quote.ID, err)
+			continue
+		}
+		indexQuote(s.searchRepo, s.duplicateRepo, res)
+		notify(s.notificationRepo, res.CreatedBy, models.NotificationQuotePublished, res.ID, "your quote was published")
+		log.Printf("quote %s published", res.ID)
+		published++
+	}
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "publish scheduled quotes success",
+		Result:  published,
+	}
+}
@@ -0,0 +1,203 @@
+package services_test
+
+import (
+	"backend/config"
+	"backend/core/models"
+	"backend/core/repositories"
+	"backend/core/services"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_CreateQuotePending(t *testing.T) {
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	quoteRepo.On("CreateQuote", mock.MatchedBy(func(payload models.CreateQuoteModel) bool {
+		return payload.Status == models.QuoteStatusPending
+	})).Return(models.QuoteModel{ID: "1", Quote: "pending quote", Status: models.QuoteStatusPending}, nil)
+	quoteRepo.On("CreateQuote", mock.MatchedBy(func(payload models.CreateQuoteModel) bool {
+		return payload.Status == models.QuoteStatusApproved
+	})).Return(models.QuoteModel{ID: "2", Quote: "trusted quote", Status: models.QuoteStatusApproved}, nil)
+
+	searchRepo := repositories.NewMemorySearchRepository()
+	quoteService := services.NewQuoteService(quoteRepos(services.QuoteRepositories{Quote: quoteRepo, Search: searchRepo}))
+	result := quoteService.CreateQuote("user", false, models.HandCreateQuoteBodyModel{Quote: "pending quote"})
+	assert.Equal(t, models.QuoteStatusPending, result.Result.(models.QuoteModel).Status)
+
+	result = quoteService.CreateQuote("trusted", true, models.HandCreateQuoteBodyModel{Quote: "trusted quote"})
+	assert.Equal(t, models.QuoteStatusApproved, result.Result.(models.QuoteModel).Status)
+
+	hits, _ := searchRepo.Search("quote", 10)
+	assert.Len(t, hits, 1)
+	assert.Equal(t, "2", hits[0].ID)
+}
+
+func Test_ModerateQuote(t *testing.T) {
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	notificationRepo := repositories.NewNotificationRepositoryMock()
+	quoteRepo.On("ModerateQuote", "1", models.QuoteStatusApproved, "mod", "").Return(models.QuoteModel{ID: "1", Quote: "quote", Status: models.QuoteStatusApproved, CreatedBy: "user"}, nil)
+	quoteRepo.On("ModerateQuote", "2", models.QuoteStatusRejected, "mod", "spam").Return(models.QuoteModel{ID: "2", Quote: "spam", Status: models.QuoteStatusRejected, CreatedBy: "user"}, nil)
+	quoteRepo.On("ModerateQuote", "3", mock.Anything, mock.Anything, mock.Anything).Return(models.QuoteModel{}, errors.New("mongo: no documents in result"))
+	notificationRepo.On("CreateNotification", mock.Anything).Return(nil)
+	reportRepo := repositories.NewReportRepositoryMock()
+	reportRepo.On("ResolveReports", mock.Anything, mock.Anything, "mod").Return(int64(0), nil)
+
+	searchRepo := repositories.NewMemorySearchRepository()
+	moderationService := services.NewModerationService(quoteRepo, searchRepo, repositories.NewMemoryDuplicateRepository(), notificationRepo, reportRepo, repositories.NewUserRepositoryMock(), repositories.NewLeaseRepositoryMock())
+
+	result := moderationService.ApproveQuote("mod", "1")
+	assert.Equal(t, "approve quote success", result.Message)
+	hits, _ := searchRepo.Search("quote", 10)
+	assert.Len(t, hits, 1)
+	notificationRepo.AssertCalled(t, "CreateNotification", mock.MatchedBy(func(n models.CreateNotificationModel) bool {
+		return n.UserID == "user" && n.Type == models.NotificationQuoteApproved && n.QuoteID == "1"
+	}))
+
+	result = moderationService.RejectQuote("mod", "2", " spam ")
+	assert.Equal(t, "reject quote success", result.Message)
+	notificationRepo.AssertCalled(t, "CreateNotification", mock.MatchedBy(func(n models.CreateNotificationModel) bool {
+		return n.Type == models.NotificationQuoteRejected && n.Message == "your quote was rejected: spam"
+	}))
+
+	result = moderationService.RejectQuote("mod", "2", " ")
+	assert.Equal(t, "reason not found", result.Message)
+
+	result = moderationService.ApproveQuote("mod", "3")
+	assert.Equal(t, 404, result.Code)
+	notificationRepo.AssertNumberOfCalls(t, "CreateNotification", 2)
+}
+
+func Test_ReportQuote(t *testing.T) {
+	config.Env.ReportHideThreshold = 2
+	defer func() { config.Env.ReportHideThreshold = 5 }()
+
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	reportRepo := repositories.NewReportRepositoryMock()
+	notificationRepo := repositories.NewNotificationRepositoryMock()
+	quoteRepo.On("GetQuote", "1").Return(models.QuoteModel{ID: "1", Quote: "quote", Status: models.QuoteStatusApproved, CreatedBy: "owner"}, nil)
+	quoteRepo.On("GetQuote", "pending").Return(models.QuoteModel{ID: "pending", Status: models.QuoteStatusPending}, nil)
+	quoteRepo.On("HideQuote", "1", "reported by 2 users").Return(models.QuoteModel{ID: "1", Quote: "quote", Status: models.QuoteStatusPending, CreatedBy: "owner"}, nil)
+	reportRepo.On("CreateReport", mock.MatchedBy(func(report models.CreateReportModel) bool {
+		return report.UserID == "first"
+	})).Return(true, nil)
+	reportRepo.On("CreateReport", mock.MatchedBy(func(report models.CreateReportModel) bool {
+		return report.UserID == "second"
+	})).Return(true, nil).Once()
+	reportRepo.On("CreateReport", mock.MatchedBy(func(report models.CreateReportModel) bool {
+		return report.UserID == "second"
+	})).Return(false, nil)
+	reportRepo.On("CountOpenReports", "1").Return(int64(1), nil).Once()
+	reportRepo.On("CountOpenReports", "1").Return(int64(2), nil)
+	notificationRepo.On("CreateNotification", mock.Anything).Return(nil)
+
+	searchRepo := repositories.NewMemorySearchRepository()
+	searchRepo.Index("1", "quote")
+	moderationService := services.NewModerationService(quoteRepo, searchRepo, repositories.NewMemoryDuplicateRepository(), notificationRepo, reportRepo, repositories.NewUserRepositoryMock(), repositories.NewLeaseRepositoryMock())
+
+	result := moderationService.ReportQuote("first", "1", models.HandReportQuoteBodyModel{Reason: "Spam"})
+	assert.Equal(t, "report quote success", result.Message)
+	quoteRepo.AssertNotCalled(t, "HideQuote", mock.Anything, mock.Anything)
+
+	result = moderationService.ReportQuote("second", "1", models.HandReportQuoteBodyModel{Reason: "offensive", Text: "rude"})
+	assert.Equal(t, "report quote success", result.Message)
+	hits, _ := searchRepo.Search("quote", 10)
+	assert.Empty(t, hits)
+	notificationRepo.AssertCalled(t, "CreateNotification", mock.MatchedBy(func(n models.CreateNotificationModel) bool {
+		return n.UserID == "owner" && n.Type == models.NotificationQuoteHidden
+	}))
+
+	result = moderationService.ReportQuote("second", "1", models.HandReportQuoteBodyModel{Reason: "offensive"})
+	assert.Equal(t, "quote already reported", result.Message)
+
+	result = moderationService.ReportQuote("first", "1", models.HandReportQuoteBodyModel{Reason: "other"})
+	assert.Equal(t, "text not found", result.Message)
+
+	result = moderationService.ReportQuote("first", "1", models.HandReportQuoteBodyModel{Reason: "boring"})
+	assert.Equal(t, 400, result.Code)
+
+	result = moderationService.ReportQuote("first", "pending", models.HandReportQuoteBodyModel{Reason: "spam"})
+	assert.Equal(t, "quote not approved", result.Message)
+}
+
+func Test_GetReports(t *testing.T) {
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	reportRepo := repositories.NewReportRepositoryMock()
+	reportRepo.On("GetReportGroups", 1, 20).Return([]models.ReportGroupModel{
+		{QuoteID: "1", Count: 3, Reports: []models.ReportModel{{Reason: "spam"}, {Reason: "spam"}, {Reason: "other"}}},
+		{QuoteID: "gone", Count: 1, Reports: []models.ReportModel{{Reason: "offensive"}}},
+	}, int64(2), nil)
+	quoteRepo.On("GetQuotesByIDs", []string{"1", "gone"}).Return([]models.QuoteModel{{ID: "1", Quote: "quote"}}, nil)
+
+	moderationService := services.NewModerationService(quoteRepo, repositories.NewMemorySearchRepository(), repositories.NewMemoryDuplicateRepository(), repositories.NewNotificationRepositoryMock(), reportRepo, repositories.NewUserRepositoryMock(), repositories.NewLeaseRepositoryMock())
+	result := moderationService.GetReports(0, 0)
+	assert.Equal(t, "get reports success", result.Message)
+	list := result.Result.(models.ReportListModel)
+	assert.Len(t, list.Quotes, 2)
+	assert.Equal(t, "quote", list.Quotes[0].Quote.Quote)
+	assert.Equal(t, map[string]int{"spam": 2, "other": 1}, list.Quotes[0].Reasons)
+	assert.Equal(t, "gone", list.Quotes[1].Quote.ID)
+}
+
+func Test_ResolveReports(t *testing.T) {
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	reportRepo := repositories.NewReportRepositoryMock()
+	userRepo := repositories.NewUserRepositoryMock()
+	notificationRepo := repositories.NewNotificationRepositoryMock()
+	quoteRepo.On("GetQuote", "hidden").Return(models.QuoteModel{ID: "hidden", Status: models.QuoteStatusPending}, nil)
+	quoteRepo.On("ModerateQuote", "hidden", models.QuoteStatusApproved, "mod", "").Return(models.QuoteModel{ID: "hidden", Quote: "hidden quote", Status: models.QuoteStatusApproved}, nil)
+	quoteRepo.On("HideQuote", "bad", "reported").Return(models.QuoteModel{ID: "bad", Status: models.QuoteStatusPending}, nil)
+	quoteRepo.On("ModerateQuote", "bad", models.QuoteStatusRejected, "mod", "hate speech").Return(models.QuoteModel{ID: "bad", Status: models.QuoteStatusRejected, CreatedBy: "owner"}, nil)
+	quoteRepo.On("SoftDeleteQuote", "spam", "mod").Return(models.QuoteModel{ID: "spam"}, nil)
+	userRepo.On("ClearVotes", "spam").Return(int64(0), nil)
+	reportRepo.On("ResolveReports", "hidden", models.ReportStatusDismissed, "mod").Return(int64(2), nil)
+	reportRepo.On("ResolveReports", "none", models.ReportStatusDismissed, "mod").Return(int64(0), nil)
+	reportRepo.On("ResolveReports", mock.Anything, models.ReportStatusActioned, "mod").Return(int64(1), nil)
+	reportRepo.On("CountOpenReports", "bad").Return(int64(1), nil)
+	reportRepo.On("CountOpenReports", "spam").Return(int64(1), nil)
+	reportRepo.On("CountOpenReports", "none").Return(int64(0), nil)
+	notificationRepo.On("CreateNotification", mock.Anything).Return(nil)
+
+	searchRepo := repositories.NewMemorySearchRepository()
+	moderationService := services.NewModerationService(quoteRepo, searchRepo, repositories.NewMemoryDuplicateRepository(), notificationRepo, reportRepo, userRepo, repositories.NewLeaseRepositoryMock())
+
+	result := moderationService.DismissReports("mod", "hidden")
+	assert.Equal(t, "dismiss reports success", result.Message)
+	hits, _ := searchRepo.Search("hidden", 10)
+	assert.Len(t, hits, 1)
+
+	result = moderationService.DismissReports("mod", "none")
+	assert.Equal(t, 404, result.Code)
+
+	result = moderationService.ActOnReports("mod", "bad", models.HandActOnReportsBodyModel{Action: models.ReportActionReject})
+	assert.Equal(t, "reason not found", result.Message)
+
+	result = moderationService.ActOnReports("mod", "bad", models.HandActOnReportsBodyModel{Action: models.ReportActionReject, Reason: "hate speech"})
+	assert.Equal(t, "act on reports success", result.Message)
+
+	result = moderationService.ActOnReports("mod", "spam", models.HandActOnReportsBodyModel{Action: models.ReportActionDelete})
+	assert.Equal(t, "act on reports success", result.Message)
+	userRepo.AssertCalled(t, "ClearVotes", "spam")
+
+	result = moderationService.ActOnReports("mod", "none", models.HandActOnReportsBodyModel{Action: models.ReportActionDelete})
+	assert.Equal(t, "reports not found", result.Message)
+
+	result = moderationService.ActOnReports("mod", "bad", models.HandActOnReportsBodyModel{Action: "ban"})
+	assert.Equal(t, 400, result.Code)
+}
+
+func Test_GetNotifications(t *testing.T) {
+	notificationRepo := repositories.NewNotificationRepositoryMock()
+	notificationRepo.On("GetNotifications", "user", 1, 20).Return([]models.NotificationModel{{ID: "1", UserID: "user"}}, int64(1), int64(1), nil)
+	notificationRepo.On("MarkNotificationsRead", "user").Return(nil)
+
+	notificationService := services.NewNotificationService(notificationRepo)
+	result := notificationService.GetNotifications("user", 0, 0)
+	assert.Equal(t, "get notifications success", result.Message)
+	list := result.Result.(models.NotificationListModel)
+	assert.Equal(t, int64(1), list.Unread)
+
+	result = notificationService.MarkNotificationsRead("user")
+	assert.Equal(t, "mark notifications read success", result.Message)
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"backend/core/models"
+	"backend/core/repositories"
+)
+
+type NotificationService interface {
+	GetNotifications(userID string, page int, limit int) (result models.ResponseModel)
+
+	MarkNotificationsRead(userID string) (result models.ResponseModel)
+}
+
+type NotificationSrv struct {
+	notificationRepo repositories.NotificationRepository
+}
+
+func NewNotificationService(notificationRepo repositories.NotificationRepository) NotificationService {
+	return &NotificationSrv{
+		notificationRepo: notificationRepo,
+	}
+}
+
+func (s *NotificationSrv) GetNotifications(userID string, page int, limit int) (result models.ResponseModel) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultPageLimit
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+	res, total, unread, err := s.notificationRepo.GetNotifications(userID, page, limit)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if res == nil {
+		res = []models.NotificationModel{}
+	}
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "get notifications success",
+		Result: models.NotificationListModel{
+			Notifications: res,
+			Unread:        unread,
+			Total:         total,
+			Page:          page,
+			Limit:         limit,
+		},
+	}
+}
+
+func (s *NotificationSrv) MarkNotificationsRead(userID string) (result models.ResponseModel) {
+	if err := s.notificationRepo.MarkNotificationsRead(userID); err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "mark notifications read success",
+		Result:  nil,
+	}
+}
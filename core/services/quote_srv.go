@@ -1,34 +1,158 @@
 package services
 
 import (
+	"backend/config"
 	"backend/core/models"
 	"backend/core/repositories"
+	"backend/utils"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 )
 
+const (
+	maxSourceTitleLength    = 200
+	maxRevisionReasonLength = 200
+)
+
+var languageRule = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})?$`)
+
 type QuoteService interface {
-	GetQuotes() (result models.ResponseModel)
+	GetQuotes(userID string, query models.HandGetQuotesQueryModel) (result models.ResponseModel)
+
+	CreateQuote(userID string, trusted bool, body models.HandCreateQuoteBodyModel) (result models.ResponseModel)
+
+	UpdateQuote(userID string, role string, trusted bool, id string, body models.HandUpdateQuoteBodyModel) (result models.ResponseModel)
+
+	GetRevisions(id string, from int, to int) (result models.ResponseModel)
+
+	RevertQuote(userID string, role string, trusted bool, id string, rev int) (result models.ResponseModel)
+
+	DeleteQuote(userID string, role string, id string) (result models.ResponseModel)
+
+	GetTrash(page int, limit int) (result models.ResponseModel)
+
+	RestoreQuote(id string) (result models.ResponseModel)
+
+	PurgeTrash() (result models.ResponseModel)
+
+	GetDuplicates() (result models.ResponseModel)
+
+	MergeQuotes(userID string, targetID string, sourceIDs []string) (result models.ResponseModel)
+
+	SearchQuotes(query string, limit int) (result models.ResponseModel)
+
+	GetContentFilter() (result models.ResponseModel)
+
+	ReloadContentFilter() (result models.ResponseModel)
 
-	CreateQuote(quote string) (result models.ResponseModel)
+	GetTranslations(id string) (result models.ResponseModel)
 
-	UpdateQuote(id string, quote string, vote int) (result models.ResponseModel)
+	AddTranslation(userID string, role string, id string, body models.HandAddTranslationBodyModel) (result models.ResponseModel)
 
-	DeleteQuote(id string) (result models.ResponseModel)
+	RemoveTranslation(userID string, role string, id string) (result models.ResponseModel)
+
+	ImportQuotes(userID string, format string, dryRun bool, file models.UploadFileModel) (result models.ResponseModel)
+
+	ReindexQuotes() error
+
+	SyncIndexes() error
+
+	MigrateQuotes() error
+
+	EnsureIndexes() error
 }
 type QuoteSrv struct {
-	quoteRepo repositories.QuoteRepository
+	quoteRepo     repositories.QuoteRepository
+	searchRepo    repositories.SearchRepository
+	tagRepo       repositories.TagRepository
+	categoryRepo  repositories.CategoryRepository
+	authorRepo    repositories.AuthorRepository
+	revisionRepo  repositories.RevisionRepository
+	userRepo      repositories.UserRepository
+	duplicateRepo repositories.DuplicateRepository
+	filterRepo    repositories.FilterRepository
+	favoriteRepo  repositories.FavoriteRepository
+	commentRepo   repositories.CommentRepository
+	reactionRepo  repositories.ReactionRepository
+	filter        *contentFilter
+	// indexMu guards indexedAt, the time the indexes were last brought up to date
+	indexMu   sync.Mutex
+	indexedAt time.Time
+}
+
+// QuoteRepositories are the stores the quote service works with, a feature
+// that needs another one adds it here so the callers only name what they use.
+type QuoteRepositories struct {
+	Quote     repositories.QuoteRepository
+	Search    repositories.SearchRepository
+	Tag       repositories.TagRepository
+	Category  repositories.CategoryRepository
+	Author    repositories.AuthorRepository
+	Revision  repositories.RevisionRepository
+	User      repositories.UserRepository
+	Duplicate repositories.DuplicateRepository
+	Filter    repositories.FilterRepository
+	Favorite  repositories.FavoriteRepository
+	Comment   repositories.CommentRepository
+	Reaction  repositories.ReactionRepository
 }
 
-func NewQuoteService(quoteRepo repositories.QuoteRepository) QuoteService {
+func NewQuoteService(repos QuoteRepositories) QuoteService {
 	return &QuoteSrv{
-		quoteRepo: quoteRepo,
+		quoteRepo:     repos.Quote,
+		searchRepo:    repos.Search,
+		tagRepo:       repos.Tag,
+		categoryRepo:  repos.Category,
+		authorRepo:    repos.Author,
+		revisionRepo:  repos.Revision,
+		userRepo:      repos.User,
+		duplicateRepo: repos.Duplicate,
+		filterRepo:    repos.Filter,
+		favoriteRepo:  repos.Favorite,
+		commentRepo:   repos.Comment,
+		reactionRepo:  repos.Reaction,
+		filter:        &contentFilter{},
 	}
 }
 
-func (s *QuoteSrv) GetQuotes() (result models.ResponseModel) {
-	res, err := s.quoteRepo.GetQuotes()
+func (s *QuoteSrv) GetQuotes(userID string, query models.HandGetQuotesQueryModel) (result models.ResponseModel) {
+	filter, err := quoteFilterFromQuery(query)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if query.Category != "" {
+		categories, err := s.categoryRepo.GetCategories()
+		if err != nil {
+			return models.ResponseModel{
+				Status:  false,
+				Code:    400,
+				Message: err.Error(),
+				Result:  nil,
+			}
+		}
+		filter.CategoryIDs = categoryDescendants(categories, query.Category)
+	}
+	limit := filter.Limit
+	// one extra quote tells whether there is a next page
+	filter.Limit = limit + 1
+	res, err := s.quoteRepo.GetQuotes(filter)
 	if err != nil {
 		return models.ResponseModel{
 			Status:  false,
@@ -37,30 +161,383 @@ func (s *QuoteSrv) GetQuotes() (result models.ResponseModel) {
 			Result:  nil,
 		}
 	}
+	list := models.QuoteListModel{
+		Quotes: res,
+	}
+	if list.Quotes == nil {
+		list.Quotes = []models.QuoteModel{}
+	}
+	if len(list.Quotes) > limit {
+		list.Quotes = list.Quotes[:limit]
+		list.NextCursor = encodeQuoteCursor(filter.Sort, list.Quotes[limit-1])
+	}
+	if !filter.Translations {
+		if err := s.localize(list.Quotes, query.AcceptLanguage); err != nil {
+			return models.ResponseModel{
+				Status:  false,
+				Code:    400,
+				Message: err.Error(),
+				Result:  nil,
+			}
+		}
+	}
+	if err := s.markFavorited(userID, list.Quotes); err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if query.IncludeTotal {
+		total, err := s.quoteRepo.CountQuotes(filter)
+		if err != nil {
+			return models.ResponseModel{
+				Status:  false,
+				Code:    400,
+				Message: err.Error(),
+				Result:  nil,
+			}
+		}
+		list.Total = &total
+	}
 	return models.ResponseModel{
 		Status:  true,
 		Code:    200,
 		Message: "get quotes success",
-		Result:  res,
+		Result:  list,
 	}
 }
 
-func (s *QuoteSrv) CreateQuote(quote string) (result models.ResponseModel) {
-	if quote == "" {
-		return models.ResponseModel{
+func quoteFilterFromQuery(query models.HandGetQuotesQueryModel) (filter models.QuoteFilterModel, err error) {
+	filter = models.QuoteFilterModel{
+		Limit:      query.Limit,
+		Sort:       query.Sort,
+		Descending: true,
+		MinVotes:   query.MinVotes,
+		AuthorID:   query.Author,
+		Language:   strings.ToLower(query.Language),
+	}
+	// asking for a language lists its variants, otherwise one per translation group
+	filter.Translations = filter.Language != ""
+	if filter.Limit < 1 {
+		filter.Limit = defaultPageLimit
+	}
+	if filter.Limit > maxPageLimit {
+		filter.Limit = maxPageLimit
+	}
+	if filter.Sort == "" {
+		filter.Sort = models.QuoteSortCreateDate
+	}
+	if !utils.StringInSlice([]string{models.QuoteSortVote, models.QuoteSortCreateDate, models.QuoteSortUpdateDate}, filter.Sort) {
+		return filter, errors.New("sort must be votes, create_date or update_date")
+	}
+	switch query.Order {
+	case "", "desc":
+	case "asc":
+		filter.Descending = false
+	default:
+		return filter, errors.New("order must be asc or desc")
+	}
+	if query.CreatedFrom != "" {
+		from, err := utils.ParseDateTime(query.CreatedFrom, time.Local)
+		if err != nil {
+			return filter, errors.New("created_from " + err.Error())
+		}
+		filter.CreatedFrom = &from
+	}
+	if query.CreatedTo != "" {
+		to, err := utils.ParseDateTime(query.CreatedTo, time.Local)
+		if err != nil {
+			return filter, errors.New("created_to " + err.Error())
+		}
+		if utils.IsDateOnly(query.CreatedTo) {
+			// a bare date includes the whole day
+			to = to.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+		filter.CreatedTo = &to
+	}
+	for _, tag := range strings.Split(query.Tag, ",") {
+		if tag = normalizeTag(tag); tag != "" {
+			filter.Tags = append(filter.Tags, tag)
+		}
+	}
+	if query.Cursor != "" {
+		after, err := decodeQuoteCursor(query.Cursor)
+		if err != nil || after.Sort != filter.Sort {
+			return filter, errors.New("cursor invalid")
+		}
+		filter.After = &after
+	}
+	return filter, nil
+}
+
+func encodeQuoteCursor(sort string, last models.QuoteModel) string {
+	raw, _ := json.Marshal(quoteCursor(sort, last))
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// quoteCursor is where the page after last starts.
+func quoteCursor(sort string, last models.QuoteModel) models.QuoteCursorModel {
+	cursor := models.QuoteCursorModel{
+		Sort: sort,
+		ID:   last.ID,
+	}
+	switch sort {
+	case models.QuoteSortVote:
+		cursor.Vote = last.Vote
+	case models.QuoteSortUpdateDate:
+		cursor.Date = last.UpdateDate
+	default:
+		cursor.Date = last.CreateDate
+	}
+	return cursor
+}
+
+func decodeQuoteCursor(str string) (cursor models.QuoteCursorModel, err error) {
+	raw, err := base64.RawURLEncoding.DecodeString(str)
+	if err != nil {
+		return cursor, err
+	}
+	err = json.Unmarshal(raw, &cursor)
+	return cursor, err
+}
+
+// checkTaxonomy normalizes the tags of a quote and checks the category exists.
+func (s *QuoteSrv) checkTaxonomy(tags []string, categoryID string) ([]string, error) {
+	tags, err := normalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+	if categoryID != "" {
+		if _, err := s.categoryRepo.GetCategory(categoryID); err != nil {
+			return nil, errors.New("category not found")
+		}
+	}
+	return tags, nil
+}
+
+// prepareTaxonomy is checkTaxonomy that also creates the new tags.
+func (s *QuoteSrv) prepareTaxonomy(tags []string, categoryID string) ([]string, error) {
+	tags, err := s.checkTaxonomy(tags, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureTags(s.tagRepo, tags); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// normalizeSource validates the source of a quote, a URL is required for
+// url sources and any URL given must pass utils.IsUrl.
+func normalizeSource(source models.QuoteSourceModel) (models.QuoteSourceModel, error) {
+	source.Type = strings.ToLower(strings.TrimSpace(source.Type))
+	source.Title = strings.TrimSpace(source.Title)
+	source.URL = strings.TrimSpace(source.URL)
+	if source.Type == "" && (source.Title != "" || source.URL != "") {
+		source.Type = models.SourceTypeOther
+		if source.URL != "" && source.Title == "" {
+			source.Type = models.SourceTypeURL
+		}
+	}
+	if source.Type != "" && !utils.StringInSlice([]string{models.SourceTypeBook, models.SourceTypeSpeech, models.SourceTypeURL, models.SourceTypeOther}, source.Type) {
+		return source, errors.New("source type must be book, speech, url or other")
+	}
+	if source.Type == models.SourceTypeURL && source.URL == "" {
+		return source, errors.New("source url not found")
+	}
+	if source.URL != "" && !utils.IsUrl(source.URL) {
+		return source, errors.New("source url invalid")
+	}
+	if utf8.RuneCountInString(source.Title) > maxSourceTitleLength {
+		return source, fmt.Errorf("source title must be <= %d characters", maxSourceTitleLength)
+	}
+	return source, nil
+}
+
+func normalizeLanguage(language string) (string, error) {
+	language = strings.ToLower(strings.TrimSpace(language))
+	if language != "" && !languageRule.MatchString(language) {
+		return language, errors.New("language must be an ISO 639 code such as th or en")
+	}
+	return language, nil
+}
+
+func validateYear(year int) error {
+	if year != 0 && (year < -3000 || year > time.Now().Year()) {
+		return errors.New("year invalid")
+	}
+	return nil
+}
+
+// parsePublishAt reads the publish_at of a quote, "" means publish right away.
+// Times without a zone are server time.
+func parsePublishAt(str string) (*time.Time, error) {
+	str = strings.TrimSpace(str)
+	if str == "" {
+		return nil, nil
+	}
+	if utils.IsDateTimeFormat(str) {
+		if t, err := time.ParseInLocation("2006-01-02 15:04:05", str, time.Local); err == nil {
+			return &t, nil
+		}
+	} else if t, err := time.Parse(time.RFC3339, str); err == nil {
+		return &t, nil
+	}
+	return nil, errors.New("publish_at must be RFC 3339 or YYYY-MM-DD HH:MM:SS")
+}
+
+// attribute resolves the author of a quote, an empty name means unattributed.
+func (s *QuoteSrv) attribute(name string) (authorID string, author string, err error) {
+	if strings.TrimSpace(name) == "" {
+		return "", "", nil
+	}
+	res, err := ensureAuthor(s.authorRepo, name)
+	if err != nil {
+		return "", "", err
+	}
+	return res.ID, res.Name, nil
+}
+
+// markFavorited sets IsFavorited on the quotes the user has saved.
+func (s *QuoteSrv) markFavorited(userID string, quotes []models.QuoteModel) error {
+	if userID == "" || len(quotes) == 0 {
+		return nil
+	}
+	ids := make([]string, len(quotes))
+	for i, quote := range quotes {
+		ids[i] = quote.ID
+	}
+	favorited, err := s.favoriteRepo.GetFavoritedIDs(userID, ids)
+	if err != nil {
+		return err
+	}
+	for i := range quotes {
+		quotes[i].IsFavorited = utils.StringInSlice(favorited, quotes[i].ID)
+	}
+	return nil
+}
+
+// draftQuote runs every check of CreateQuote without writing anything, the
+// payload still needs its tags and author resolved before it can be stored.
+// ok is false when the quote is refused, failure then says why.
+func (s *QuoteSrv) draftQuote(userID string, trusted bool, body models.HandCreateQuoteBodyModel) (payload models.CreateQuoteModel, failure models.ResponseModel, ok bool) {
+	body.Quote = utils.NormalizeQuote(body.Quote)
+	if body.Quote == "" {
+		return payload, models.ResponseModel{
 			Status:  false,
 			Code:    400,
 			Message: "quote not found",
 			Result:  nil,
-		}
+		}, false
+	}
+	source, err := normalizeSource(body.Source)
+	if err == nil {
+		err = validateYear(body.Year)
+	}
+	language := ""
+	if err == nil {
+		language, err = normalizeLanguage(body.Language)
+	}
+	var publishAt *time.Time
+	if err == nil {
+		publishAt, err = parsePublishAt(body.PublishAt)
+	}
+	if err != nil {
+		return payload, models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}, false
 	}
-	payload := models.CreateQuoteModel{
+	decision := s.filter.check(body.Quote, language)
+	if decision.Action == models.FilterActionReject {
+		return payload, models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "quote rejected by content filter",
+			Result:  decision,
+		}, false
+	}
+	tags, err := s.checkTaxonomy(body.Tags, body.CategoryID)
+	if err != nil {
+		return payload, models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}, false
+	}
+	if duplicate, ok := s.findDuplicate(body.Quote, ""); ok {
+		return payload, models.ResponseModel{
+			Status:  false,
+			Code:    409,
+			Message: "quote already exist",
+			Result:  duplicate,
+		}, false
+	}
+	author, err := normalizeAuthorName(body.Author)
+	if err != nil {
+		return payload, models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}, false
+	}
+	payload = models.CreateQuoteModel{
 		ID:         uuid.New().String(),
-		Quote:      quote,
+		Quote:      body.Quote,
 		Vote:       0,
+		CreatedBy:  userID,
+		Tags:       tags,
+		CategoryID: body.CategoryID,
+		Author:     author,
+		Source:     source,
+		Language:   language,
+		Year:       body.Year,
+		Status:     models.QuoteStatusPending,
+		PublishAt:  publishAt,
 		CreateDate: time.Now(),
 		UpdateDate: time.Now(),
 	}
+	if decision.Action == models.FilterActionFlag {
+		payload.FlagReasons = decision.Reasons
+	} else if trusted {
+		payload.Status = models.QuoteStatusApproved
+		if publishAt != nil && publishAt.After(time.Now()) {
+			payload.Status = models.QuoteStatusScheduled
+		}
+	}
+	return payload, models.ResponseModel{}, true
+}
+
+// resolveQuote creates the new tags and the author of a drafted quote.
+func (s *QuoteSrv) resolveQuote(payload *models.CreateQuoteModel) (err error) {
+	if err = ensureTags(s.tagRepo, payload.Tags); err != nil {
+		return err
+	}
+	payload.AuthorID, payload.Author, err = s.attribute(payload.Author)
+	return err
+}
+
+// CreateQuote stores a new quote, quotes from untrusted users wait in the
+// moderation queue until a moderator approves them.
+func (s *QuoteSrv) CreateQuote(userID string, trusted bool, body models.HandCreateQuoteBodyModel) (result models.ResponseModel) {
+	payload, failure, ok := s.draftQuote(userID, trusted, body)
+	if !ok {
+		return failure
+	}
+	if err := s.resolveQuote(&payload); err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
 	res, err := s.quoteRepo.CreateQuote(payload)
 	if err != nil {
 		return models.ResponseModel{
@@ -70,6 +547,7 @@ func (s *QuoteSrv) CreateQuote(quote string) (result models.ResponseModel) {
 			Result:  nil,
 		}
 	}
+	s.index(res)
 	return models.ResponseModel{
 		Status:  true,
 		Code:    201,
@@ -78,8 +556,11 @@ func (s *QuoteSrv) CreateQuote(quote string) (result models.ResponseModel) {
 	}
 }
 
-func (s *QuoteSrv) UpdateQuote(id string, quote string, vote int) (result models.ResponseModel) {
-	if id == "" || quote == "" {
+// UpdateQuote lets the creator of a quote or a moderator edit it. An edit by
+// an untrusted user sends a published quote back to the moderation queue.
+func (s *QuoteSrv) UpdateQuote(userID string, role string, trusted bool, id string, body models.HandUpdateQuoteBodyModel) (result models.ResponseModel) {
+	body.Quote = utils.NormalizeQuote(body.Quote)
+	if id == "" || body.Quote == "" {
 		return models.ResponseModel{
 			Status:  false,
 			Code:    400,
@@ -87,7 +568,7 @@ func (s *QuoteSrv) UpdateQuote(id string, quote string, vote int) (result models
 			Result:  nil,
 		}
 	}
-	if vote < 0 {
+	if body.Vote < 0 {
 		return models.ResponseModel{
 			Status:  false,
 			Code:    400,
@@ -95,12 +576,155 @@ func (s *QuoteSrv) UpdateQuote(id string, quote string, vote int) (result models
 			Result:  nil,
 		}
 	}
+	body.Reason = strings.TrimSpace(body.Reason)
+	if utf8.RuneCountInString(body.Reason) > maxRevisionReasonLength {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: fmt.Sprintf("reason must be <= %d characters", maxRevisionReasonLength),
+			Result:  nil,
+		}
+	}
+	current, err := s.quoteRepo.GetQuote(id)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    404,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if !canEdit(current, userID, role) {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    403,
+			Message: "forbidden",
+			Result:  nil,
+		}
+	}
+	if role != models.RoleModerator && role != models.RoleAdmin {
+		// only moderators correct a vote tally, the creator votes like everyone else
+		body.Vote = 0
+	}
 	payload := models.UpdateQuoteModel{
-		Quote:      quote,
-		Vote:       vote,
+		Quote:      body.Quote,
+		Vote:       body.Vote,
+		CategoryID: body.CategoryID,
 		UpdateDate: time.Now(),
 	}
-	res, err := s.quoteRepo.UpdateQuote(id, payload)
+	if body.Tags != nil || body.CategoryID != nil {
+		tags := []string{}
+		if body.Tags != nil {
+			tags = *body.Tags
+		}
+		categoryID := ""
+		if body.CategoryID != nil {
+			categoryID = *body.CategoryID
+		}
+		tags, err := s.prepareTaxonomy(tags, categoryID)
+		if err != nil {
+			return models.ResponseModel{
+				Status:  false,
+				Code:    400,
+				Message: err.Error(),
+				Result:  nil,
+			}
+		}
+		if body.Tags != nil {
+			payload.Tags = &tags
+		}
+	}
+	if body.Source != nil {
+		source, err := normalizeSource(*body.Source)
+		if err != nil {
+			return models.ResponseModel{
+				Status:  false,
+				Code:    400,
+				Message: err.Error(),
+				Result:  nil,
+			}
+		}
+		payload.Source = &source
+	}
+	if body.Language != nil {
+		language, err := normalizeLanguage(*body.Language)
+		if err != nil {
+			return models.ResponseModel{
+				Status:  false,
+				Code:    400,
+				Message: err.Error(),
+				Result:  nil,
+			}
+		}
+		payload.Language = &language
+	}
+	if body.Year != nil {
+		if err := validateYear(*body.Year); err != nil {
+			return models.ResponseModel{
+				Status:  false,
+				Code:    400,
+				Message: err.Error(),
+				Result:  nil,
+			}
+		}
+		payload.Year = body.Year
+	}
+	if body.Author != nil {
+		authorID, author, err := s.attribute(*body.Author)
+		if err != nil {
+			return models.ResponseModel{
+				Status:  false,
+				Code:    400,
+				Message: err.Error(),
+				Result:  nil,
+			}
+		}
+		payload.AuthorID = &authorID
+		payload.Author = &author
+	}
+	if body.Vote != 0 && !quoteApproved(current) {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "quote not approved",
+			Result:  nil,
+		}
+	}
+	if body.PublishAt != nil {
+		if quoteApproved(current) {
+			return models.ResponseModel{
+				Status:  false,
+				Code:    400,
+				Message: "quote already published",
+				Result:  nil,
+			}
+		}
+		publishAt, err := parsePublishAt(*body.PublishAt)
+		if err != nil {
+			return models.ResponseModel{
+				Status:  false,
+				Code:    400,
+				Message: err.Error(),
+				Result:  nil,
+			}
+		}
+		now := time.Now()
+		if publishAt == nil {
+			publishAt = &now
+		}
+		payload.PublishAt = publishAt
+		if current.Status == models.QuoteStatusScheduled && !publishAt.After(now) {
+			payload.Status = models.QuoteStatusApproved
+		}
+	}
+	language := current.Language
+	if payload.Language != nil {
+		language = *payload.Language
+	}
+	if failure, ok := s.reviewEdit(current, &payload, language, trusted); !ok {
+		return failure
+	}
+	res, err := s.editQuote(userID, current, payload, body.Reason)
 	if err != nil {
 		return models.ResponseModel{
 			Status:  false,
@@ -109,7 +733,6 @@ func (s *QuoteSrv) UpdateQuote(id string, quote string, vote int) (result models
 			Result:  nil,
 		}
 	}
-
 	return models.ResponseModel{
 		Status:  true,
 		Code:    200,
@@ -118,33 +741,154 @@ func (s *QuoteSrv) UpdateQuote(id string, quote string, vote int) (result models
 	}
 }
 
-func (s *QuoteSrv) DeleteQuote(id string) (result models.ResponseModel) {
-	if id == "" {
+// originalRevision is the first revision of a quote, quotes edited before
+// revisions existed only get it stored on their next edit.
+func originalRevision(quote models.QuoteModel) models.RevisionModel {
+	return models.RevisionModel{
+		ID:         uuid.New().String(),
+		QuoteID:    quote.ID,
+		Rev:        1,
+		Quote:      quote.Quote,
+		EditedBy:   quote.CreatedBy,
+		CreateDate: quote.CreateDate,
+	}
+}
+
+func (s *QuoteSrv) quoteRevisions(quote models.QuoteModel) ([]models.RevisionModel, error) {
+	revisions, err := s.revisionRepo.GetRevisions(quote.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(revisions) == 0 {
+		revisions = []models.RevisionModel{originalRevision(quote)}
+	}
+	return revisions, nil
+}
+
+// canEdit lets the creator of a quote and moderators change it.
+func canEdit(quote models.QuoteModel, userID string, role string) bool {
+	return quote.CreatedBy == userID || role == models.RoleModerator || role == models.RoleAdmin
+}
+
+// reviewEdit runs the checks of CreateQuote on the new text of an edit and
+// decides the status it leaves the quote in. Any edit by an untrusted user
+// sends a published quote back to the moderation queue, else an approved
+// quote could be swapped for text no moderator has seen.
+func (s *QuoteSrv) reviewEdit(current models.QuoteModel, payload *models.UpdateQuoteModel, language string, trusted bool) (failure models.ResponseModel, ok bool) {
+	text := current.Quote
+	if payload.Quote != "" {
+		text = payload.Quote
+	}
+	decision := s.filter.check(text, language)
+	if decision.Action == models.FilterActionReject {
 		return models.ResponseModel{
 			Status:  false,
 			Code:    400,
-			Message: "id not found",
-			Result:  nil,
+			Message: "quote rejected by content filter",
+			Result:  decision,
+		}, false
+	}
+	if text != current.Quote {
+		if duplicate, ok := s.findDuplicate(text, current.ID); ok {
+			return models.ResponseModel{
+				Status:  false,
+				Code:    409,
+				Message: "quote already exist",
+				Result:  duplicate,
+			}, false
 		}
 	}
-	res, err := s.quoteRepo.GetQuote(id)
+	if current.Status == models.QuoteStatusRejected {
+		return models.ResponseModel{}, true
+	}
+	if decision.Action == models.FilterActionFlag {
+		// a flagged edit takes the quote out of the public list until a moderator looks at it
+		payload.Status = models.QuoteStatusPending
+		payload.FlagReasons = decision.Reasons
+	} else if !trusted && current.Status != models.QuoteStatusPending {
+		payload.Status = models.QuoteStatusPending
+	}
+	return models.ResponseModel{}, true
+}
+
+// editQuote applies payload to current and stores the result as a new revision.
+// Votes are reset when the deployment asks for it and the text changed substantially.
+func (s *QuoteSrv) editQuote(userID string, current models.QuoteModel, payload models.UpdateQuoteModel, reason string) (models.QuoteModel, error) {
+	revisions, err := s.revisionRepo.GetRevisions(current.ID)
 	if err != nil {
-		return models.ResponseModel{
-			Status:  false,
-			Code:    400,
-			Message: err.Error(),
-			Result:  nil,
+		return models.QuoteModel{}, err
+	}
+	if len(revisions) == 0 {
+		original := originalRevision(current)
+		if err := s.revisionRepo.CreateRevision(models.CreateRevisionModel(original)); err != nil {
+			return models.QuoteModel{}, err
+		}
+		revisions = append(revisions, original)
+	}
+	voteReset := false
+	if payload.Quote != "" && payload.Quote != current.Quote && config.Env.RevisionVotes == models.RevisionVotesReset {
+		voteReset = utils.DiffSimilarity(utils.DiffWords(current.Quote, payload.Quote)) < config.Env.RevisionResetSimilarity
+	}
+	if voteReset {
+		payload.Vote = 0
+	}
+	res, err := s.quoteRepo.UpdateQuote(current.ID, payload)
+	if err != nil {
+		return res, err
+	}
+	if voteReset && current.GroupID != "" {
+		// only the votes cast on this variant go, the group keeps the others
+		cleared, err := s.userRepo.ClearVotes(current.ID)
+		if err != nil {
+			return res, err
+		}
+		if cleared > 0 {
+			if err := s.quoteRepo.IncrementVote(current.ID, -int(cleared)); err != nil {
+				return res, err
+			}
 		}
+		res.Vote = max(current.Vote-int(cleared), 0)
+	} else if voteReset {
+		if err := s.quoteRepo.ResetVote(current.ID); err != nil {
+			return res, err
+		}
+		if _, err := s.userRepo.ClearVotes(current.ID); err != nil {
+			return res, err
+		}
+		res.Vote = 0
+	} else if payload.Vote != 0 && current.GroupID != "" {
+		if err := s.setGroupVote(current.GroupID, payload.Vote); err != nil {
+			return res, err
+		}
+	}
+	err = s.revisionRepo.CreateRevision(models.CreateRevisionModel{
+		ID:         uuid.New().String(),
+		QuoteID:    current.ID,
+		Rev:        revisions[len(revisions)-1].Rev + 1,
+		Quote:      res.Quote,
+		EditedBy:   userID,
+		Reason:     reason,
+		VoteReset:  voteReset,
+		CreateDate: time.Now(),
+	})
+	if err != nil {
+		return res, err
 	}
-	if res.Vote > 0 {
+	s.index(res)
+	return res, nil
+}
+
+func (s *QuoteSrv) GetRevisions(id string, from int, to int) (result models.ResponseModel) {
+	quote, err := s.quoteRepo.GetQuote(id)
+	if err != nil {
 		return models.ResponseModel{
 			Status:  false,
-			Code:    400,
-			Message: "cannot delete quote with vote > 0",
+			Code:    404,
+			Message: err.Error(),
 			Result:  nil,
 		}
 	}
-	err = s.quoteRepo.DeleteQuote(id)
+	revisions, err := s.quoteRevisions(quote)
 	if err != nil {
 		return models.ResponseModel{
 			Status:  false,
@@ -153,10 +897,978 @@ func (s *QuoteSrv) DeleteQuote(id string) (result models.ResponseModel) {
 			Result:  nil,
 		}
 	}
-	return models.ResponseModel{
-		Status:  true,
-		Code:    200,
-		Message: "delete quote success",
+	res := models.QuoteRevisionsModel{Revisions: revisions}
+	if from != 0 || to != 0 {
+		var a, b *models.RevisionModel
+		for i := range revisions {
+			if revisions[i].Rev == from {
+				a = &revisions[i]
+			}
+			if revisions[i].Rev == to {
+				b = &revisions[i]
+			}
+		}
+		if a == nil || b == nil {
+			return models.ResponseModel{
+				Status:  false,
+				Code:    404,
+				Message: "revision not found",
+				Result:  nil,
+			}
+		}
+		parts := utils.DiffWords(a.Quote, b.Quote)
+		diff := models.RevisionDiffModel{
+			From:       from,
+			To:         to,
+			Similarity: utils.DiffSimilarity(parts),
+			Parts:      []models.DiffPartModel{},
+		}
+		for _, part := range parts {
+			diff.Parts = append(diff.Parts, models.DiffPartModel(part))
+		}
+		res.Diff = &diff
+	}
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "get revisions success",
+		Result:  res,
+	}
+}
+
+// RevertQuote restores the text of an earlier revision, it is an edit so the
+// same people may do it and the same checks apply as in UpdateQuote.
+func (s *QuoteSrv) RevertQuote(userID string, role string, trusted bool, id string, rev int) (result models.ResponseModel) {
+	quote, err := s.quoteRepo.GetQuote(id)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    404,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if !canEdit(quote, userID, role) {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    403,
+			Message: "forbidden",
+			Result:  nil,
+		}
+	}
+	revisions, err := s.quoteRevisions(quote)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	var target *models.RevisionModel
+	for i := range revisions {
+		if revisions[i].Rev == rev {
+			target = &revisions[i]
+		}
+	}
+	if target == nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    404,
+			Message: "revision not found",
+			Result:  nil,
+		}
+	}
+	if target.Quote == quote.Quote {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "quote already matches revision",
+			Result:  nil,
+		}
+	}
+	payload := models.UpdateQuoteModel{
+		Quote:      target.Quote,
+		UpdateDate: time.Now(),
+	}
+	if failure, ok := s.reviewEdit(quote, &payload, quote.Language, trusted); !ok {
+		return failure
+	}
+	res, err := s.editQuote(userID, quote, payload, fmt.Sprintf("revert to revision %d", rev))
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "revert quote success",
+		Result:  res,
+	}
+}
+
+// DeleteQuote moves a quote to the trash. Creators may delete their own quotes,
+// moderators any quote, the users who voted for it get their vote back.
+func (s *QuoteSrv) DeleteQuote(userID string, role string, id string) (result models.ResponseModel) {
+	if id == "" {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "id not found",
+			Result:  nil,
+		}
+	}
+	res, err := s.quoteRepo.GetQuote(id)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if res.CreatedBy != userID && role != models.RoleModerator && role != models.RoleAdmin {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    403,
+			Message: "forbidden",
+			Result:  nil,
+		}
+	}
+	if _, err := s.leaveGroup(res); err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	_, err = s.quoteRepo.SoftDeleteQuote(id, userID)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if _, err := s.userRepo.ClearVotes(id); err != nil {
+		log.Printf("release votes of quote %s failed: %v", id, err)
+	}
+	s.unindex(id)
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "delete quote success",
 		Result:  nil,
 	}
 }
+
+func (s *QuoteSrv) GetTrash(page int, limit int) (result models.ResponseModel) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultPageLimit
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+	res, total, err := s.quoteRepo.GetTrash(page, limit)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if res == nil {
+		res = []models.QuoteModel{}
+	}
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "get trash success",
+		Result: models.TrashListModel{
+			Quotes: res,
+			Total:  total,
+			Page:   page,
+			Limit:  limit,
+		},
+	}
+}
+
+func (s *QuoteSrv) RestoreQuote(id string) (result models.ResponseModel) {
+	res, err := s.quoteRepo.RestoreQuote(id)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    404,
+			Message: "quote not found in trash",
+			Result:  nil,
+		}
+	}
+	s.index(res)
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "restore quote success",
+		Result:  res,
+	}
+}
+
+// PurgeTrash permanently removes quotes that have been in the trash longer
+// than the configured retention, together with their revisions.
+func (s *QuoteSrv) PurgeTrash() (result models.ResponseModel) {
+	cutoff := time.Now().AddDate(0, 0, -config.Env.TrashRetentionDays)
+	quotes, err := s.quoteRepo.GetQuotesDeletedBefore(cutoff)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    500,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	purged := 0
+	for _, quote := range quotes {
+		if err := s.quoteRepo.DeleteQuote(quote.ID); err != nil {
+			log.Printf("purge quote %s failed: %v", quote.ID, err)
+			continue
+		}
+		if err := s.revisionRepo.DeleteRevisionsByQuote(quote.ID); err != nil {
+			log.Printf("purge revisions of quote %s failed: %v", quote.ID, err)
+		}
+		if err := s.favoriteRepo.DeleteFavoritesByQuote(quote.ID); err != nil {
+			log.Printf("purge favorites of quote %s failed: %v", quote.ID, err)
+		}
+		if err := s.commentRepo.DeleteCommentsByQuote(quote.ID); err != nil {
+			log.Printf("purge comments of quote %s failed: %v", quote.ID, err)
+		}
+		if err := s.reactionRepo.DeleteReactionsByQuote(quote.ID); err != nil {
+			log.Printf("purge reactions of quote %s failed: %v", quote.ID, err)
+		}
+		purged++
+	}
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "purge trash success",
+		Result:  purged,
+	}
+}
+
+func (s *QuoteSrv) SearchQuotes(query string, limit int) (result models.ResponseModel) {
+	if strings.TrimSpace(query) == "" {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "query not found",
+			Result:  nil,
+		}
+	}
+	if limit < 1 {
+		limit = defaultPageLimit
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+	hits, err := s.searchRepo.Search(query, limit)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	results := []models.QuoteSearchResultModel{}
+	if len(hits) == 0 {
+		return models.ResponseModel{
+			Status:  true,
+			Code:    200,
+			Message: "search quotes success",
+			Result:  results,
+		}
+	}
+	ids := []string{}
+	for _, hit := range hits {
+		ids = append(ids, hit.ID)
+	}
+	quotes, err := s.quoteRepo.GetQuotesByIDs(ids)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	byID := map[string]models.QuoteModel{}
+	for _, quote := range quotes {
+		byID[quote.ID] = quote
+	}
+	// keep the ranking of the index, quotes gone from the database are skipped
+	for _, hit := range hits {
+		quote, ok := byID[hit.ID]
+		if !ok {
+			continue
+		}
+		results = append(results, models.QuoteSearchResultModel{
+			Quote:     quote,
+			Score:     hit.Score,
+			Highlight: hit.Highlight,
+		})
+	}
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "search quotes success",
+		Result:  results,
+	}
+}
+
+func (s *QuoteSrv) GetContentFilter() (result models.ResponseModel) {
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "get content filter success",
+		Result:  s.filter.getRules(),
+	}
+}
+
+// ReloadContentFilter reads the content filter rules again, the rules in use
+// are kept when the new ones are invalid.
+func (s *QuoteSrv) ReloadContentFilter() (result models.ResponseModel) {
+	rules, err := s.filterRepo.GetRules()
+	if err == nil {
+		err = s.filter.load(rules)
+	}
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "reload content filter success",
+		Result:  rules,
+	}
+}
+
+// acceptLanguages lists the language tags of an Accept-Language header, most
+// preferred first.
+func acceptLanguages(header string) []string {
+	type tag struct {
+		language string
+		q        float64
+	}
+	tags := []tag{}
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		language := strings.ToLower(strings.TrimSpace(fields[0]))
+		if language == "" || language == "*" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q > 0 {
+			tags = append(tags, tag{language: language, q: q})
+		}
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+	languages := make([]string, len(tags))
+	for i, t := range tags {
+		languages[i] = t.language
+	}
+	return languages
+}
+
+// pickTranslation returns the variant in the first preferred language, an exact
+// tag wins over a matching primary language. Without a match it keeps quote.
+func pickTranslation(quote models.QuoteModel, group []models.QuoteModel, languages []string) models.QuoteModel {
+	primary := func(language string) string {
+		return strings.SplitN(language, "-", 2)[0]
+	}
+	for _, language := range languages {
+		for _, variant := range group {
+			if variant.Language == language {
+				return variant
+			}
+		}
+		for _, variant := range group {
+			if variant.Language != "" && primary(variant.Language) == primary(language) {
+				return variant
+			}
+		}
+	}
+	return quote
+}
+
+// localize swaps every translated quote of a list for the variant the reader
+// prefers and lists the variants of its group on it.
+func (s *QuoteSrv) localize(quotes []models.QuoteModel, acceptLanguage string) error {
+	groupIDs := []string{}
+	for _, quote := range quotes {
+		if quote.GroupID != "" {
+			groupIDs = append(groupIDs, quote.GroupID)
+		}
+	}
+	if len(groupIDs) == 0 {
+		return nil
+	}
+	members, err := s.quoteRepo.GetGroups(groupIDs)
+	if err != nil {
+		return err
+	}
+	groups := map[string][]models.QuoteModel{}
+	for _, member := range members {
+		groups[member.GroupID] = append(groups[member.GroupID], member)
+	}
+	languages := acceptLanguages(acceptLanguage)
+	for i, quote := range quotes {
+		group := groups[quote.GroupID]
+		if len(group) == 0 {
+			continue
+		}
+		sort.Slice(group, func(a, b int) bool { return group[a].Language < group[b].Language })
+		translations := make([]models.QuoteTranslationModel, len(group))
+		for j, variant := range group {
+			translations[j] = models.QuoteTranslationModel{ID: variant.ID, Language: variant.Language}
+		}
+		quotes[i] = pickTranslation(quote, group, languages)
+		quotes[i].Translations = translations
+	}
+	return nil
+}
+
+// groupOf returns the quotes of the translation group of quote, just quote
+// when it is not translated.
+func (s *QuoteSrv) groupOf(quote models.QuoteModel) ([]models.QuoteModel, error) {
+	if quote.GroupID == "" {
+		return []models.QuoteModel{quote}, nil
+	}
+	return s.quoteRepo.GetGroup(quote.GroupID)
+}
+
+func (s *QuoteSrv) setGroupVote(groupID string, vote int) error {
+	members, err := s.quoteRepo.GetGroup(groupID)
+	if err != nil {
+		return err
+	}
+	ids := make([]string, len(members))
+	for i, member := range members {
+		ids[i] = member.ID
+	}
+	return s.quoteRepo.SetGroup(ids, groupID, vote)
+}
+
+// leaveGroup takes quote out of its translation group. The quote keeps the
+// votes cast on it and the rest of the group keeps the others, the oldest
+// remaining quote becomes the root when the root leaves.
+func (s *QuoteSrv) leaveGroup(quote models.QuoteModel) (models.QuoteModel, error) {
+	if quote.GroupID == "" {
+		return quote, nil
+	}
+	members, err := s.quoteRepo.GetGroup(quote.GroupID)
+	if err != nil {
+		return quote, err
+	}
+	own, err := s.userRepo.CountVotes(quote.ID)
+	if err != nil {
+		return quote, err
+	}
+	rest := []string{}
+	for _, member := range members {
+		if member.ID != quote.ID {
+			rest = append(rest, member.ID)
+		}
+	}
+	if err := s.quoteRepo.SetGroup([]string{quote.ID}, "", int(own)); err != nil {
+		return quote, err
+	}
+	vote := max(quote.Vote-int(own), 0)
+	switch {
+	case len(rest) == 1:
+		err = s.quoteRepo.SetGroup(rest, "", vote)
+	case len(rest) > 1 && quote.GroupID == quote.ID:
+		err = s.quoteRepo.SetGroup(rest, rest[0], vote)
+	case len(rest) > 1:
+		err = s.quoteRepo.SetGroup(rest, quote.GroupID, vote)
+	}
+	if err != nil {
+		return quote, err
+	}
+	quote.GroupID = ""
+	quote.Vote = int(own)
+	return quote, nil
+}
+
+func (s *QuoteSrv) GetTranslations(id string) (result models.ResponseModel) {
+	quote, err := s.quoteRepo.GetQuote(id)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    404,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	members, err := s.groupOf(quote)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	res := models.QuoteTranslationsModel{GroupID: quote.GroupID, Vote: quote.Vote, Quotes: []models.QuoteModel{}}
+	for _, member := range members {
+		if quoteApproved(member) {
+			res.Quotes = append(res.Quotes, member)
+		}
+	}
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "get translations success",
+		Result:  res,
+	}
+}
+
+// AddTranslation links quote_id to the quote id as its translation, the votes
+// of both count for the group from then on. Moderators can link any quotes,
+// users only quotes they created.
+func (s *QuoteSrv) AddTranslation(userID string, role string, id string, body models.HandAddTranslationBodyModel) (result models.ResponseModel) {
+	if id == "" || body.QuoteID == "" {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "id or quote_id not found",
+			Result:  nil,
+		}
+	}
+	if id == body.QuoteID {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "quote can not translate itself",
+			Result:  nil,
+		}
+	}
+	quote, err := s.quoteRepo.GetQuote(id)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    404,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	translation, err := s.quoteRepo.GetQuote(body.QuoteID)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    404,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	moderator := role == models.RoleModerator || role == models.RoleAdmin
+	if !moderator && (quote.CreatedBy != userID || translation.CreatedBy != userID) {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    403,
+			Message: "forbidden",
+			Result:  nil,
+		}
+	}
+	if !quoteApproved(quote) || !quoteApproved(translation) {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "quote not approved",
+			Result:  nil,
+		}
+	}
+	if translation.GroupID != "" {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    409,
+			Message: "quote already in a translation group",
+			Result:  nil,
+		}
+	}
+	if quote.Language == "" || translation.Language == "" {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "quote language not set",
+			Result:  nil,
+		}
+	}
+	members, err := s.groupOf(quote)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	for _, member := range members {
+		if member.Language == translation.Language {
+			return models.ResponseModel{
+				Status:  false,
+				Code:    409,
+				Message: fmt.Sprintf("group already has a %s translation", translation.Language),
+				Result:  nil,
+			}
+		}
+	}
+	groupID := quote.GroupID
+	if groupID == "" {
+		groupID = quote.ID
+	}
+	vote := quote.Vote + translation.Vote
+	members = append(members, translation)
+	ids := make([]string, len(members))
+	for i := range members {
+		ids[i] = members[i].ID
+		members[i].GroupID = groupID
+		members[i].Vote = vote
+	}
+	if err := s.quoteRepo.SetGroup(ids, groupID, vote); err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "add translation success",
+		Result:  models.QuoteTranslationsModel{GroupID: groupID, Vote: vote, Quotes: members},
+	}
+}
+
+// RemoveTranslation takes the quote id out of its translation group.
+func (s *QuoteSrv) RemoveTranslation(userID string, role string, id string) (result models.ResponseModel) {
+	quote, err := s.quoteRepo.GetQuote(id)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    404,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if quote.CreatedBy != userID && role != models.RoleModerator && role != models.RoleAdmin {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    403,
+			Message: "forbidden",
+			Result:  nil,
+		}
+	}
+	if quote.GroupID == "" {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    404,
+			Message: "translation not found",
+			Result:  nil,
+		}
+	}
+	res, err := s.leaveGroup(quote)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "remove translation success",
+		Result:  res,
+	}
+}
+
+// indexSyncOverlap reads the changes of a moment before the last sync again,
+// so writes that were in flight or stamped by a clock running behind are not
+// missed. Indexing a quote twice does no harm.
+const indexSyncOverlap = time.Minute
+
+// ReindexQuotes loads every quote into the search and duplicate indexes, it runs on startup.
+func (s *QuoteSrv) ReindexQuotes() error {
+	s.indexMu.Lock()
+	defer s.indexMu.Unlock()
+	start := time.Now()
+	quotes, err := s.quoteRepo.GetQuotes(models.QuoteFilterModel{
+		Statuses:     []string{models.QuoteStatusApproved, models.QuoteStatusPending, models.QuoteStatusScheduled},
+		Translations: true,
+	})
+	if err != nil {
+		return err
+	}
+	for _, quote := range quotes {
+		if err := indexQuote(s.searchRepo, s.duplicateRepo, quote); err != nil {
+			return err
+		}
+	}
+	s.indexedAt = start
+	return nil
+}
+
+// SyncIndexes brings the in-memory indexes up to date with quotes written
+// since the last sync. Every instance keeps its own indexes, this is how one
+// learns about quotes that another instance created, moderated, published,
+// edited or deleted.
+func (s *QuoteSrv) SyncIndexes() error {
+	s.indexMu.Lock()
+	defer s.indexMu.Unlock()
+	start := time.Now()
+	quotes, err := s.quoteRepo.GetQuotesUpdatedSince(s.indexedAt.Add(-indexSyncOverlap))
+	if err != nil {
+		return err
+	}
+	for _, quote := range quotes {
+		if quote.DeletedAt != nil {
+			s.unindex(quote.ID)
+			continue
+		}
+		if err := indexQuote(s.searchRepo, s.duplicateRepo, quote); err != nil {
+			return err
+		}
+	}
+	s.indexedAt = start
+	return nil
+}
+
+// quoteApproved reports whether a quote is public, quotes stored before
+// moderation existed count as approved.
+func quoteApproved(quote models.QuoteModel) bool {
+	return quote.Status == models.QuoteStatusApproved || quote.Status == ""
+}
+
+// indexQuote keeps the in-memory indexes in step with a quote that was written.
+// Only approved quotes are searchable, pending ones still block duplicates.
+func indexQuote(searchRepo repositories.SearchRepository, duplicateRepo repositories.DuplicateRepository, quote models.QuoteModel) error {
+	if quoteApproved(quote) {
+		if err := searchRepo.Index(quote.ID, quote.Quote); err != nil {
+			return err
+		}
+	} else if err := searchRepo.Remove(quote.ID); err != nil {
+		return err
+	}
+	if quote.Status == models.QuoteStatusRejected {
+		return duplicateRepo.Remove(quote.ID)
+	}
+	return duplicateRepo.Index(quote.ID, quote.Quote)
+}
+
+func (s *QuoteSrv) index(quote models.QuoteModel) {
+	indexQuote(s.searchRepo, s.duplicateRepo, quote)
+}
+
+func (s *QuoteSrv) unindex(id string) {
+	s.searchRepo.Remove(id)
+	s.duplicateRepo.Remove(id)
+}
+
+// findDuplicate returns the existing quote most similar to text, if any is
+// similar enough to count as the same quote. An edited quote passes its own
+// id as except so it is not a duplicate of itself.
+func (s *QuoteSrv) findDuplicate(text string, except string) (models.DuplicateQuoteModel, bool) {
+	matches, err := s.duplicateRepo.FindSimilar(text, config.Env.DuplicateSimilarity)
+	if err != nil {
+		return models.DuplicateQuoteModel{}, false
+	}
+	for _, match := range matches {
+		if match.ID == except {
+			continue
+		}
+		if quote, err := s.quoteRepo.GetQuote(match.ID); err == nil {
+			return models.DuplicateQuoteModel{Existing: quote, Similarity: match.Similarity}, true
+		}
+	}
+	return models.DuplicateQuoteModel{}, false
+}
+
+func (s *QuoteSrv) GetDuplicates() (result models.ResponseModel) {
+	clusters, err := s.duplicateRepo.Clusters(config.Env.DuplicateSimilarity)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	res := []models.DuplicateClusterModel{}
+	for _, cluster := range clusters {
+		quotes, err := s.quoteRepo.GetQuotesByIDs(cluster.IDs)
+		if err != nil {
+			return models.ResponseModel{
+				Status:  false,
+				Code:    400,
+				Message: err.Error(),
+				Result:  nil,
+			}
+		}
+		if len(quotes) < 2 {
+			continue
+		}
+		cluster.Quotes = quotes
+		res = append(res, cluster)
+	}
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "get duplicates success",
+		Result:  res,
+	}
+}
+
+// MergeQuotes folds the source quotes into the target. The votes of the sources
+// are added to the target, their voters now vote for the target and the sources
+// go to the trash.
+func (s *QuoteSrv) MergeQuotes(userID string, targetID string, sourceIDs []string) (result models.ResponseModel) {
+	if targetID == "" || len(sourceIDs) == 0 {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "target_id or source_ids not found",
+			Result:  nil,
+		}
+	}
+	if utils.StringInSlice(sourceIDs, targetID) {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "cannot merge a quote into itself",
+			Result:  nil,
+		}
+	}
+	if _, err := s.quoteRepo.GetQuote(targetID); err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    404,
+			Message: "target quote not found",
+			Result:  nil,
+		}
+	}
+	sources := []models.QuoteModel{}
+	for _, id := range sourceIDs {
+		source, err := s.quoteRepo.GetQuote(id)
+		if err != nil {
+			return models.ResponseModel{
+				Status:  false,
+				Code:    404,
+				Message: "source quote not found",
+				Result:  nil,
+			}
+		}
+		sources = append(sources, source)
+	}
+	merged := []string{}
+	for _, source := range sources {
+		// a translation brings only its own votes
+		source, err := s.leaveGroup(source)
+		if err != nil {
+			return models.ResponseModel{
+				Status:  false,
+				Code:    400,
+				Message: err.Error(),
+				Result:  nil,
+			}
+		}
+		if _, err := s.userRepo.MoveVotes(source.ID, targetID); err != nil {
+			return models.ResponseModel{
+				Status:  false,
+				Code:    400,
+				Message: err.Error(),
+				Result:  nil,
+			}
+		}
+		if source.Vote > 0 {
+			if err := s.quoteRepo.IncrementVote(targetID, source.Vote); err != nil {
+				return models.ResponseModel{
+					Status:  false,
+					Code:    400,
+					Message: err.Error(),
+					Result:  nil,
+				}
+			}
+		}
+		if _, err := s.quoteRepo.SoftDeleteQuote(source.ID, userID); err != nil {
+			return models.ResponseModel{
+				Status:  false,
+				Code:    400,
+				Message: err.Error(),
+				Result:  nil,
+			}
+		}
+		s.unindex(source.ID)
+		merged = append(merged, source.ID)
+	}
+	target, err := s.quoteRepo.GetQuote(targetID)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "merge quotes success",
+		Result: models.MergeQuotesResultModel{
+			Quote:  target,
+			Merged: merged,
+		},
+	}
+}
+
+// MigrateQuotes backfills attribution fields on quotes created before they existed.
+func (s *QuoteSrv) MigrateQuotes() error {
+	migrated, err := s.quoteRepo.MigrateQuotes()
+	if err != nil {
+		return err
+	}
+	if migrated > 0 {
+		log.Printf("migrated %d quotes", migrated)
+	}
+	return nil
+}
+
+// EnsureIndexes creates the unique indexes ensureAuthor and ensureTags rely on
+// when requests create the same author or tag at once.
+func (s *QuoteSrv) EnsureIndexes() error {
+	if err := s.authorRepo.EnsureIndexes(); err != nil {
+		return err
+	}
+	return s.tagRepo.EnsureIndexes()
+}
@@ -1,6 +1,7 @@
 package services_test
 
 import (
+	"backend/config"
 	"backend/core/models"
 	"backend/core/repositories"
 	"backend/core/services"
@@ -11,8 +12,51 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
+// quoteRepos fills the repositories a test leaves out with bare mocks and the
+// in memory indexes.
+func quoteRepos(repos services.QuoteRepositories) services.QuoteRepositories {
+	if repos.Quote == nil {
+		repos.Quote = repositories.NewQuoteRepositoryMock()
+	}
+	if repos.Search == nil {
+		repos.Search = repositories.NewMemorySearchRepository()
+	}
+	if repos.Tag == nil {
+		repos.Tag = repositories.NewTagRepositoryMock()
+	}
+	if repos.Category == nil {
+		repos.Category = repositories.NewCategoryRepositoryMock()
+	}
+	if repos.Author == nil {
+		repos.Author = repositories.NewAuthorRepositoryMock()
+	}
+	if repos.Revision == nil {
+		repos.Revision = repositories.NewRevisionRepositoryMock()
+	}
+	if repos.User == nil {
+		repos.User = repositories.NewUserRepositoryMock()
+	}
+	if repos.Duplicate == nil {
+		repos.Duplicate = repositories.NewMemoryDuplicateRepository()
+	}
+	if repos.Filter == nil {
+		repos.Filter = repositories.NewFilterRepositoryMock()
+	}
+	if repos.Favorite == nil {
+		repos.Favorite = repositories.NewFavoriteRepositoryMock()
+	}
+	if repos.Comment == nil {
+		repos.Comment = repositories.NewCommentRepositoryMock()
+	}
+	if repos.Reaction == nil {
+		repos.Reaction = repositories.NewReactionRepositoryMock()
+	}
+	return repos
+}
+
 func Test_GetQuotes(t *testing.T) {
 	type test struct {
 		Name string
@@ -45,7 +89,7 @@ func Test_GetQuotes(t *testing.T) {
 				Status:  true,
 				Code:    200,
 				Message: "get quotes success",
-				Result:  []models.QuoteModel{},
+				Result:  models.QuoteListModel{Quotes: []models.QuoteModel{}},
 			},
 		},
 		{
@@ -75,16 +119,57 @@ func Test_GetQuotes(t *testing.T) {
 	for _, c := range cases {
 		t.Run(c.Name, func(t *testing.T) {
 			quoteRepo := repositories.NewQuoteRepositoryMock()
-			quoteRepo.On("GetQuotes").Return(c.Mock.GetQuotes.Output, c.Mock.GetQuotes.Error)
+			quoteRepo.On("GetQuotes", mock.Anything).Return(c.Mock.GetQuotes.Output, c.Mock.GetQuotes.Error)
 
-			quoteService := services.NewQuoteService(quoteRepo)
-			result := quoteService.GetQuotes()
+			quoteService := services.NewQuoteService(quoteRepos(services.QuoteRepositories{Quote: quoteRepo}))
+			result := quoteService.GetQuotes("", models.HandGetQuotesQueryModel{})
 
 			assert.Equal(t, c.Output, result)
 		})
 	}
 }
 
+func Test_GetQuotesPaging(t *testing.T) {
+	date := time.Now()
+	quotes := []models.QuoteModel{
+		{ID: "c", Vote: 5, CreateDate: date},
+		{ID: "b", Vote: 3, CreateDate: date},
+		{ID: "a", Vote: 3, CreateDate: date},
+	}
+
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	quoteRepo.On("GetQuotes", mock.MatchedBy(func(filter models.QuoteFilterModel) bool {
+		return filter.After == nil && filter.Limit == 3
+	})).Return(quotes, nil)
+	quoteRepo.On("GetQuotes", mock.MatchedBy(func(filter models.QuoteFilterModel) bool {
+		return filter.After != nil && filter.After.Vote == 3 && filter.After.ID == "b"
+	})).Return(quotes[2:], nil)
+	quoteRepo.On("CountQuotes", mock.Anything).Return(int64(3), nil)
+	quoteService := services.NewQuoteService(quoteRepos(services.QuoteRepositories{Quote: quoteRepo}))
+
+	first := quoteService.GetQuotes("", models.HandGetQuotesQueryModel{Limit: 2, Sort: "votes", IncludeTotal: true})
+	list := first.Result.(models.QuoteListModel)
+	assert.Equal(t, quotes[:2], list.Quotes)
+	assert.NotEmpty(t, list.NextCursor)
+	assert.Equal(t, int64(3), *list.Total)
+
+	second := quoteService.GetQuotes("", models.HandGetQuotesQueryModel{Limit: 2, Sort: "votes", Cursor: list.NextCursor})
+	list = second.Result.(models.QuoteListModel)
+	assert.Equal(t, quotes[2:], list.Quotes)
+	assert.Empty(t, list.NextCursor)
+	assert.Nil(t, list.Total)
+
+	// a cursor only works with the sort it was issued for
+	invalid := quoteService.GetQuotes("", models.HandGetQuotesQueryModel{Limit: 2, Sort: "update_date", Cursor: first.Result.(models.QuoteListModel).NextCursor})
+	assert.Equal(t, "cursor invalid", invalid.Message)
+
+	invalid = quoteService.GetQuotes("", models.HandGetQuotesQueryModel{Sort: "author"})
+	assert.Equal(t, "sort must be votes, create_date or update_date", invalid.Message)
+
+	invalid = quoteService.GetQuotes("", models.HandGetQuotesQueryModel{CreatedFrom: "yesterday"})
+	assert.Equal(t, "created_from invalid date format", invalid.Message)
+}
+
 func Test_CreateQuote(t *testing.T) {
 	type test struct {
 		Name  string
@@ -218,8 +303,8 @@ func Test_CreateQuote(t *testing.T) {
 			quoteRepo := repositories.NewQuoteRepositoryMock()
 			quoteRepo.On("CreateQuote", mock.Anything).Return(c.Mock.CreateQuote.Output, c.Mock.CreateQuote.Error)
 
-			quoteService := services.NewQuoteService(quoteRepo)
-			result := quoteService.CreateQuote(c.Input.Quote)
+			quoteService := services.NewQuoteService(quoteRepos(services.QuoteRepositories{Quote: quoteRepo}))
+			result := quoteService.CreateQuote(uuid.New().String(), true, models.HandCreateQuoteBodyModel{Quote: c.Input.Quote})
 
 			assert.Equal(t, c.Output, result)
 		})
@@ -473,15 +558,132 @@ func Test_UpdateQuote(t *testing.T) {
 		t.Run(c.Name, func(t *testing.T) {
 			quoteRepo := repositories.NewQuoteRepositoryMock()
 			quoteRepo.On("UpdateQuote", mock.Anything, mock.Anything).Return(c.Mock.UpdateQuote.Output, c.Mock.UpdateQuote.Error)
+			quoteRepo.On("GetQuote", mock.Anything).Return(models.QuoteModel{ID: c.Input.ID, Quote: "quote", CreatedBy: "user"}, nil)
+			revisionRepo := repositories.NewRevisionRepositoryMock()
+			revisionRepo.On("GetRevisions", mock.Anything).Return([]models.RevisionModel{{QuoteID: c.Input.ID, Rev: 1, Quote: "quote"}}, nil)
+			revisionRepo.On("CreateRevision", mock.Anything).Return(nil)
 
-			quoteService := services.NewQuoteService(quoteRepo)
-			result := quoteService.UpdateQuote(c.Input.ID, c.Input.Quote, c.Input.Vote)
+			quoteService := services.NewQuoteService(quoteRepos(services.QuoteRepositories{Quote: quoteRepo, Revision: revisionRepo}))
+			result := quoteService.UpdateQuote("user", models.RoleUser, false, c.Input.ID, models.HandUpdateQuoteBodyModel{Quote: c.Input.Quote, Vote: c.Input.Vote})
 
 			assert.Equal(t, c.Output, result)
 		})
 	}
 }
 
+func Test_UpdateQuoteModeration(t *testing.T) {
+	cases := []struct {
+		Name    string
+		UserID  string
+		Role    string
+		Trusted bool
+		Quote   string
+		Code    int
+		Status  string
+	}{
+		{
+			Name:   "stranger",
+			UserID: "stranger",
+			Role:   models.RoleUser,
+			Quote:  "Love the life you live!",
+			Code:   403,
+		},
+		{
+			// an approved quote can not be swapped for unreviewed text
+			Name:   "untrusted creator",
+			UserID: "owner",
+			Role:   models.RoleUser,
+			Quote:  "Love the life you live!",
+			Code:   200,
+			Status: models.QuoteStatusPending,
+		},
+		{
+			Name:    "trusted creator",
+			UserID:  "owner",
+			Role:    models.RoleUser,
+			Trusted: true,
+			Quote:   "Love the life you live!",
+			Code:    200,
+		},
+		{
+			Name:    "moderator",
+			UserID:  "moderator",
+			Role:    models.RoleModerator,
+			Trusted: true,
+			Quote:   "Love the life you live!",
+			Code:    200,
+		},
+		{
+			Name:    "duplicate of another quote",
+			UserID:  "owner",
+			Role:    models.RoleUser,
+			Trusted: true,
+			Quote:   "Peace comes from within, do not seek it without",
+			Code:    409,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			current := models.QuoteModel{ID: "1", Quote: "Love the life you live", Status: models.QuoteStatusApproved, CreatedBy: "owner"}
+			other := models.QuoteModel{ID: "2", Quote: "Peace comes from within. Do not seek it without.", Status: models.QuoteStatusApproved}
+			quoteRepo := repositories.NewQuoteRepositoryMock()
+			quoteRepo.On("GetQuote", "1").Return(current, nil)
+			quoteRepo.On("GetQuote", "2").Return(other, nil)
+			var payload models.UpdateQuoteModel
+			quoteRepo.On("UpdateQuote", "1", mock.Anything).Run(func(args mock.Arguments) {
+				payload = args.Get(1).(models.UpdateQuoteModel)
+			}).Return(models.QuoteModel{ID: "1", Quote: c.Quote}, nil)
+			revisionRepo := repositories.NewRevisionRepositoryMock()
+			revisionRepo.On("GetRevisions", "1").Return([]models.RevisionModel{{QuoteID: "1", Rev: 1, Quote: current.Quote}}, nil)
+			revisionRepo.On("CreateRevision", mock.Anything).Return(nil)
+			duplicateRepo := repositories.NewMemoryDuplicateRepository()
+			duplicateRepo.Index(current.ID, current.Quote)
+			duplicateRepo.Index(other.ID, other.Quote)
+
+			quoteService := services.NewQuoteService(quoteRepos(services.QuoteRepositories{Quote: quoteRepo, Revision: revisionRepo, Duplicate: duplicateRepo}))
+			result := quoteService.UpdateQuote(c.UserID, c.Role, c.Trusted, "1", models.HandUpdateQuoteBodyModel{Quote: c.Quote})
+			assert.Equal(t, c.Code, result.Code, result.Message)
+			if c.Code != 200 {
+				quoteRepo.AssertNotCalled(t, "UpdateQuote", mock.Anything, mock.Anything)
+				return
+			}
+			assert.Equal(t, c.Status, payload.Status)
+		})
+	}
+}
+
+func Test_UpdateQuoteVote(t *testing.T) {
+	cases := []struct {
+		Name     string
+		UserID   string
+		Role     string
+		Expected int
+	}{
+		{Name: "creator can not set the tally", UserID: "owner", Role: models.RoleUser, Expected: 0},
+		{Name: "moderator corrects the tally", UserID: "moderator", Role: models.RoleModerator, Expected: 99},
+		{Name: "admin corrects the tally", UserID: "admin", Role: models.RoleAdmin, Expected: 99},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			current := models.QuoteModel{ID: "1", Quote: "Love the life you live", Vote: 3, Status: models.QuoteStatusApproved, CreatedBy: "owner"}
+			quoteRepo := repositories.NewQuoteRepositoryMock()
+			quoteRepo.On("GetQuote", "1").Return(current, nil)
+			var payload models.UpdateQuoteModel
+			quoteRepo.On("UpdateQuote", "1", mock.Anything).Run(func(args mock.Arguments) {
+				payload = args.Get(1).(models.UpdateQuoteModel)
+			}).Return(current, nil)
+			revisionRepo := repositories.NewRevisionRepositoryMock()
+			revisionRepo.On("GetRevisions", "1").Return([]models.RevisionModel{{QuoteID: "1", Rev: 1, Quote: current.Quote}}, nil)
+			revisionRepo.On("CreateRevision", mock.Anything).Return(nil)
+
+			quoteService := services.NewQuoteService(quoteRepos(services.QuoteRepositories{Quote: quoteRepo, Revision: revisionRepo}))
+			result := quoteService.UpdateQuote(c.UserID, c.Role, true, "1", models.HandUpdateQuoteBodyModel{Quote: current.Quote, Vote: 99})
+			assert.Equal(t, 200, result.Code, result.Message)
+			assert.Equal(t, c.Expected, payload.Vote)
+		})
+	}
+}
+
 func Test_DeleteQuote(t *testing.T) {
 	type test struct {
 		Name  string
@@ -522,9 +724,10 @@ func Test_DeleteQuote(t *testing.T) {
 				}{
 					Input: id,
 					Output: models.QuoteModel{
-						ID:    id,
-						Quote: "quote",
-						Vote:  0,
+						ID:        id,
+						Quote:     "quote",
+						Vote:      0,
+						CreatedBy: "user",
 					},
 					Error: nil,
 				},
@@ -596,7 +799,7 @@ func Test_DeleteQuote(t *testing.T) {
 			},
 		},
 		{
-			Name:  "vote > 0",
+			Name:  "not owner",
 			Input: id,
 			Mock: struct {
 				GetQuote struct {
@@ -616,17 +819,18 @@ func Test_DeleteQuote(t *testing.T) {
 				}{
 					Input: id,
 					Output: models.QuoteModel{
-						ID:    id,
-						Quote: "quote",
-						Vote:  1,
+						ID:        id,
+						Quote:     "quote",
+						Vote:      1,
+						CreatedBy: "other",
 					},
 					Error: nil,
 				},
 			},
 			Output: models.ResponseModel{
 				Status:  false,
-				Code:    400,
-				Message: "cannot delete quote with vote > 0",
+				Code:    403,
+				Message: "forbidden",
 				Result:  nil,
 			},
 		},
@@ -651,9 +855,10 @@ func Test_DeleteQuote(t *testing.T) {
 				}{
 					Input: id,
 					Output: models.QuoteModel{
-						ID:    id,
-						Quote: "quote",
-						Vote:  0,
+						ID:        id,
+						Quote:     "quote",
+						Vote:      0,
+						CreatedBy: "user",
 					},
 					Error: nil,
 				},
@@ -677,12 +882,283 @@ func Test_DeleteQuote(t *testing.T) {
 		t.Run(c.Name, func(t *testing.T) {
 			quoteRepo := repositories.NewQuoteRepositoryMock()
 			quoteRepo.On("GetQuote", mock.Anything).Return(c.Mock.GetQuote.Output, c.Mock.GetQuote.Error)
-			quoteRepo.On("DeleteQuote", mock.Anything).Return(c.Mock.DeleteQuote.Error)
+			quoteRepo.On("SoftDeleteQuote", mock.Anything, mock.Anything).Return(c.Mock.GetQuote.Output, c.Mock.DeleteQuote.Error)
+			userRepo := repositories.NewUserRepositoryMock()
+			userRepo.On("ClearVotes", mock.Anything).Return(int64(0), nil)
 
-			quoteService := services.NewQuoteService(quoteRepo)
-			result := quoteService.DeleteQuote(c.Input)
+			quoteService := services.NewQuoteService(quoteRepos(services.QuoteRepositories{Quote: quoteRepo, User: userRepo}))
+			result := quoteService.DeleteQuote("user", models.RoleUser, c.Input)
 
 			assert.Equal(t, c.Output, result)
 		})
 	}
 }
+
+func Test_SearchQuotes(t *testing.T) {
+	quotes := []models.QuoteModel{
+		{ID: "1", Quote: "ความสุขคือการได้ทำสิ่งที่รัก"},
+		{ID: "2", Quote: "ชีวิตไม่ได้ง่าย แต่เราต้องสู้"},
+		{ID: "3", Quote: "Love the life you live, ชีวิตคือการเรียนรู้"},
+		{ID: "4", Quote: "<img src=x onerror=alert(1)> & all is well"},
+	}
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	quoteRepo.On("GetQuotes", mock.Anything).Return(quotes, nil)
+	quoteRepo.On("GetQuotesByIDs", mock.Anything).Return(quotes, nil)
+	quoteService := services.NewQuoteService(quoteRepos(services.QuoteRepositories{Quote: quoteRepo}))
+	assert.NoError(t, quoteService.ReindexQuotes())
+
+	type test struct {
+		Name      string
+		Input     string
+		IDs       []string
+		Highlight string
+	}
+	cases := []test{
+		{
+			Name:      "thai word",
+			Input:     "ชีวิต",
+			IDs:       []string{"2", "3"},
+			Highlight: "<mark>ชีวิต</mark>ไม่ได้ง่าย แต่เราต้องสู้",
+		},
+		{
+			Name:      "thai subword of compound",
+			Input:     "สุข",
+			IDs:       []string{"1"},
+			Highlight: "<mark>ความสุข</mark>คือการได้ทำสิ่งที่รัก",
+		},
+		{
+			Name:      "english case insensitive",
+			Input:     "LIFE",
+			IDs:       []string{"3"},
+			Highlight: "Love the <mark>life</mark> you live, ชีวิตคือการเรียนรู้",
+		},
+		{
+			// markup in the quote is text, only the marks are HTML
+			Name:      "markup escaped",
+			Input:     "alert",
+			IDs:       []string{"4"},
+			Highlight: "&lt;img src=x onerror=<mark>alert</mark>(1)&gt; &amp; all is well",
+		},
+		{
+			Name:  "no match",
+			Input: "hate",
+			IDs:   []string{},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			result := quoteService.SearchQuotes(c.Input, 10)
+			assert.Equal(t, "search quotes success", result.Message)
+
+			ids := []string{}
+			for _, r := range result.Result.([]models.QuoteSearchResultModel) {
+				ids = append(ids, r.Quote.ID)
+			}
+			assert.ElementsMatch(t, c.IDs, ids)
+			if c.Highlight != "" {
+				assert.Equal(t, c.Highlight, result.Result.([]models.QuoteSearchResultModel)[0].Highlight)
+			}
+		})
+	}
+
+	result := quoteService.SearchQuotes(" ", 10)
+	assert.Equal(t, "query not found", result.Message)
+}
+
+func Test_SyncIndexes(t *testing.T) {
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	quoteRepo.On("GetQuotes", mock.Anything).Return([]models.QuoteModel{
+		{ID: "1", Quote: "Love the life you live", Status: models.QuoteStatusApproved},
+		{ID: "2", Quote: "Morning has come", Status: models.QuoteStatusScheduled},
+	}, nil)
+	deletedAt := time.Now()
+	// another instance deleted quote 1 and published quote 2
+	quoteRepo.On("GetQuotesUpdatedSince", mock.MatchedBy(func(since time.Time) bool {
+		return time.Since(since) > time.Minute-time.Second && time.Since(since) < 2*time.Minute
+	})).Return([]models.QuoteModel{
+		{ID: "1", Quote: "Love the life you live", Status: models.QuoteStatusApproved, DeletedAt: &deletedAt},
+		{ID: "2", Quote: "Morning has come", Status: models.QuoteStatusApproved},
+	}, nil)
+	searchRepo := repositories.NewMemorySearchRepository()
+	duplicateRepo := repositories.NewMemoryDuplicateRepository()
+	quoteService := services.NewQuoteService(quoteRepos(services.QuoteRepositories{Quote: quoteRepo, Search: searchRepo, Duplicate: duplicateRepo}))
+	assert.NoError(t, quoteService.ReindexQuotes())
+	hits, _ := searchRepo.Search("morning", 10)
+	assert.Len(t, hits, 0)
+
+	assert.NoError(t, quoteService.SyncIndexes())
+	hits, _ = searchRepo.Search("morning", 10)
+	assert.Len(t, hits, 1)
+	hits, _ = searchRepo.Search("love", 10)
+	assert.Len(t, hits, 0)
+	similar, _ := duplicateRepo.FindSimilar("Love the life you live", 0.8)
+	assert.Len(t, similar, 0)
+}
+
+func Test_CreateQuoteTags(t *testing.T) {
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	tagRepo := repositories.NewTagRepositoryMock()
+	categoryRepo := repositories.NewCategoryRepositoryMock()
+	quoteRepo.On("CreateQuote", mock.MatchedBy(func(payload models.CreateQuoteModel) bool {
+		return assert.ObjectsAreEqual([]string{"self love", "life"}, payload.Tags) && payload.CategoryID == "poem"
+	})).Return(models.QuoteModel{ID: "1", Quote: "quote", Tags: []string{"self love", "life"}, CategoryID: "poem"}, nil)
+	tagRepo.On("GetTagByName", "self love").Return(models.TagModel{}, errors.New("not found"))
+	tagRepo.On("GetTagByName", "life").Return(models.TagModel{Name: "life"}, nil)
+	tagRepo.On("CreateTag", mock.MatchedBy(func(tag models.CreateTagModel) bool {
+		return tag.Name == "self love"
+	})).Return(models.TagModel{Name: "self love"}, nil)
+	categoryRepo.On("GetCategory", "poem").Return(models.CategoryModel{ID: "poem"}, nil)
+	categoryRepo.On("GetCategory", "unknown").Return(models.CategoryModel{}, errors.New("not found"))
+
+	quoteService := services.NewQuoteService(quoteRepos(services.QuoteRepositories{Quote: quoteRepo, Tag: tagRepo, Category: categoryRepo}))
+	result := quoteService.CreateQuote("user", true, models.HandCreateQuoteBodyModel{
+		Quote:      "quote",
+		Tags:       []string{"Self  Love", "life", "LIFE"},
+		CategoryID: "poem",
+	})
+	assert.Equal(t, "create quote success", result.Message)
+	tagRepo.AssertNumberOfCalls(t, "CreateTag", 1)
+
+	result = quoteService.CreateQuote("user", true, models.HandCreateQuoteBodyModel{Quote: "quote", CategoryID: "unknown"})
+	assert.Equal(t, "category not found", result.Message)
+
+	result = quoteService.CreateQuote("user", true, models.HandCreateQuoteBodyModel{Quote: "quote", Tags: []string{" "}})
+	assert.Equal(t, "tag name not found", result.Message)
+}
+
+func Test_CreateQuoteConcurrentCreate(t *testing.T) {
+	duplicate := mongo.WriteException{WriteErrors: mongo.WriteErrors{{Code: 11000, Message: "duplicate key"}}}
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	quoteRepo.On("CreateQuote", mock.MatchedBy(func(payload models.CreateQuoteModel) bool {
+		return payload.AuthorID == "buddha" && assert.ObjectsAreEqual([]string{"life"}, payload.Tags)
+	})).Return(models.QuoteModel{ID: "1", Quote: "quote", AuthorID: "buddha", Author: "Buddha", Tags: []string{"life"}}, nil)
+	// another request creates the author and the tag between the lookup and the insert
+	authorRepo := repositories.NewAuthorRepositoryMock()
+	authorRepo.On("GetAuthorByNameKey", "buddha").Return(models.AuthorModel{}, errors.New("not found")).Once()
+	authorRepo.On("GetAuthorByNameKey", "buddha").Return(models.AuthorModel{ID: "buddha", Name: "Buddha"}, nil)
+	authorRepo.On("CreateAuthor", mock.Anything).Return(models.AuthorModel{}, duplicate)
+	tagRepo := repositories.NewTagRepositoryMock()
+	tagRepo.On("GetTagByName", "life").Return(models.TagModel{}, errors.New("not found"))
+	tagRepo.On("CreateTag", mock.Anything).Return(models.TagModel{}, duplicate)
+
+	quoteService := services.NewQuoteService(quoteRepos(services.QuoteRepositories{Quote: quoteRepo, Tag: tagRepo, Author: authorRepo}))
+	result := quoteService.CreateQuote("user", true, models.HandCreateQuoteBodyModel{Quote: "quote", Author: "Buddha", Tags: []string{"life"}})
+	assert.Equal(t, "create quote success", result.Message)
+	quoteRepo.AssertNumberOfCalls(t, "CreateQuote", 1)
+}
+
+func Test_CreateQuoteAttribution(t *testing.T) {
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	authorRepo := repositories.NewAuthorRepositoryMock()
+	quoteRepo.On("CreateQuote", mock.MatchedBy(func(payload models.CreateQuoteModel) bool {
+		return payload.AuthorID == "buddha" && payload.Author == "Buddha" && payload.Language == "th" && payload.Source.Type == models.SourceTypeBook
+	})).Return(models.QuoteModel{ID: "1", Quote: "quote", AuthorID: "buddha", Author: "Buddha"}, nil)
+	authorRepo.On("GetAuthorByNameKey", "buddha").Return(models.AuthorModel{ID: "buddha", Name: "Buddha"}, nil)
+
+	quoteService := services.NewQuoteService(quoteRepos(services.QuoteRepositories{Quote: quoteRepo, Author: authorRepo}))
+	result := quoteService.CreateQuote("user", true, models.HandCreateQuoteBodyModel{
+		Quote:    "quote",
+		Author:   "  buddha ",
+		Source:   models.QuoteSourceModel{Type: "Book", Title: "Dhammapada"},
+		Language: "TH",
+		Year:     -500,
+	})
+	assert.Equal(t, "create quote success", result.Message)
+	authorRepo.AssertNotCalled(t, "CreateAuthor", mock.Anything)
+
+	cases := []struct {
+		Name     string
+		Input    models.HandCreateQuoteBodyModel
+		Expected string
+	}{
+		{
+			Name:     "unknown source type",
+			Input:    models.HandCreateQuoteBodyModel{Quote: "quote", Source: models.QuoteSourceModel{Type: "tweet"}},
+			Expected: "source type must be book, speech, url or other",
+		},
+		{
+			Name:     "url source without url",
+			Input:    models.HandCreateQuoteBodyModel{Quote: "quote", Source: models.QuoteSourceModel{Type: "url", Title: "blog"}},
+			Expected: "source url not found",
+		},
+		{
+			Name:     "invalid url",
+			Input:    models.HandCreateQuoteBodyModel{Quote: "quote", Source: models.QuoteSourceModel{Type: "url", URL: "not a url"}},
+			Expected: "source url invalid",
+		},
+		{
+			Name:     "invalid language",
+			Input:    models.HandCreateQuoteBodyModel{Quote: "quote", Language: "thai language"},
+			Expected: "language must be an ISO 639 code such as th or en",
+		},
+		{
+			Name:     "future year",
+			Input:    models.HandCreateQuoteBodyModel{Quote: "quote", Year: time.Now().Year() + 1},
+			Expected: "year invalid",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			result := quoteService.CreateQuote("user", true, c.Input)
+			assert.Equal(t, c.Expected, result.Message)
+		})
+	}
+}
+
+func Test_DeleteQuoteReleasesVotes(t *testing.T) {
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	userRepo := repositories.NewUserRepositoryMock()
+	search := repositories.NewMemorySearchRepository()
+	search.Index("1", "Love the life you live")
+	quoteRepo.On("GetQuote", "1").Return(models.QuoteModel{ID: "1", Quote: "Love the life you live", Vote: 5, CreatedBy: "owner"}, nil)
+	quoteRepo.On("SoftDeleteQuote", "1", "mod").Return(models.QuoteModel{ID: "1"}, nil)
+	userRepo.On("ClearVotes", "1").Return(int64(5), nil)
+
+	quoteService := services.NewQuoteService(quoteRepos(services.QuoteRepositories{Quote: quoteRepo, Search: search, User: userRepo}))
+	result := quoteService.DeleteQuote("mod", models.RoleModerator, "1")
+	assert.Equal(t, "delete quote success", result.Message)
+	userRepo.AssertCalled(t, "ClearVotes", "1")
+
+	hits, _ := search.Search("life", 10)
+	assert.Empty(t, hits)
+}
+
+func Test_RestoreQuote(t *testing.T) {
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	search := repositories.NewMemorySearchRepository()
+	quoteRepo.On("RestoreQuote", "1").Return(models.QuoteModel{ID: "1", Quote: "Love the life you live"}, nil)
+	quoteRepo.On("RestoreQuote", "2").Return(models.QuoteModel{}, errors.New("mongo: no documents in result"))
+
+	quoteService := services.NewQuoteService(quoteRepos(services.QuoteRepositories{Quote: quoteRepo, Search: search}))
+	result := quoteService.RestoreQuote("1")
+	assert.Equal(t, "restore quote success", result.Message)
+	hits, _ := search.Search("life", 10)
+	assert.Len(t, hits, 1)
+
+	result = quoteService.RestoreQuote("2")
+	assert.Equal(t, 404, result.Code)
+	assert.Equal(t, "quote not found in trash", result.Message)
+}
+
+func Test_PurgeTrash(t *testing.T) {
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	revisionRepo := repositories.NewRevisionRepositoryMock()
+	quoteRepo.On("GetQuotesDeletedBefore", mock.MatchedBy(func(cutoff time.Time) bool {
+		return cutoff.Before(time.Now().AddDate(0, 0, -config.Env.TrashRetentionDays+1))
+	})).Return([]models.QuoteModel{{ID: "1"}, {ID: "2"}}, nil)
+	quoteRepo.On("DeleteQuote", "1").Return(nil)
+	quoteRepo.On("DeleteQuote", "2").Return(errors.New("delete quote error"))
+	revisionRepo.On("DeleteRevisionsByQuote", "1").Return(nil)
+	favoriteRepo := repositories.NewFavoriteRepositoryMock()
+	favoriteRepo.On("DeleteFavoritesByQuote", "1").Return(nil)
+	commentRepo := repositories.NewCommentRepositoryMock()
+	commentRepo.On("DeleteCommentsByQuote", "1").Return(nil)
+	reactionRepo := repositories.NewReactionRepositoryMock()
+	reactionRepo.On("DeleteReactionsByQuote", "1").Return(nil)
+
+	quoteService := services.NewQuoteService(quoteRepos(services.QuoteRepositories{Quote: quoteRepo, Revision: revisionRepo, Favorite: favoriteRepo, Comment: commentRepo, Reaction: reactionRepo}))
+	result := quoteService.PurgeTrash()
+	assert.Equal(t, "purge trash success", result.Message)
+	assert.Equal(t, 1, result.Result)
+	revisionRepo.AssertNotCalled(t, "DeleteRevisionsByQuote", "2")
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"backend/config"
+	"backend/core/models"
+	"backend/core/repositories"
+	"log"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// randomOversample is how many more quotes a weighted draw samples from the
+// database than it returns, the weighting then picks among those.
+const randomOversample = 5
+
+type RandomQuoteService interface {
+	GetRandomQuotes(userID string, query models.HandRandomQuotesQueryModel) (result models.ResponseModel)
+}
+
+type RandomQuoteSrv struct {
+	quoteRepo repositories.QuoteRepository
+	seenRepo  repositories.SeenRepository
+}
+
+func NewRandomQuoteService(quoteRepo repositories.QuoteRepository, seenRepo repositories.SeenRepository) RandomQuoteService {
+	return &RandomQuoteSrv{
+		quoteRepo: quoteRepo,
+		seenRepo:  seenRepo,
+	}
+}
+
+// weightedSample draws count quotes without replacement, each with a chance
+// proportional to voteWeight (Efraimidis-Spirakis keys).
+func weightedSample(quotes []models.QuoteModel, count int) []models.QuoteModel {
+	keys := make(map[string]float64, len(quotes))
+	for _, quote := range quotes {
+		keys[quote.ID] = math.Pow(rand.Float64(), 1/float64(voteWeight(quote)))
+	}
+	sort.SliceStable(quotes, func(i, j int) bool { return keys[quotes[i].ID] > keys[quotes[j].ID] })
+	if len(quotes) > count {
+		quotes = quotes[:count]
+	}
+	return quotes
+}
+
+// sample draws until it has size distinct quotes or none are left, $sample may
+// return a quote more than once. Each round excludes the quotes already drawn
+// so it either adds a quote or shows there is nothing left.
+func (s *RandomQuoteSrv) sample(filter models.QuoteFilterModel, count int, weighted bool) (result []models.QuoteModel, err error) {
+	size := count
+	if weighted {
+		size = count * randomOversample
+	}
+	filter.ExcludeIDs = append([]string{}, filter.ExcludeIDs...)
+	seen := map[string]bool{}
+	for len(result) < size {
+		quotes, err := s.quoteRepo.GetRandomQuotes(filter, size-len(result))
+		if err != nil {
+			return result, err
+		}
+		added := 0
+		for _, quote := range quotes {
+			if !seen[quote.ID] {
+				seen[quote.ID] = true
+				filter.ExcludeIDs = append(filter.ExcludeIDs, quote.ID)
+				result = append(result, quote)
+				added++
+			}
+		}
+		if added == 0 {
+			break
+		}
+	}
+	if weighted {
+		return weightedSample(result, count), nil
+	}
+	if len(result) > count {
+		result = result[:count]
+	}
+	return result, nil
+}
+
+func (s *RandomQuoteSrv) GetRandomQuotes(userID string, query models.HandRandomQuotesQueryModel) (result models.ResponseModel) {
+	count := query.Count
+	if count < 1 {
+		count = 1
+	}
+	if count > maxPageLimit {
+		count = maxPageLimit
+	}
+	filter := models.QuoteFilterModel{
+		Language: strings.ToLower(query.Language),
+	}
+	filter.Translations = filter.Language != ""
+	for _, tag := range strings.Split(query.Tag, ",") {
+		if tag = normalizeTag(tag); tag != "" {
+			filter.Tags = append(filter.Tags, tag)
+		}
+	}
+	for _, id := range strings.Split(query.Exclude, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			filter.ExcludeIDs = append(filter.ExcludeIDs, id)
+		}
+	}
+	track := query.Track && userID != ""
+	excluded := len(filter.ExcludeIDs)
+	if track {
+		seen, err := s.seenRepo.GetSeenIDs(userID, config.Env.RandomSeenLimit)
+		if err != nil {
+			return models.ResponseModel{
+				Status:  false,
+				Code:    400,
+				Message: err.Error(),
+				Result:  nil,
+			}
+		}
+		filter.ExcludeIDs = append(filter.ExcludeIDs, seen...)
+	}
+
+	quotes, err := s.sample(filter, count, query.Weighted)
+	if err == nil && track && len(quotes) == 0 && len(filter.ExcludeIDs) > excluded {
+		// the caller has seen everything, start over
+		if err = s.seenRepo.DeleteSeenByUser(userID); err == nil {
+			filter.ExcludeIDs = filter.ExcludeIDs[:excluded]
+			quotes, err = s.sample(filter, count, query.Weighted)
+		}
+	}
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if quotes == nil {
+		quotes = []models.QuoteModel{}
+	}
+	if track {
+		ids := make([]string, len(quotes))
+		for i, quote := range quotes {
+			ids[i] = quote.ID
+		}
+		if err := s.seenRepo.AddSeen(userID, ids); err != nil {
+			log.Printf("track seen quotes of user %s failed: %v", userID, err)
+		}
+	}
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "get random quotes success",
+		Result:  models.RandomQuoteListModel{Quotes: quotes},
+	}
+}
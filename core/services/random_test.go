@@ -0,0 +1,74 @@
+package services_test
+
+import (
+	"backend/core/models"
+	"backend/core/repositories"
+	"backend/core/services"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_GetRandomQuotes(t *testing.T) {
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	quoteRepo.On("GetRandomQuotes", mock.MatchedBy(func(filter models.QuoteFilterModel) bool {
+		return filter.Language == "th" && assert.ObjectsAreEqual([]string{"life", "love"}, filter.Tags) && assert.ObjectsAreEqual([]string{"9"}, filter.ExcludeIDs)
+	}), 3).Return([]models.QuoteModel{{ID: "1"}, {ID: "2"}, {ID: "1"}}, nil)
+	// $sample may repeat a quote, the missing one is drawn again without the quotes already found
+	quoteRepo.On("GetRandomQuotes", mock.MatchedBy(func(filter models.QuoteFilterModel) bool {
+		return assert.ObjectsAreEqual([]string{"9", "1", "2"}, filter.ExcludeIDs)
+	}), 1).Return([]models.QuoteModel{{ID: "3"}}, nil)
+
+	randomService := services.NewRandomQuoteService(quoteRepo, repositories.NewSeenRepositoryMock())
+	result := randomService.GetRandomQuotes("user", models.HandRandomQuotesQueryModel{Count: 3, Tag: "Life, love", Language: "TH", Exclude: "9"})
+	assert.Equal(t, "get random quotes success", result.Message)
+	assert.Equal(t, []models.QuoteModel{{ID: "1"}, {ID: "2"}, {ID: "3"}}, result.Result.(models.RandomQuoteListModel).Quotes)
+}
+
+func Test_GetRandomQuotesWeighted(t *testing.T) {
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	quoteRepo.On("GetRandomQuotes", mock.Anything, 5).Return([]models.QuoteModel{
+		{ID: "popular", Vote: 100000000},
+		{ID: "a", Vote: -3},
+		{ID: "b"},
+	}, nil)
+	// fewer quotes than the oversample exist
+	quoteRepo.On("GetRandomQuotes", mock.Anything, 2).Return([]models.QuoteModel{}, nil)
+
+	randomService := services.NewRandomQuoteService(quoteRepo, repositories.NewSeenRepositoryMock())
+	for i := 0; i < 20; i++ {
+		result := randomService.GetRandomQuotes("user", models.HandRandomQuotesQueryModel{Count: 1, Weighted: true})
+		quotes := result.Result.(models.RandomQuoteListModel).Quotes
+		assert.Len(t, quotes, 1)
+		assert.Equal(t, "popular", quotes[0].ID)
+	}
+}
+
+func Test_GetRandomQuotesTracked(t *testing.T) {
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	seenRepo := repositories.NewSeenRepositoryMock()
+	seenRepo.On("GetSeenIDs", "user", mock.Anything).Return([]string{"1", "2"}, nil)
+	quoteRepo.On("GetRandomQuotes", mock.MatchedBy(func(filter models.QuoteFilterModel) bool {
+		return len(filter.ExcludeIDs) == 2
+	}), 1).Return([]models.QuoteModel{{ID: "3"}}, nil).Once()
+	seenRepo.On("AddSeen", "user", []string{"3"}).Return(nil)
+
+	randomService := services.NewRandomQuoteService(quoteRepo, seenRepo)
+	result := randomService.GetRandomQuotes("user", models.HandRandomQuotesQueryModel{Track: true})
+	assert.Equal(t, "3", result.Result.(models.RandomQuoteListModel).Quotes[0].ID)
+
+	// everything seen, the history starts over
+	quoteRepo.On("GetRandomQuotes", mock.MatchedBy(func(filter models.QuoteFilterModel) bool {
+		return len(filter.ExcludeIDs) == 2
+	}), 1).Return([]models.QuoteModel{}, nil).Once()
+	quoteRepo.On("GetRandomQuotes", mock.MatchedBy(func(filter models.QuoteFilterModel) bool {
+		return len(filter.ExcludeIDs) == 0
+	}), 1).Return([]models.QuoteModel{{ID: "1"}}, nil)
+	seenRepo.On("DeleteSeenByUser", "user").Return(nil)
+	seenRepo.On("AddSeen", "user", []string{"1"}).Return(nil)
+
+	result = randomService.GetRandomQuotes("user", models.HandRandomQuotesQueryModel{Track: true})
+	assert.Equal(t, "1", result.Result.(models.RandomQuoteListModel).Quotes[0].ID)
+	seenRepo.AssertCalled(t, "DeleteSeenByUser", "user")
+}
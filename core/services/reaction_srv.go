@@ -0,0 +1,232 @@
+package services
+
+import (
+	"backend/config"
+	"backend/core/models"
+	"backend/core/repositories"
+	"backend/utils"
+	"log"
+	"strings"
+	"time"
+)
+
+type ReactionService interface {
+	AddReaction(userID string, quoteID string, emoji string) (result models.ResponseModel)
+
+	RemoveReaction(userID string, quoteID string, emoji string) (result models.ResponseModel)
+
+	GetReactions(quoteID string) (result models.ResponseModel)
+}
+
+type ReactionSrv struct {
+	reactionRepo repositories.ReactionRepository
+	quoteRepo    repositories.QuoteRepository
+	userRepo     repositories.UserRepository
+}
+
+func NewReactionService(reactionRepo repositories.ReactionRepository, quoteRepo repositories.QuoteRepository, userRepo repositories.UserRepository) ReactionService {
+	return &ReactionSrv{
+		reactionRepo: reactionRepo,
+		quoteRepo:    quoteRepo,
+		userRepo:     userRepo,
+	}
+}
+
+func reactionEmojis() []string {
+	emojis := []string{}
+	for _, emoji := range strings.Split(config.Env.ReactionEmojis, ",") {
+		if emoji = strings.TrimSpace(emoji); emoji != "" {
+			emojis = append(emojis, emoji)
+		}
+	}
+	return emojis
+}
+
+// reactionEmoji finds emoji in the configured set, emoji typed with or without
+// a variation selector match the same entry.
+func reactionEmoji(emoji string) (string, bool) {
+	want := strings.ReplaceAll(strings.TrimSpace(emoji), "\ufe0f", "")
+	for _, allowed := range reactionEmojis() {
+		if strings.ReplaceAll(allowed, "\ufe0f", "") == want {
+			return allowed, true
+		}
+	}
+	return "", false
+}
+
+func (s *ReactionSrv) AddReaction(userID string, quoteID string, emoji string) (result models.ResponseModel) {
+	emoji, ok := reactionEmoji(emoji)
+	if !ok {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "emoji must be one of " + strings.Join(reactionEmojis(), " "),
+			Result:  nil,
+		}
+	}
+	quote, err := s.quoteRepo.GetQuote(quoteID)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    404,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if !quoteApproved(quote) {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "quote not approved",
+			Result:  nil,
+		}
+	}
+	created, err := s.reactionRepo.AddReaction(models.CreateReactionModel{
+		QuoteID:    quote.ID,
+		UserID:     userID,
+		Emoji:      emoji,
+		CreateDate: time.Now(),
+	})
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if !created {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    409,
+			Message: "reaction already exist",
+			Result:  nil,
+		}
+	}
+	if err := s.quoteRepo.IncrementReaction(quote.ID, emoji, 1); err != nil {
+		log.Printf("count reaction of quote %s failed: %v", quote.ID, err)
+	}
+	if quote.Reactions == nil {
+		quote.Reactions = map[string]int{}
+	}
+	quote.Reactions[emoji]++
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "add reaction success",
+		Result:  quote,
+	}
+}
+
+func (s *ReactionSrv) RemoveReaction(userID string, quoteID string, emoji string) (result models.ResponseModel) {
+	emoji, ok := reactionEmoji(emoji)
+	if !ok {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    404,
+			Message: "reaction not found",
+			Result:  nil,
+		}
+	}
+	removed, err := s.reactionRepo.RemoveReaction(quoteID, userID, emoji)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if !removed {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    404,
+			Message: "reaction not found",
+			Result:  nil,
+		}
+	}
+	if err := s.quoteRepo.IncrementReaction(quoteID, emoji, -1); err != nil {
+		log.Printf("count reaction of quote %s failed: %v", quoteID, err)
+	}
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "remove reaction success",
+		Result:  nil,
+	}
+}
+
+// GetReactions lists who reacted to a quote, grouped by emoji in the order of
+// the configured set. Emoji dropped from the set are listed last.
+func (s *ReactionSrv) GetReactions(quoteID string) (result models.ResponseModel) {
+	quote, err := s.quoteRepo.GetQuote(quoteID)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    404,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	reactions, err := s.reactionRepo.GetReactions(quote.ID)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	userIDs := []string{}
+	for _, reaction := range reactions {
+		userIDs = append(userIDs, reaction.UserID)
+	}
+	users := map[string]models.UserModel{}
+	if len(userIDs) > 0 {
+		res, err := s.userRepo.GetUsersByIDs(userIDs)
+		if err != nil {
+			return models.ResponseModel{
+				Status:  false,
+				Code:    400,
+				Message: err.Error(),
+				Result:  nil,
+			}
+		}
+		for _, user := range res {
+			users[user.ID] = user
+		}
+	}
+
+	order := reactionEmojis()
+	groups := map[string]*models.ReactionBreakdownModel{}
+	for _, reaction := range reactions {
+		group, ok := groups[reaction.Emoji]
+		if !ok {
+			group = &models.ReactionBreakdownModel{Emoji: reaction.Emoji, Users: []models.ReactorModel{}}
+			groups[reaction.Emoji] = group
+			if !utils.StringInSlice(order, reaction.Emoji) {
+				order = append(order, reaction.Emoji)
+			}
+		}
+		user := users[reaction.UserID]
+		group.Count++
+		group.Users = append(group.Users, models.ReactorModel{
+			UserID:      reaction.UserID,
+			DisplayName: user.DisplayName,
+			AvatarURL:   user.AvatarURL,
+			CreateDate:  reaction.CreateDate,
+		})
+	}
+	res := models.QuoteReactionsModel{QuoteID: quote.ID, Reactions: []models.ReactionBreakdownModel{}}
+	for _, emoji := range order {
+		if group, ok := groups[emoji]; ok {
+			res.Reactions = append(res.Reactions, *group)
+		}
+	}
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "get reactions success",
+		Result:  res,
+	}
+}
@@ -0,0 +1,90 @@
+package services_test
+
+import (
+	"backend/core/models"
+	"backend/core/repositories"
+	"backend/core/services"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_AddReaction(t *testing.T) {
+	reactionRepo := repositories.NewReactionRepositoryMock()
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	quoteRepo.On("GetQuote", "1").Return(models.QuoteModel{ID: "1", Status: models.QuoteStatusApproved, Reactions: map[string]int{"👍": 2}}, nil)
+	quoteRepo.On("GetQuote", "pending").Return(models.QuoteModel{ID: "pending", Status: models.QuoteStatusPending}, nil)
+	quoteRepo.On("IncrementReaction", "1", mock.Anything, 1).Return(nil)
+	reactionRepo.On("AddReaction", mock.MatchedBy(func(reaction models.CreateReactionModel) bool {
+		return reaction.UserID == "user" && reaction.QuoteID == "1" && reaction.Emoji == "❤️"
+	})).Return(true, nil).Once()
+	reactionRepo.On("AddReaction", mock.Anything).Return(false, nil)
+
+	reactionService := services.NewReactionService(reactionRepo, quoteRepo, repositories.NewUserRepositoryMock())
+	// a heart typed without the variation selector is the same reaction
+	result := reactionService.AddReaction("user", "1", "❤")
+	assert.Equal(t, "add reaction success", result.Message)
+	assert.Equal(t, map[string]int{"👍": 2, "❤️": 1}, result.Result.(models.QuoteModel).Reactions)
+	quoteRepo.AssertCalled(t, "IncrementReaction", "1", "❤️", 1)
+
+	result = reactionService.AddReaction("user", "1", "❤️")
+	assert.Equal(t, 409, result.Code)
+	assert.Equal(t, "reaction already exist", result.Message)
+	quoteRepo.AssertNumberOfCalls(t, "IncrementReaction", 1)
+
+	result = reactionService.AddReaction("user", "1", "🍕")
+	assert.Equal(t, 400, result.Code)
+	assert.Contains(t, result.Message, "emoji must be one of")
+
+	result = reactionService.AddReaction("user", "pending", "👍")
+	assert.Equal(t, "quote not approved", result.Message)
+}
+
+func Test_RemoveReaction(t *testing.T) {
+	reactionRepo := repositories.NewReactionRepositoryMock()
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	reactionRepo.On("RemoveReaction", "1", "user", "👍").Return(true, nil).Once()
+	reactionRepo.On("RemoveReaction", "1", "user", "👍").Return(false, nil)
+	quoteRepo.On("IncrementReaction", "1", "👍", -1).Return(nil)
+
+	reactionService := services.NewReactionService(reactionRepo, quoteRepo, repositories.NewUserRepositoryMock())
+	result := reactionService.RemoveReaction("user", "1", "👍")
+	assert.Equal(t, "remove reaction success", result.Message)
+
+	result = reactionService.RemoveReaction("user", "1", "👍")
+	assert.Equal(t, "reaction not found", result.Message)
+	quoteRepo.AssertNumberOfCalls(t, "IncrementReaction", 1)
+
+	result = reactionService.RemoveReaction("user", "1", "🍕")
+	assert.Equal(t, 404, result.Code)
+}
+
+func Test_GetReactions(t *testing.T) {
+	reactionRepo := repositories.NewReactionRepositoryMock()
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	userRepo := repositories.NewUserRepositoryMock()
+	now := time.Now()
+	quoteRepo.On("GetQuote", "1").Return(models.QuoteModel{ID: "1"}, nil)
+	reactionRepo.On("GetReactions", "1").Return([]models.ReactionModel{
+		{QuoteID: "1", UserID: "a", Emoji: "😂", CreateDate: now},
+		{QuoteID: "1", UserID: "b", Emoji: "👍", CreateDate: now},
+		{QuoteID: "1", UserID: "a", Emoji: "👍", CreateDate: now},
+	}, nil)
+	userRepo.On("GetUsersByIDs", []string{"a", "b", "a"}).Return([]models.UserModel{
+		{ID: "a", DisplayName: "Alice"},
+		{ID: "b", DisplayName: "Bob"},
+	}, nil)
+
+	reactionService := services.NewReactionService(reactionRepo, quoteRepo, userRepo)
+	result := reactionService.GetReactions("1")
+	assert.Equal(t, "get reactions success", result.Message)
+	res := result.Result.(models.QuoteReactionsModel)
+	assert.Len(t, res.Reactions, 2)
+	assert.Equal(t, "👍", res.Reactions[0].Emoji)
+	assert.Equal(t, 2, res.Reactions[0].Count)
+	assert.Equal(t, "Bob", res.Reactions[0].Users[0].DisplayName)
+	assert.Equal(t, "😂", res.Reactions[1].Emoji)
+	assert.Equal(t, "Alice", res.Reactions[1].Users[0].DisplayName)
+}
@@ -0,0 +1,163 @@
+package services_test
+
+import (
+	"backend/config"
+	"backend/core/models"
+	"backend/core/repositories"
+	"backend/core/services"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_GetRevisions(t *testing.T) {
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	revisionRepo := repositories.NewRevisionRepositoryMock()
+	quoteRepo.On("GetQuote", "1").Return(models.QuoteModel{ID: "1", Quote: "Love the life you have", CreatedBy: "owner"}, nil)
+	quoteRepo.On("GetQuote", "2").Return(models.QuoteModel{ID: "2", Quote: "never edited", CreatedBy: "owner"}, nil)
+	revisionRepo.On("GetRevisions", "1").Return([]models.RevisionModel{
+		{QuoteID: "1", Rev: 1, Quote: "Love the life you live"},
+		{QuoteID: "1", Rev: 2, Quote: "Love the life you have"},
+	}, nil)
+	revisionRepo.On("GetRevisions", "2").Return([]models.RevisionModel{}, nil)
+
+	quoteService := services.NewQuoteService(quoteRepos(services.QuoteRepositories{Quote: quoteRepo, Revision: revisionRepo}))
+	result := quoteService.GetRevisions("1", 1, 2)
+	assert.Equal(t, "get revisions success", result.Message)
+	res := result.Result.(models.QuoteRevisionsModel)
+	assert.Len(t, res.Revisions, 2)
+	assert.Equal(t, []models.DiffPartModel{
+		{Op: "equal", Text: "Love the life you "},
+		{Op: "delete", Text: "live"},
+		{Op: "insert", Text: "have"},
+	}, res.Diff.Parts)
+
+	result = quoteService.GetRevisions("1", 1, 3)
+	assert.Equal(t, "revision not found", result.Message)
+
+	result = quoteService.GetRevisions("2", 0, 0)
+	res = result.Result.(models.QuoteRevisionsModel)
+	assert.Len(t, res.Revisions, 1)
+	assert.Equal(t, "never edited", res.Revisions[0].Quote)
+	assert.Equal(t, "owner", res.Revisions[0].EditedBy)
+	assert.Nil(t, res.Diff)
+}
+
+func Test_RevertQuote(t *testing.T) {
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	revisionRepo := repositories.NewRevisionRepositoryMock()
+	quoteRepo.On("GetQuote", "1").Return(models.QuoteModel{ID: "1", Quote: "Love the life you have", Vote: 3}, nil)
+	quoteRepo.On("UpdateQuote", "1", mock.MatchedBy(func(payload models.UpdateQuoteModel) bool {
+		return payload.Quote == "Love the life you live"
+	})).Return(models.QuoteModel{ID: "1", Quote: "Love the life you live", Vote: 3}, nil)
+	revisionRepo.On("GetRevisions", "1").Return([]models.RevisionModel{
+		{QuoteID: "1", Rev: 1, Quote: "Love the life you live"},
+		{QuoteID: "1", Rev: 2, Quote: "Love the life you have"},
+	}, nil)
+	revisionRepo.On("CreateRevision", mock.MatchedBy(func(revision models.CreateRevisionModel) bool {
+		return revision.Rev == 3 && revision.EditedBy == "editor" && revision.Reason == "revert to revision 1"
+	})).Return(nil)
+
+	quoteService := services.NewQuoteService(quoteRepos(services.QuoteRepositories{Quote: quoteRepo, Revision: revisionRepo}))
+	result := quoteService.RevertQuote("editor", models.RoleModerator, true, "1", 1)
+	assert.Equal(t, "revert quote success", result.Message)
+	revisionRepo.AssertNumberOfCalls(t, "CreateRevision", 1)
+
+	result = quoteService.RevertQuote("editor", models.RoleModerator, true, "1", 2)
+	assert.Equal(t, "quote already matches revision", result.Message)
+
+	result = quoteService.RevertQuote("editor", models.RoleModerator, true, "1", 9)
+	assert.Equal(t, "revision not found", result.Message)
+}
+
+func Test_UpdateQuoteRevisionVotes(t *testing.T) {
+	defer func(policy string) { config.Env.RevisionVotes = policy }(config.Env.RevisionVotes)
+	cases := []struct {
+		Name   string
+		Policy string
+		Quote  string
+		Reset  bool
+	}{
+		{
+			Name:   "keep policy",
+			Policy: models.RevisionVotesKeep,
+			Quote:  "Something else entirely",
+			Reset:  false,
+		},
+		{
+			Name:   "reset policy small edit",
+			Policy: models.RevisionVotesReset,
+			Quote:  "Love the life you live!",
+			Reset:  false,
+		},
+		{
+			Name:   "reset policy new meaning",
+			Policy: models.RevisionVotesReset,
+			Quote:  "Something else entirely",
+			Reset:  true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			config.Env.RevisionVotes = c.Policy
+			quoteRepo := repositories.NewQuoteRepositoryMock()
+			revisionRepo := repositories.NewRevisionRepositoryMock()
+			userRepo := repositories.NewUserRepositoryMock()
+			quoteRepo.On("GetQuote", "1").Return(models.QuoteModel{ID: "1", Quote: "Love the life you live", Vote: 3, CreatedBy: "owner", CreateDate: time.Now()}, nil)
+			quoteRepo.On("UpdateQuote", "1", mock.Anything).Return(models.QuoteModel{ID: "1", Quote: c.Quote, Vote: 3}, nil)
+			quoteRepo.On("ResetVote", "1").Return(nil)
+			userRepo.On("ClearVotes", "1").Return(int64(3), nil)
+			revisionRepo.On("GetRevisions", "1").Return([]models.RevisionModel{}, nil)
+			revisionRepo.On("CreateRevision", mock.Anything).Return(nil)
+
+			quoteService := services.NewQuoteService(quoteRepos(services.QuoteRepositories{Quote: quoteRepo, Revision: revisionRepo, User: userRepo}))
+			result := quoteService.UpdateQuote("editor", models.RoleModerator, true, "1", models.HandUpdateQuoteBodyModel{Quote: c.Quote, Reason: "typo"})
+			assert.Equal(t, "update quote success", result.Message)
+			revisionRepo.AssertCalled(t, "CreateRevision", mock.MatchedBy(func(revision models.CreateRevisionModel) bool {
+				return revision.Rev == 1 && revision.EditedBy == "owner"
+			}))
+			revisionRepo.AssertCalled(t, "CreateRevision", mock.MatchedBy(func(revision models.CreateRevisionModel) bool {
+				return revision.Rev == 2 && revision.EditedBy == "editor" && revision.Reason == "typo" && revision.VoteReset == c.Reset
+			}))
+			if c.Reset {
+				assert.Equal(t, 0, result.Result.(models.QuoteModel).Vote)
+				userRepo.AssertCalled(t, "ClearVotes", "1")
+			} else {
+				assert.Equal(t, 3, result.Result.(models.QuoteModel).Vote)
+				quoteRepo.AssertNotCalled(t, "ResetVote", "1")
+			}
+		})
+	}
+}
+
+func Test_RevertQuoteChecks(t *testing.T) {
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	revisionRepo := repositories.NewRevisionRepositoryMock()
+	quoteRepo.On("GetQuote", "1").Return(models.QuoteModel{ID: "1", Quote: "be kind", Status: models.QuoteStatusApproved, CreatedBy: "owner"}, nil)
+	quoteRepo.On("UpdateQuote", "1", mock.MatchedBy(func(payload models.UpdateQuoteModel) bool {
+		return payload.Quote == "be gentle" && payload.Status == models.QuoteStatusPending
+	})).Return(models.QuoteModel{ID: "1", Quote: "be gentle", Status: models.QuoteStatusPending}, nil)
+	revisionRepo.On("GetRevisions", "1").Return([]models.RevisionModel{
+		{QuoteID: "1", Rev: 1, Quote: "be kind, damn it"},
+		{QuoteID: "1", Rev: 2, Quote: "be gentle"},
+		{QuoteID: "1", Rev: 3, Quote: "be kind"},
+	}, nil)
+	revisionRepo.On("CreateRevision", mock.Anything).Return(nil)
+	quoteService := newFilteredQuoteService(quoteRepo, revisionRepo, models.FilterRulesModel{
+		Phrases: map[string]models.FilterPhrasesModel{"*": {Reject: []string{"damn"}}},
+	})
+
+	result := quoteService.RevertQuote("stranger", models.RoleUser, false, "1", 2)
+	assert.Equal(t, 403, result.Code)
+
+	// text that the rules reject today can not come back through a revert
+	result = quoteService.RevertQuote("owner", models.RoleUser, false, "1", 1)
+	assert.Equal(t, "quote rejected by content filter", result.Message)
+
+	result = quoteService.RevertQuote("owner", models.RoleUser, false, "1", 2)
+	assert.Equal(t, "revert quote success", result.Message)
+	assert.Equal(t, models.QuoteStatusPending, result.Result.(models.QuoteModel).Status)
+	quoteRepo.AssertNumberOfCalls(t, "UpdateQuote", 1)
+}
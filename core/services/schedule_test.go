@@ -0,0 +1,100 @@
+package services_test
+
+import (
+	"backend/core/models"
+	"backend/core/repositories"
+	"backend/core/services"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_CreateQuoteScheduled(t *testing.T) {
+	type test struct {
+		Name      string
+		PublishAt string
+		Trusted   bool
+		Status    string
+		Message   string
+	}
+	future := time.Now().Add(48 * time.Hour)
+	cases := []test{
+		{Name: "rfc 3339", PublishAt: future.Format(time.RFC3339), Trusted: true, Status: models.QuoteStatusScheduled, Message: "create quote success"},
+		{Name: "date time format", PublishAt: future.Format("2006-01-02 15:04:05"), Trusted: true, Status: models.QuoteStatusScheduled, Message: "create quote success"},
+		{Name: "past time", PublishAt: "2020-01-01T00:00:00Z", Trusted: true, Status: models.QuoteStatusApproved, Message: "create quote success"},
+		{Name: "untrusted waits for moderation", PublishAt: future.Format(time.RFC3339), Trusted: false, Status: models.QuoteStatusPending, Message: "create quote success"},
+		{Name: "date only", PublishAt: "2030-01-01", Trusted: true, Message: "publish_at must be RFC 3339 or YYYY-MM-DD HH:MM:SS"},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			quoteRepo := repositories.NewQuoteRepositoryMock()
+			quoteRepo.On("CreateQuote", mock.MatchedBy(func(payload models.CreateQuoteModel) bool {
+				return payload.Status == c.Status && payload.PublishAt != nil
+			})).Return(models.QuoteModel{ID: "1", Quote: "scheduled quote", Status: c.Status}, nil)
+
+			searchRepo := repositories.NewMemorySearchRepository()
+			quoteService := services.NewQuoteService(quoteRepos(services.QuoteRepositories{Quote: quoteRepo, Search: searchRepo}))
+			result := quoteService.CreateQuote("editor", c.Trusted, models.HandCreateQuoteBodyModel{Quote: "scheduled quote", PublishAt: c.PublishAt})
+			assert.Equal(t, c.Message, result.Message)
+			if c.Status == models.QuoteStatusScheduled {
+				hits, _ := searchRepo.Search("scheduled", 10)
+				assert.Len(t, hits, 0)
+			}
+		})
+	}
+}
+
+func Test_UpdateQuotePublishAt(t *testing.T) {
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	revisionRepo := repositories.NewRevisionRepositoryMock()
+	quoteRepo.On("GetQuote", "scheduled").Return(models.QuoteModel{ID: "scheduled", Quote: "Not yet", Status: models.QuoteStatusScheduled}, nil)
+	quoteRepo.On("GetQuote", "public").Return(models.QuoteModel{ID: "public", Quote: "Already out", Status: models.QuoteStatusApproved}, nil)
+	quoteRepo.On("UpdateQuote", "scheduled", mock.MatchedBy(func(payload models.UpdateQuoteModel) bool {
+		return payload.Status == models.QuoteStatusApproved && payload.PublishAt != nil
+	})).Return(models.QuoteModel{ID: "scheduled", Quote: "Not yet", Status: models.QuoteStatusApproved}, nil)
+	revisionRepo.On("GetRevisions", mock.Anything).Return([]models.RevisionModel{}, nil)
+	revisionRepo.On("CreateRevision", mock.Anything).Return(nil)
+
+	quoteService := services.NewQuoteService(quoteRepos(services.QuoteRepositories{Quote: quoteRepo, Revision: revisionRepo}))
+	now := ""
+	// an empty publish_at publishes a scheduled quote right away
+	result := quoteService.UpdateQuote("editor", models.RoleModerator, true, "scheduled", models.HandUpdateQuoteBodyModel{Quote: "Not yet", PublishAt: &now})
+	assert.Equal(t, "update quote success", result.Message)
+
+	later := "2099-01-01 09:00:00"
+	result = quoteService.UpdateQuote("editor", models.RoleModerator, true, "public", models.HandUpdateQuoteBodyModel{Quote: "Already out", PublishAt: &later})
+	assert.Equal(t, "quote already published", result.Message)
+}
+
+func Test_PublishScheduledQuotes(t *testing.T) {
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	notificationRepo := repositories.NewNotificationRepositoryMock()
+	leaseRepo := repositories.NewLeaseRepositoryMock()
+	leaseRepo.On("AcquireLease", "publish_scheduled_quotes", mock.Anything, mock.Anything).Return(true, nil).Once()
+	leaseRepo.On("AcquireLease", "publish_scheduled_quotes", mock.Anything, mock.Anything).Return(false, nil)
+	leaseRepo.On("ReleaseLease", "publish_scheduled_quotes", mock.Anything).Return(nil)
+	quoteRepo.On("GetDueQuotes", mock.Anything).Return([]models.QuoteModel{{ID: "1"}, {ID: "2"}}, nil)
+	quoteRepo.On("PublishQuote", "1").Return(models.QuoteModel{ID: "1", Quote: "Morning has come", Status: models.QuoteStatusApproved, CreatedBy: "editor"}, nil)
+	// published by another run in the meantime
+	quoteRepo.On("PublishQuote", "2").Return(models.QuoteModel{}, errors.New("mongo: no documents in result"))
+	notificationRepo.On("CreateNotification", mock.MatchedBy(func(notification models.CreateNotificationModel) bool {
+		return notification.UserID == "editor" && notification.Type == models.NotificationQuotePublished
+	})).Return(nil)
+
+	searchRepo := repositories.NewMemorySearchRepository()
+	moderationService := services.NewModerationService(quoteRepo, searchRepo, repositories.NewMemoryDuplicateRepository(), notificationRepo, repositories.NewReportRepositoryMock(), repositories.NewUserRepositoryMock(), leaseRepo)
+	result := moderationService.PublishScheduledQuotes()
+	assert.Equal(t, "publish scheduled quotes success", result.Message)
+	assert.Equal(t, 1, result.Result)
+	hits, _ := searchRepo.Search("morning", 10)
+	assert.Len(t, hits, 1)
+	notificationRepo.AssertNumberOfCalls(t, "CreateNotification", 1)
+	leaseRepo.AssertCalled(t, "ReleaseLease", "publish_scheduled_quotes", mock.Anything)
+
+	result = moderationService.PublishScheduledQuotes()
+	assert.Equal(t, "publish scheduled quotes running elsewhere", result.Message)
+	quoteRepo.AssertNumberOfCalls(t, "GetDueQuotes", 1)
+}
@@ -0,0 +1,308 @@
+package services
+
+import (
+	"backend/core/models"
+	"backend/core/repositories"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	maxTagLength    = 30
+	maxTagsPerQuote = 10
+)
+
+type TagService interface {
+	GetTags() (result models.ResponseModel)
+
+	CreateTag(name string) (result models.ResponseModel)
+
+	RenameTag(id string, name string) (result models.ResponseModel)
+
+	MergeTag(id string, targetID string) (result models.ResponseModel)
+}
+
+type TagSrv struct {
+	tagRepo   repositories.TagRepository
+	quoteRepo repositories.QuoteRepository
+}
+
+func NewTagService(tagRepo repositories.TagRepository, quoteRepo repositories.QuoteRepository) TagService {
+	return &TagSrv{
+		tagRepo:   tagRepo,
+		quoteRepo: quoteRepo,
+	}
+}
+
+// normalizeTag lowercases a tag and collapses its inner whitespace.
+func normalizeTag(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+func validateTag(name string) error {
+	if name == "" {
+		return errors.New("tag name not found")
+	}
+	if utf8.RuneCountInString(name) > maxTagLength {
+		return fmt.Errorf("tag name must be <= %d characters", maxTagLength)
+	}
+	return nil
+}
+
+// normalizeTags validates the tags of a quote and drops duplicates.
+func normalizeTags(tags []string) ([]string, error) {
+	result := []string{}
+	seen := map[string]bool{}
+	for _, tag := range tags {
+		name := normalizeTag(tag)
+		if err := validateTag(name); err != nil {
+			return nil, err
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		result = append(result, name)
+	}
+	if len(result) > maxTagsPerQuote {
+		return nil, fmt.Errorf("a quote can have at most %d tags", maxTagsPerQuote)
+	}
+	return result, nil
+}
+
+// ensureTags creates the tags that are used for the first time.
+func ensureTags(tagRepo repositories.TagRepository, names []string) error {
+	for _, name := range names {
+		if _, err := tagRepo.GetTagByName(name); err == nil {
+			continue
+		}
+		_, err := tagRepo.CreateTag(models.CreateTagModel{
+			ID:         uuid.New().String(),
+			Name:       name,
+			CreateDate: time.Now(),
+			UpdateDate: time.Now(),
+		})
+		// a duplicate key means another request created the tag in the meantime
+		if err != nil && !mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *TagSrv) GetTags() (result models.ResponseModel) {
+	tags, err := s.tagRepo.GetTags()
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	counts, err := s.quoteRepo.CountByTag()
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	res := []models.TagCountModel{}
+	for _, tag := range tags {
+		res = append(res, models.TagCountModel{
+			ID:    tag.ID,
+			Name:  tag.Name,
+			Count: counts[tag.Name],
+		})
+	}
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "get tags success",
+		Result:  res,
+	}
+}
+
+func (s *TagSrv) CreateTag(name string) (result models.ResponseModel) {
+	name = normalizeTag(name)
+	if err := validateTag(name); err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if _, err := s.tagRepo.GetTagByName(name); err == nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    409,
+			Message: "tag already exist",
+			Result:  nil,
+		}
+	}
+	res, err := s.tagRepo.CreateTag(models.CreateTagModel{
+		ID:         uuid.New().String(),
+		Name:       name,
+		CreateDate: time.Now(),
+		UpdateDate: time.Now(),
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    409,
+			Message: "tag already exist",
+			Result:  nil,
+		}
+	}
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	return models.ResponseModel{
+		Status:  true,
+		Code:    201,
+		Message: "create tag success",
+		Result:  res,
+	}
+}
+
+func (s *TagSrv) RenameTag(id string, name string) (result models.ResponseModel) {
+	name = normalizeTag(name)
+	if err := validateTag(name); err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	tag, err := s.tagRepo.GetTag(id)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    404,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if tag.Name == name {
+		return models.ResponseModel{
+			Status:  true,
+			Code:    200,
+			Message: "rename tag success",
+			Result:  tag,
+		}
+	}
+	if _, err := s.tagRepo.GetTagByName(name); err == nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    409,
+			Message: "tag already exist, merge the tags instead",
+			Result:  nil,
+		}
+	}
+	// quotes first, so a failure leaves the old tag in place and the rename can be retried
+	if err := s.quoteRepo.ReplaceTag(tag.Name, name); err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	res, err := s.tagRepo.RenameTag(id, name)
+	if mongo.IsDuplicateKeyError(err) {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    409,
+			Message: "tag already exist, merge the tags instead",
+			Result:  nil,
+		}
+	}
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "rename tag success",
+		Result:  res,
+	}
+}
+
+func (s *TagSrv) MergeTag(id string, targetID string) (result models.ResponseModel) {
+	if id == "" || targetID == "" {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "id or target id not found",
+			Result:  nil,
+		}
+	}
+	if id == targetID {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "cannot merge a tag into itself",
+			Result:  nil,
+		}
+	}
+	source, err := s.tagRepo.GetTag(id)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    404,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	target, err := s.tagRepo.GetTag(targetID)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    404,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if err := s.quoteRepo.ReplaceTag(source.Name, target.Name); err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if err := s.tagRepo.DeleteTag(source.ID); err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "merge tag success",
+		Result:  target,
+	}
+}
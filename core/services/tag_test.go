@@ -0,0 +1,125 @@
+package services_test
+
+import (
+	"backend/core/models"
+	"backend/core/repositories"
+	"backend/core/services"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func Test_GetTags(t *testing.T) {
+	tagRepo := repositories.NewTagRepositoryMock()
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	tagRepo.On("GetTags").Return([]models.TagModel{{ID: "1", Name: "life"}, {ID: "2", Name: "ความรัก"}}, nil)
+	quoteRepo.On("CountByTag").Return(map[string]int64{"life": 3}, nil)
+
+	tagService := services.NewTagService(tagRepo, quoteRepo)
+	result := tagService.GetTags()
+
+	assert.Equal(t, models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "get tags success",
+		Result: []models.TagCountModel{
+			{ID: "1", Name: "life", Count: 3},
+			{ID: "2", Name: "ความรัก", Count: 0},
+		},
+	}, result)
+}
+
+func Test_RenameTag(t *testing.T) {
+	type test struct {
+		Name  string
+		Input string
+		Mock  struct {
+			GetTagByName error
+		}
+		Output struct {
+			Code    int
+			Message string
+		}
+		Replaced bool
+	}
+	cases := []test{
+		{
+			Name:  "rename tag success",
+			Input: "  Self   Love ",
+			Mock: struct {
+				GetTagByName error
+			}{GetTagByName: mongo.ErrNoDocuments},
+			Output: struct {
+				Code    int
+				Message string
+			}{Code: 200, Message: "rename tag success"},
+			Replaced: true,
+		},
+		{
+			Name:  "tag already exist",
+			Input: "self love",
+			Mock: struct {
+				GetTagByName error
+			}{GetTagByName: nil},
+			Output: struct {
+				Code    int
+				Message string
+			}{Code: 409, Message: "tag already exist, merge the tags instead"},
+		},
+		{
+			Name:  "tag name not found",
+			Input: "   ",
+			Output: struct {
+				Code    int
+				Message string
+			}{Code: 400, Message: "tag name not found"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			tagRepo := repositories.NewTagRepositoryMock()
+			quoteRepo := repositories.NewQuoteRepositoryMock()
+			tagRepo.On("GetTag", "1").Return(models.TagModel{ID: "1", Name: "love"}, nil)
+			tagRepo.On("GetTagByName", "self love").Return(models.TagModel{ID: "2", Name: "self love"}, c.Mock.GetTagByName)
+			tagRepo.On("RenameTag", "1", "self love").Return(models.TagModel{ID: "1", Name: "self love"}, nil)
+			quoteRepo.On("ReplaceTag", "love", "self love").Return(nil)
+
+			tagService := services.NewTagService(tagRepo, quoteRepo)
+			result := tagService.RenameTag("1", c.Input)
+
+			assert.Equal(t, c.Output.Code, result.Code)
+			assert.Equal(t, c.Output.Message, result.Message)
+			if c.Replaced {
+				quoteRepo.AssertCalled(t, "ReplaceTag", "love", "self love")
+			} else {
+				quoteRepo.AssertNotCalled(t, "ReplaceTag", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func Test_MergeTag(t *testing.T) {
+	tagRepo := repositories.NewTagRepositoryMock()
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	tagRepo.On("GetTag", "1").Return(models.TagModel{ID: "1", Name: "luv"}, nil)
+	tagRepo.On("GetTag", "2").Return(models.TagModel{ID: "2", Name: "love"}, nil)
+	tagRepo.On("DeleteTag", "1").Return(nil)
+	quoteRepo.On("ReplaceTag", "luv", "love").Return(nil)
+
+	tagService := services.NewTagService(tagRepo, quoteRepo)
+	result := tagService.MergeTag("1", "2")
+
+	assert.Equal(t, models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "merge tag success",
+		Result:  models.TagModel{ID: "2", Name: "love"},
+	}, result)
+	tagRepo.AssertExpectations(t)
+	quoteRepo.AssertExpectations(t)
+
+	result = tagService.MergeTag("1", "1")
+	assert.Equal(t, "cannot merge a tag into itself", result.Message)
+}
@@ -0,0 +1,123 @@
+package services_test
+
+import (
+	"backend/core/models"
+	"backend/core/repositories"
+	"backend/core/services"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTranslationQuoteService(quoteRepo repositories.QuoteRepository, userRepo repositories.UserRepository) services.QuoteService {
+	return services.NewQuoteService(quoteRepos(services.QuoteRepositories{Quote: quoteRepo, User: userRepo}))
+}
+
+func Test_AddTranslation(t *testing.T) {
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	quoteRepo.On("GetQuote", "th").Return(models.QuoteModel{ID: "th", Language: "th", Vote: 3, CreatedBy: "user"}, nil)
+	quoteRepo.On("GetQuote", "en").Return(models.QuoteModel{ID: "en", Language: "en", Vote: 2, CreatedBy: "user"}, nil)
+	quoteRepo.On("GetQuote", "th2").Return(models.QuoteModel{ID: "th2", Language: "th", CreatedBy: "user"}, nil)
+	quoteRepo.On("GetQuote", "grouped").Return(models.QuoteModel{ID: "grouped", Language: "th", Vote: 5, GroupID: "th", CreatedBy: "user"}, nil)
+	quoteRepo.On("GetGroup", "th").Return([]models.QuoteModel{
+		{ID: "th", Language: "th", GroupID: "th"},
+		{ID: "grouped", Language: "en", GroupID: "th"},
+	}, nil)
+	quoteRepo.On("SetGroup", []string{"th", "en"}, "th", 5).Return(nil)
+
+	quoteService := newTranslationQuoteService(quoteRepo, repositories.NewUserRepositoryMock())
+	result := quoteService.AddTranslation("user", models.RoleUser, "th", models.HandAddTranslationBodyModel{QuoteID: "en"})
+	assert.Equal(t, "add translation success", result.Message)
+	res := result.Result.(models.QuoteTranslationsModel)
+	assert.Equal(t, "th", res.GroupID)
+	// the group counts the votes of both
+	assert.Equal(t, 5, res.Vote)
+	assert.Len(t, res.Quotes, 2)
+
+	result = quoteService.AddTranslation("other", models.RoleUser, "th", models.HandAddTranslationBodyModel{QuoteID: "en"})
+	assert.Equal(t, "forbidden", result.Message)
+
+	result = quoteService.AddTranslation("user", models.RoleUser, "th", models.HandAddTranslationBodyModel{QuoteID: "th"})
+	assert.Equal(t, "quote can not translate itself", result.Message)
+
+	result = quoteService.AddTranslation("user", models.RoleUser, "en", models.HandAddTranslationBodyModel{QuoteID: "grouped"})
+	assert.Equal(t, "quote already in a translation group", result.Message)
+
+	result = quoteService.AddTranslation("mod", models.RoleModerator, "grouped", models.HandAddTranslationBodyModel{QuoteID: "th2"})
+	assert.Equal(t, "group already has a th translation", result.Message)
+}
+
+func Test_RemoveTranslation(t *testing.T) {
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	userRepo := repositories.NewUserRepositoryMock()
+	quoteRepo.On("GetQuote", "root").Return(models.QuoteModel{ID: "root", GroupID: "root", Vote: 10, CreatedBy: "user"}, nil)
+	quoteRepo.On("GetQuote", "alone").Return(models.QuoteModel{ID: "alone", CreatedBy: "user"}, nil)
+	quoteRepo.On("GetGroup", "root").Return([]models.QuoteModel{{ID: "root"}, {ID: "a"}, {ID: "b"}}, nil)
+	userRepo.On("CountVotes", "root").Return(int64(4), nil)
+	quoteRepo.On("SetGroup", []string{"root"}, "", 4).Return(nil)
+	quoteRepo.On("SetGroup", []string{"a", "b"}, "a", 6).Return(nil)
+
+	quoteService := newTranslationQuoteService(quoteRepo, userRepo)
+	result := quoteService.RemoveTranslation("user", models.RoleUser, "root")
+	assert.Equal(t, "remove translation success", result.Message)
+	assert.Equal(t, 4, result.Result.(models.QuoteModel).Vote)
+	// the oldest remaining quote becomes the root
+	quoteRepo.AssertCalled(t, "SetGroup", []string{"a", "b"}, "a", 6)
+
+	result = quoteService.RemoveTranslation("user", models.RoleUser, "alone")
+	assert.Equal(t, "translation not found", result.Message)
+
+	result = quoteService.RemoveTranslation("other", models.RoleUser, "root")
+	assert.Equal(t, "forbidden", result.Message)
+}
+
+func Test_GetQuotesAcceptLanguage(t *testing.T) {
+	cases := []struct {
+		Name           string
+		AcceptLanguage string
+		ID             string
+	}{
+		{Name: "exact tag", AcceptLanguage: "en-US,en;q=0.9,th;q=0.5", ID: "en-us"},
+		{Name: "primary language", AcceptLanguage: "en-GB, th;q=0.5", ID: "en-us"},
+		{Name: "weights", AcceptLanguage: "ja;q=0.1, th;q=0.8", ID: "th"},
+		{Name: "no match keeps the root", AcceptLanguage: "fr", ID: "th"},
+		{Name: "no header keeps the root", AcceptLanguage: "", ID: "th"},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			quoteRepo := repositories.NewQuoteRepositoryMock()
+			quoteRepo.On("GetQuotes", mock.MatchedBy(func(filter models.QuoteFilterModel) bool {
+				return !filter.Translations
+			})).Return([]models.QuoteModel{
+				{ID: "th", Language: "th", GroupID: "th", Vote: 7},
+				{ID: "plain", Language: "en"},
+			}, nil)
+			quoteRepo.On("GetGroups", []string{"th"}).Return([]models.QuoteModel{
+				{ID: "th", Language: "th", GroupID: "th", Vote: 7},
+				{ID: "en-us", Language: "en-us", GroupID: "th", Vote: 7},
+				{ID: "ja", Language: "ja", GroupID: "th", Vote: 7},
+			}, nil)
+
+			quoteService := newTranslationQuoteService(quoteRepo, repositories.NewUserRepositoryMock())
+			result := quoteService.GetQuotes("", models.HandGetQuotesQueryModel{AcceptLanguage: c.AcceptLanguage})
+			assert.Equal(t, "get quotes success", result.Message)
+			quotes := result.Result.(models.QuoteListModel).Quotes
+			assert.Equal(t, c.ID, quotes[0].ID)
+			assert.Equal(t, 7, quotes[0].Vote)
+			assert.Len(t, quotes[0].Translations, 3)
+			assert.Equal(t, "plain", quotes[1].ID)
+			assert.Nil(t, quotes[1].Translations)
+		})
+	}
+
+	// a language filter lists the variants themselves
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	quoteRepo.On("GetQuotes", mock.MatchedBy(func(filter models.QuoteFilterModel) bool {
+		return filter.Translations && filter.Language == "en-us"
+	})).Return([]models.QuoteModel{{ID: "en-us", Language: "en-us", GroupID: "th"}}, nil)
+	quoteService := newTranslationQuoteService(quoteRepo, repositories.NewUserRepositoryMock())
+	result := quoteService.GetQuotes("", models.HandGetQuotesQueryModel{Language: "en-US", AcceptLanguage: "th"})
+	assert.Equal(t, "en-us", result.Result.(models.QuoteListModel).Quotes[0].ID)
+	quoteRepo.AssertNotCalled(t, "GetGroups", mock.Anything)
+}
@@ -1,30 +1,123 @@
 package services
 
 import (
+	"backend/config"
 	"backend/core/models"
 	"backend/core/repositories"
 	"backend/utils"
+	"errors"
+	"fmt"
+	"log"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
+const (
+	maxDisplayNameLength = 50
+	maxBioLength         = 300
+	maxAvatarSize        = 2 << 20
+)
+
+var avatarExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
 type UserService interface {
 	SignIn(email string, password string) (result models.ResponseModel)
 
 	CreateUser(email string, password string) (result models.ResponseModel)
 
 	UpdateVote(id string, qouteID string) (result models.ResponseModel)
+
+	GetMe(id string) (result models.ResponseModel)
+
+	UpdateProfile(id string, profile models.UpdateProfileModel) (result models.ResponseModel)
+
+	ChangePassword(id string, currentPassword string, newPassword string) (result models.ResponseModel)
+
+	ExportData(id string) (result models.ResponseModel)
+
+	DeleteAccount(id string) (result models.ResponseModel)
+
+	CancelDeleteAccount(id string) (result models.ResponseModel)
+
+	PurgeDeletedAccounts() (result models.ResponseModel)
 }
 
 type UserSrv struct {
-	userRepo repositories.UserRepository
+	repos AccountRepositories
 }
 
-func NewUserService(userRepo repositories.UserRepository) UserService {
+// AccountRepositories are the stores that keep the data of a user account,
+// the user and admin services share them. A feature that keeps personal data
+// adds its repository here, to ExportData and to purgeUser.
+type AccountRepositories struct {
+	User         repositories.UserRepository
+	Quote        repositories.QuoteRepository
+	Blob         repositories.BlobRepository
+	Audit        repositories.AuditRepository
+	Notification repositories.NotificationRepository
+	Favorite     repositories.FavoriteRepository
+	Comment      repositories.CommentRepository
+	Reaction     repositories.ReactionRepository
+	Seen         repositories.SeenRepository
+}
+
+func NewUserService(repos AccountRepositories) UserService {
 	return &UserSrv{
-		userRepo: userRepo,
+		repos: repos,
+	}
+}
+
+// audit records an account event, a failed write is logged but never fails the request.
+func (s *UserSrv) audit(userID string, action string, detail string) {
+	writeAudit(s.repos.Audit, userID, action, detail)
+}
+
+func writeAudit(auditRepo repositories.AuditRepository, userID string, action string, detail string) {
+	err := auditRepo.CreateAudit(models.CreateAuditModel{
+		ID:         uuid.New().String(),
+		UserID:     userID,
+		Action:     action,
+		Detail:     detail,
+		CreateDate: time.Now(),
+	})
+	if err != nil {
+		log.Println(utils.LoggingFormat(userID, "audit "+action+" failed: "+err.Error()))
+	}
+}
+
+func toUserRes(user models.UserModel) models.UserResModel {
+	return models.UserResModel{
+		ID:                 user.ID,
+		Email:              user.Email,
+		QouteID:            user.QouteID,
+		DisplayName:        user.DisplayName,
+		Bio:                user.Bio,
+		AvatarURL:          user.AvatarURL,
+		Role:               userRole(user),
+		Suspended:          user.Suspended,
+		SuspendReason:      user.SuspendReason,
+		MustChangePassword: user.MustChangePassword,
+		Trusted:            user.Trusted,
+		DeleteAt:           user.DeleteAt,
+		CreateDate:         user.CreateDate,
+		UpdateDate:         user.UpdateDate,
+	}
+}
+
+// userRole treats accounts created before roles existed as plain users.
+func userRole(user models.UserModel) string {
+	if user.Role == "" {
+		return models.RoleUser
 	}
+	return user.Role
 }
 
 func (s *UserSrv) SignIn(email string, password string) (result models.ResponseModel) {
@@ -46,7 +139,7 @@ func (s *UserSrv) SignIn(email string, password string) (result models.ResponseM
 		}
 	}
 
-	user, err := s.userRepo.GetUser(email)
+	user, err := s.repos.User.GetUser(email)
 	if err != nil {
 		return models.ResponseModel{
 			Status:  false,
@@ -63,7 +156,7 @@ func (s *UserSrv) SignIn(email string, password string) (result models.ResponseM
 			Result:  nil,
 		}
 	}
-	token, err := utils.GenerateToken(user.Email)
+	token, err := utils.GenerateToken(user.ID, user.Email)
 	if err != nil {
 		return models.ResponseModel{
 			Status:  false,
@@ -106,7 +199,7 @@ func (s *UserSrv) CreateUser(email string, password string) (result models.Respo
 		}
 	}
 
-	_, err := s.userRepo.GetUser(email)
+	_, err := s.repos.User.GetUser(email)
 	if err == nil {
 		return models.ResponseModel{
 			Status:  false,
@@ -120,10 +213,11 @@ func (s *UserSrv) CreateUser(email string, password string) (result models.Respo
 		Email:      email,
 		QouteID:    "",
 		Password:   utils.GeneratePassword(password),
+		Role:       models.RoleUser,
 		CreateDate: time.Now(),
 		UpdateDate: time.Now(),
 	}
-	err = s.userRepo.CreateUser(payload)
+	err = s.repos.User.CreateUser(payload)
 	if err != nil {
 		return models.ResponseModel{
 			Status:  false,
@@ -149,11 +243,28 @@ func (s *UserSrv) UpdateVote(id string, qouteID string) (result models.ResponseM
 			Result:  nil,
 		}
 	}
+	quote, err := s.repos.Quote.GetQuote(qouteID)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    404,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if !quoteApproved(quote) {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "quote not approved",
+			Result:  nil,
+		}
+	}
 	payload := models.UpdateUserModel{
 		QuoteID:    qouteID,
 		UpdateDate: time.Now(),
 	}
-	res, err := s.userRepo.UpdateUser(id, payload)
+	res, err := s.repos.User.UpdateUser(id, payload)
 	if err != nil {
 		return models.ResponseModel{
 			Status:  false,
@@ -166,6 +277,521 @@ func (s *UserSrv) UpdateVote(id string, qouteID string) (result models.ResponseM
 		Status:  true,
 		Code:    200,
 		Message: "update vote success",
-		Result:  res,
+		Result:  toUserRes(res),
+	}
+}
+
+func (s *UserSrv) GetMe(id string) (result models.ResponseModel) {
+	if id == "" {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    401,
+			Message: "unauthorized",
+			Result:  nil,
+		}
+	}
+	res, err := s.repos.User.GetUserByID(id)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "get user success",
+		Result:  toUserRes(res),
+	}
+}
+
+func (s *UserSrv) UpdateProfile(id string, profile models.UpdateProfileModel) (result models.ResponseModel) {
+	if id == "" {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    401,
+			Message: "unauthorized",
+			Result:  nil,
+		}
+	}
+	if profile.DisplayName == nil && profile.Bio == nil && profile.Avatar == nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "nothing to update",
+			Result:  nil,
+		}
+	}
+	if profile.DisplayName != nil && utf8.RuneCountInString(*profile.DisplayName) > maxDisplayNameLength {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: fmt.Sprintf("display name must be <= %d characters", maxDisplayNameLength),
+			Result:  nil,
+		}
+	}
+	if profile.Bio != nil && utf8.RuneCountInString(*profile.Bio) > maxBioLength {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: fmt.Sprintf("bio must be <= %d characters", maxBioLength),
+			Result:  nil,
+		}
+	}
+	user, err := s.repos.User.GetUserByID(id)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	payload := models.UpdateUserModel{
+		DisplayName: profile.DisplayName,
+		Bio:         profile.Bio,
+		UpdateDate:  time.Now(),
+	}
+	if profile.Avatar != nil {
+		ext, ok := avatarExtensions[profile.Avatar.ContentType]
+		if !ok {
+			return models.ResponseModel{
+				Status:  false,
+				Code:    400,
+				Message: "avatar must be png, jpeg, gif or webp",
+				Result:  nil,
+			}
+		}
+		if profile.Avatar.Size > maxAvatarSize {
+			return models.ResponseModel{
+				Status:  false,
+				Code:    400,
+				Message: "avatar must be <= 2MB",
+				Result:  nil,
+			}
+		}
+		key := fmt.Sprintf("avatars/%s/%s%s", id, uuid.New().String(), ext)
+		url, err := s.repos.Blob.Put(key, profile.Avatar.Content)
+		if err != nil {
+			return models.ResponseModel{
+				Status:  false,
+				Code:    500,
+				Message: err.Error(),
+				Result:  nil,
+			}
+		}
+		payload.AvatarURL = &url
+		payload.AvatarKey = &key
+	}
+	res, err := s.repos.User.UpdateUser(id, payload)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if payload.AvatarKey != nil && user.AvatarKey != "" {
+		// the old avatar is unreachable now, failing to remove it is not fatal
+		s.repos.Blob.Delete(user.AvatarKey)
+	}
+	s.audit(id, "update_profile", "")
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "update profile success",
+		Result:  toUserRes(res),
+	}
+}
+
+func (s *UserSrv) ChangePassword(id string, currentPassword string, newPassword string) (result models.ResponseModel) {
+	if id == "" {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    401,
+			Message: "unauthorized",
+			Result:  nil,
+		}
+	}
+	if currentPassword == "" || newPassword == "" {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "current password or new password not found",
+			Result:  nil,
+		}
+	}
+	user, err := s.repos.User.GetUserByID(id)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if !utils.ComparePassword(user.Password, currentPassword) {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "password invalid",
+			Result:  nil,
+		}
+	}
+	if currentPassword == newPassword {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "new password must be different from current password",
+			Result:  nil,
+		}
+	}
+	mustChangePassword := false
+	payload := models.UpdateUserModel{
+		Password:           utils.GeneratePassword(newPassword),
+		MustChangePassword: &mustChangePassword,
+		UpdateDate:         time.Now(),
+	}
+	_, err = s.repos.User.UpdateUser(id, payload)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	s.audit(id, "change_password", "")
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "change password success",
+		Result:  nil,
+	}
+}
+
+func (s *UserSrv) ExportData(id string) (result models.ResponseModel) {
+	if id == "" {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    401,
+			Message: "unauthorized",
+			Result:  nil,
+		}
+	}
+	user, err := s.repos.User.GetUserByID(id)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	quotes, err := s.repos.Quote.GetQuotesByCreator(id)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	votes := []models.QuoteModel{}
+	if user.QouteID != "" {
+		quote, err := s.repos.Quote.GetQuote(user.QouteID)
+		if err == nil {
+			votes = append(votes, quote)
+		}
+	}
+	s.audit(id, "export_data", "")
+	audits, err := s.repos.Audit.GetAuditsByUser(id)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if quotes == nil {
+		quotes = []models.QuoteModel{}
+	}
+	if audits == nil {
+		audits = []models.AuditModel{}
+	}
+	notifications, err := s.repos.Notification.GetAllNotifications(id)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if notifications == nil {
+		notifications = []models.NotificationModel{}
+	}
+	favorites, err := s.repos.Favorite.GetAllFavorites(id)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if favorites == nil {
+		favorites = []models.FavoriteModel{}
+	}
+	comments, err := s.repos.Comment.GetCommentsByUser(id)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if comments == nil {
+		comments = []models.CommentModel{}
+	}
+	reactions, err := s.repos.Reaction.GetReactionsByUser(id)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if reactions == nil {
+		reactions = []models.ReactionModel{}
+	}
+	seen, err := s.repos.Seen.GetSeenByUser(id)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if seen == nil {
+		seen = []models.SeenQuoteModel{}
+	}
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "export data success",
+		Result: models.UserExportModel{
+			Profile:       toUserRes(user),
+			Quotes:        quotes,
+			Votes:         votes,
+			Audits:        audits,
+			Notifications: notifications,
+			Favorites:     favorites,
+			Comments:      comments,
+			Reactions:     reactions,
+			SeenQuotes:    seen,
+			ExportDate:    time.Now(),
+		},
+	}
+}
+
+func (s *UserSrv) DeleteAccount(id string) (result models.ResponseModel) {
+	if id == "" {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    401,
+			Message: "unauthorized",
+			Result:  nil,
+		}
+	}
+	user, err := s.repos.User.GetUserByID(id)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if config.Env.DeleteGraceDays <= 0 {
+		if err := s.purgeUser(user); err != nil {
+			return models.ResponseModel{
+				Status:  false,
+				Code:    400,
+				Message: err.Error(),
+				Result:  nil,
+			}
+		}
+		return models.ResponseModel{
+			Status:  true,
+			Code:    200,
+			Message: "delete account success",
+			Result:  nil,
+		}
+	}
+	deleteAt := time.Now().AddDate(0, 0, config.Env.DeleteGraceDays)
+	res, err := s.repos.User.ScheduleDeleteUser(id, &deleteAt)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	s.audit(id, "request_delete", deleteAt.Format(time.RFC3339))
+	return models.ResponseModel{
+		Status:  true,
+		Code:    202,
+		Message: "delete account scheduled",
+		Result:  toUserRes(res),
+	}
+}
+
+func (s *UserSrv) CancelDeleteAccount(id string) (result models.ResponseModel) {
+	if id == "" {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    401,
+			Message: "unauthorized",
+			Result:  nil,
+		}
+	}
+	user, err := s.repos.User.GetUserByID(id)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	if user.DeleteAt == nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: "account is not scheduled for deletion",
+			Result:  nil,
+		}
+	}
+	res, err := s.repos.User.ScheduleDeleteUser(id, nil)
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    400,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	s.audit(id, "cancel_delete", "")
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "cancel delete account success",
+		Result:  toUserRes(res),
+	}
+}
+
+func (s *UserSrv) PurgeDeletedAccounts() (result models.ResponseModel) {
+	users, err := s.repos.User.GetUsersDueForDeletion(time.Now())
+	if err != nil {
+		return models.ResponseModel{
+			Status:  false,
+			Code:    500,
+			Message: err.Error(),
+			Result:  nil,
+		}
+	}
+	purged := 0
+	for _, user := range users {
+		if err := s.purgeUser(user); err != nil {
+			log.Println(utils.LoggingFormat(user.ID, "purge account failed: "+err.Error()))
+			continue
+		}
+		purged++
+	}
+	return models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "purge accounts success",
+		Result:  purged,
+	}
+}
+
+// purgeUser removes the account and its personal data. Quotes the user created are
+// kept without attribution and their vote is taken off the quote tally.
+func (s *UserSrv) purgeUser(user models.UserModel) error {
+	return purgeUser(s.repos, user)
+}
+
+func purgeUser(repos AccountRepositories, user models.UserModel) error {
+	if user.QouteID != "" {
+		// the vote may point at a quote that is gone, there is nothing to take back then
+		if err := repos.Quote.IncrementVote(user.QouteID, -1); err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+			return err
+		}
+		// cleared at once so a retried purge does not take the vote back twice
+		if err := repos.User.ClearVote(user.ID); err != nil {
+			return err
+		}
+	}
+	if err := repos.Quote.ClearCreator(user.ID); err != nil {
+		return err
+	}
+	if err := repos.Audit.DeleteAuditsByUser(user.ID); err != nil {
+		return err
+	}
+	if err := repos.Notification.DeleteNotificationsByUser(user.ID); err != nil {
+		return err
+	}
+	favorites, err := repos.Favorite.GetAllFavorites(user.ID)
+	if err != nil {
+		return err
+	}
+	// each record goes with its count, a retry only sees what is left
+	for _, favorite := range favorites {
+		removed, err := repos.Favorite.RemoveFavorite(user.ID, favorite.QuoteID)
+		if err != nil {
+			return err
+		}
+		if !removed {
+			continue
+		}
+		if err := repos.Quote.IncrementFavoriteCount(favorite.QuoteID, -1); err != nil {
+			return err
+		}
+	}
+	if err := repos.Comment.ClearCommentAuthor(user.ID); err != nil {
+		return err
+	}
+	reactions, err := repos.Reaction.GetReactionsByUser(user.ID)
+	if err != nil {
+		return err
+	}
+	for _, reaction := range reactions {
+		removed, err := repos.Reaction.RemoveReaction(reaction.QuoteID, user.ID, reaction.Emoji)
+		if err != nil {
+			return err
+		}
+		if !removed {
+			continue
+		}
+		if err := repos.Quote.IncrementReaction(reaction.QuoteID, reaction.Emoji, -1); err != nil {
+			return err
+		}
+	}
+	if err := repos.Seen.DeleteSeenByUser(user.ID); err != nil {
+		return err
+	}
+	if user.AvatarKey != "" {
+		repos.Blob.Delete(user.AvatarKey)
 	}
+	return repos.User.DeleteUser(user.ID)
 }
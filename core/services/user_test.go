@@ -5,6 +5,7 @@ import (
 	"backend/core/repositories"
 	"backend/core/services"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -14,6 +15,78 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
+// accountRepos fills the repositories a test leaves out with mocks that
+// accept the calls every account flow makes.
+func accountRepos(repos services.AccountRepositories) services.AccountRepositories {
+	if repos.User == nil {
+		repos.User = repositories.NewUserRepositoryMock()
+	}
+	if repos.Quote == nil {
+		repos.Quote = repositories.NewQuoteRepositoryMock()
+	}
+	if repos.Blob == nil {
+		repos.Blob = repositories.NewBlobRepositoryMock()
+	}
+	if repos.Audit == nil {
+		repos.Audit = newAuditRepoMock()
+	}
+	if repos.Notification == nil {
+		repos.Notification = newNotificationRepoMock()
+	}
+	if repos.Favorite == nil {
+		repos.Favorite = newFavoriteRepoMock()
+	}
+	if repos.Comment == nil {
+		repos.Comment = newCommentRepoMock()
+	}
+	if repos.Reaction == nil {
+		repos.Reaction = newReactionRepoMock()
+	}
+	if repos.Seen == nil {
+		repos.Seen = newSeenRepoMock()
+	}
+	return repos
+}
+
+func newAuditRepoMock() repositories.AuditRepository {
+	auditRepo := repositories.NewAuditRepositoryMock()
+	auditRepo.On("CreateAudit", mock.Anything).Return(nil)
+	return auditRepo
+}
+
+func newSeenRepoMock() repositories.SeenRepository {
+	seenRepo := repositories.NewSeenRepositoryMock()
+	seenRepo.On("GetSeenByUser", mock.Anything).Return([]models.SeenQuoteModel{}, nil)
+	seenRepo.On("DeleteSeenByUser", mock.Anything).Return(nil)
+	return seenRepo
+}
+
+func newReactionRepoMock() repositories.ReactionRepository {
+	reactionRepo := repositories.NewReactionRepositoryMock()
+	reactionRepo.On("GetReactionsByUser", mock.Anything).Return([]models.ReactionModel{}, nil)
+	return reactionRepo
+}
+
+func newCommentRepoMock() repositories.CommentRepository {
+	commentRepo := repositories.NewCommentRepositoryMock()
+	commentRepo.On("GetCommentsByUser", mock.Anything).Return([]models.CommentModel{}, nil)
+	commentRepo.On("ClearCommentAuthor", mock.Anything).Return(nil)
+	return commentRepo
+}
+
+func newFavoriteRepoMock() repositories.FavoriteRepository {
+	favoriteRepo := repositories.NewFavoriteRepositoryMock()
+	favoriteRepo.On("GetAllFavorites", mock.Anything).Return([]models.FavoriteModel{}, nil)
+	return favoriteRepo
+}
+
+func newNotificationRepoMock() repositories.NotificationRepository {
+	notificationRepo := repositories.NewNotificationRepositoryMock()
+	notificationRepo.On("GetAllNotifications", mock.Anything).Return([]models.NotificationModel{}, nil)
+	notificationRepo.On("DeleteNotificationsByUser", mock.Anything).Return(nil)
+	return notificationRepo
+}
+
 func Test_SignIn(t *testing.T) {
 	type test struct {
 		Name  string
@@ -219,7 +292,7 @@ func Test_SignIn(t *testing.T) {
 		t.Run(c.Name, func(t *testing.T) {
 			userRepo := repositories.NewUserRepositoryMock()
 			userRepo.On("GetUser", c.Mock.GetUser.Input).Return(c.Mock.GetUser.Output, c.Mock.GetUser.Error)
-			userService := services.NewUserService(userRepo)
+			userService := services.NewUserService(accountRepos(services.AccountRepositories{User: userRepo}))
 			result := userService.SignIn(c.Input.Email, c.Input.Password)
 
 			assert.Equal(t, result.Message, c.Output.Message)
@@ -503,7 +576,7 @@ func Test_CreateUser(t *testing.T) {
 			userRepo := repositories.NewUserRepositoryMock()
 			userRepo.On("GetUser", c.Mock.GetUser.Input).Return(c.Mock.GetUser.Output, c.Mock.GetUser.Error)
 			userRepo.On("CreateUser", mock.Anything).Return(c.Mock.CreateUser.Error)
-			userService := services.NewUserService(userRepo)
+			userService := services.NewUserService(accountRepos(services.AccountRepositories{User: userRepo}))
 			result := userService.CreateUser(c.Input.Email, c.Input.Password)
 
 			assert.Equal(t, result, c.Output)
@@ -582,7 +655,8 @@ func Test_UpdateVote(t *testing.T) {
 				Status:  true,
 				Code:    200,
 				Message: "update vote success",
-				Result: models.UserModel{
+				Result: models.UserResModel{
+					Role:       models.RoleUser,
 					ID:         id,
 					QouteID:    qouteID,
 					UpdateDate: updateDate,
@@ -712,9 +786,631 @@ func Test_UpdateVote(t *testing.T) {
 		t.Run(c.Name, func(t *testing.T) {
 			userRepo := repositories.NewUserRepositoryMock()
 			userRepo.On("UpdateUser", mock.Anything, mock.Anything).Return(c.Mock.UpdateUser.Output, c.Mock.UpdateUser.Error)
-			userService := services.NewUserService(userRepo)
+			quoteRepo := repositories.NewQuoteRepositoryMock()
+			quoteRepo.On("GetQuote", mock.Anything).Return(models.QuoteModel{ID: c.Input.QouteID, Status: models.QuoteStatusApproved}, nil)
+			userService := services.NewUserService(accountRepos(services.AccountRepositories{User: userRepo, Quote: quoteRepo}))
 			result := userService.UpdateVote(c.Input.ID, c.Input.QouteID)
 			assert.Equal(t, result, c.Output)
 		})
 	}
 }
+
+func Test_UpdateVotePending(t *testing.T) {
+	userRepo := repositories.NewUserRepositoryMock()
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	quoteRepo.On("GetQuote", "pending").Return(models.QuoteModel{ID: "pending", Status: models.QuoteStatusPending}, nil)
+	quoteRepo.On("GetQuote", "deleted").Return(models.QuoteModel{}, errors.New("mongo: no documents in result"))
+
+	userService := services.NewUserService(accountRepos(services.AccountRepositories{User: userRepo, Quote: quoteRepo}))
+	result := userService.UpdateVote("user", "pending")
+	assert.Equal(t, "quote not approved", result.Message)
+
+	result = userService.UpdateVote("user", "deleted")
+	assert.Equal(t, 404, result.Code)
+	userRepo.AssertNotCalled(t, "UpdateUser", mock.Anything, mock.Anything)
+}
+
+func Test_GetMe(t *testing.T) {
+	type test struct {
+		Name  string
+		Input string
+		Mock  struct {
+			GetUserByID struct {
+				Output models.UserModel
+				Error  error
+			}
+		}
+		Output models.ResponseModel
+	}
+	id := uuid.New().String()
+	date := time.Now()
+	cases := []test{
+		{
+			Name:  "get user success",
+			Input: id,
+			Mock: struct {
+				GetUserByID struct {
+					Output models.UserModel
+					Error  error
+				}
+			}{
+				GetUserByID: struct {
+					Output models.UserModel
+					Error  error
+				}{
+					Output: models.UserModel{
+						ID:          id,
+						Email:       "test@gmail.com",
+						Password:    "$2a$10$TODe5QSVwJdjrhPnpKPZb.uRL7dMA3YnOx6VCXcZs5HiPoYHs7c.6",
+						DisplayName: "test",
+						CreateDate:  date,
+						UpdateDate:  date,
+					},
+					Error: nil,
+				},
+			},
+			Output: models.ResponseModel{
+				Status:  true,
+				Code:    200,
+				Message: "get user success",
+				Result: models.UserResModel{
+					Role:        models.RoleUser,
+					ID:          id,
+					Email:       "test@gmail.com",
+					DisplayName: "test",
+					CreateDate:  date,
+					UpdateDate:  date,
+				},
+			},
+		},
+		{
+			Name:  "unauthorized",
+			Input: "",
+			Output: models.ResponseModel{
+				Status:  false,
+				Code:    401,
+				Message: "unauthorized",
+				Result:  nil,
+			},
+		},
+		{
+			Name:  "user not found",
+			Input: id,
+			Mock: struct {
+				GetUserByID struct {
+					Output models.UserModel
+					Error  error
+				}
+			}{
+				GetUserByID: struct {
+					Output models.UserModel
+					Error  error
+				}{
+					Output: models.UserModel{},
+					Error:  mongo.ErrNoDocuments,
+				},
+			},
+			Output: models.ResponseModel{
+				Status:  false,
+				Code:    400,
+				Message: mongo.ErrNoDocuments.Error(),
+				Result:  nil,
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			userRepo := repositories.NewUserRepositoryMock()
+			userRepo.On("GetUserByID", c.Input).Return(c.Mock.GetUserByID.Output, c.Mock.GetUserByID.Error)
+			userService := services.NewUserService(accountRepos(services.AccountRepositories{User: userRepo}))
+			result := userService.GetMe(c.Input)
+			assert.Equal(t, c.Output, result)
+		})
+	}
+}
+
+func Test_UpdateProfile(t *testing.T) {
+	type test struct {
+		Name  string
+		Input models.UpdateProfileModel
+		Mock  struct {
+			Put struct {
+				Output string
+				Error  error
+			}
+			UpdateUser struct {
+				Output models.UserModel
+				Error  error
+			}
+		}
+		Output models.ResponseModel
+	}
+	id := uuid.New().String()
+	date := time.Now()
+	name := "test"
+	longName := strings.Repeat("ก", 51)
+	cases := []test{
+		{
+			Name: "update profile success",
+			Input: models.UpdateProfileModel{
+				DisplayName: &name,
+			},
+			Mock: struct {
+				Put struct {
+					Output string
+					Error  error
+				}
+				UpdateUser struct {
+					Output models.UserModel
+					Error  error
+				}
+			}{
+				UpdateUser: struct {
+					Output models.UserModel
+					Error  error
+				}{
+					Output: models.UserModel{
+						ID:          id,
+						Password:    "hash",
+						DisplayName: name,
+						UpdateDate:  date,
+					},
+				},
+			},
+			Output: models.ResponseModel{
+				Status:  true,
+				Code:    200,
+				Message: "update profile success",
+				Result: models.UserResModel{
+					Role:        models.RoleUser,
+					ID:          id,
+					DisplayName: name,
+					UpdateDate:  date,
+				},
+			},
+		},
+		{
+			Name:  "nothing to update",
+			Input: models.UpdateProfileModel{},
+			Output: models.ResponseModel{
+				Status:  false,
+				Code:    400,
+				Message: "nothing to update",
+				Result:  nil,
+			},
+		},
+		{
+			Name: "display name too long",
+			Input: models.UpdateProfileModel{
+				DisplayName: &longName,
+			},
+			Output: models.ResponseModel{
+				Status:  false,
+				Code:    400,
+				Message: "display name must be <= 50 characters",
+				Result:  nil,
+			},
+		},
+		{
+			Name: "avatar invalid type",
+			Input: models.UpdateProfileModel{
+				Avatar: &models.UploadFileModel{
+					Filename:    "avatar.txt",
+					ContentType: "text/plain",
+					Size:        4,
+					Content:     strings.NewReader("test"),
+				},
+			},
+			Output: models.ResponseModel{
+				Status:  false,
+				Code:    400,
+				Message: "avatar must be png, jpeg, gif or webp",
+				Result:  nil,
+			},
+		},
+		{
+			Name: "upload avatar success",
+			Input: models.UpdateProfileModel{
+				Avatar: &models.UploadFileModel{
+					Filename:    "avatar.png",
+					ContentType: "image/png",
+					Size:        4,
+					Content:     strings.NewReader("test"),
+				},
+			},
+			Mock: struct {
+				Put struct {
+					Output string
+					Error  error
+				}
+				UpdateUser struct {
+					Output models.UserModel
+					Error  error
+				}
+			}{
+				Put: struct {
+					Output string
+					Error  error
+				}{
+					Output: "/uploads/avatars/avatar.png",
+				},
+				UpdateUser: struct {
+					Output models.UserModel
+					Error  error
+				}{
+					Output: models.UserModel{
+						ID:         id,
+						AvatarURL:  "/uploads/avatars/avatar.png",
+						AvatarKey:  "avatars/avatar.png",
+						UpdateDate: date,
+					},
+				},
+			},
+			Output: models.ResponseModel{
+				Status:  true,
+				Code:    200,
+				Message: "update profile success",
+				Result: models.UserResModel{
+					Role:       models.RoleUser,
+					ID:         id,
+					AvatarURL:  "/uploads/avatars/avatar.png",
+					UpdateDate: date,
+				},
+			},
+		},
+		{
+			Name: "upload avatar error",
+			Input: models.UpdateProfileModel{
+				Avatar: &models.UploadFileModel{
+					Filename:    "avatar.png",
+					ContentType: "image/png",
+					Size:        4,
+					Content:     strings.NewReader("test"),
+				},
+			},
+			Mock: struct {
+				Put struct {
+					Output string
+					Error  error
+				}
+				UpdateUser struct {
+					Output models.UserModel
+					Error  error
+				}
+			}{
+				Put: struct {
+					Output string
+					Error  error
+				}{
+					Error: errors.New("disk full"),
+				},
+			},
+			Output: models.ResponseModel{
+				Status:  false,
+				Code:    500,
+				Message: "disk full",
+				Result:  nil,
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			userRepo := repositories.NewUserRepositoryMock()
+			blobRepo := repositories.NewBlobRepositoryMock()
+			userRepo.On("GetUserByID", id).Return(models.UserModel{ID: id}, nil)
+			userRepo.On("UpdateUser", id, mock.Anything).Return(c.Mock.UpdateUser.Output, c.Mock.UpdateUser.Error)
+			blobRepo.On("Put", mock.Anything, mock.Anything).Return(c.Mock.Put.Output, c.Mock.Put.Error)
+			userService := services.NewUserService(accountRepos(services.AccountRepositories{User: userRepo, Blob: blobRepo}))
+			result := userService.UpdateProfile(id, c.Input)
+			assert.Equal(t, c.Output, result)
+		})
+	}
+}
+
+func Test_ChangePassword(t *testing.T) {
+	type test struct {
+		Name  string
+		Input struct {
+			CurrentPassword string
+			NewPassword     string
+		}
+		Output models.ResponseModel
+	}
+	id := uuid.New().String()
+	cases := []test{
+		{
+			Name: "change password success",
+			Input: struct {
+				CurrentPassword string
+				NewPassword     string
+			}{
+				CurrentPassword: "123",
+				NewPassword:     "456",
+			},
+			Output: models.ResponseModel{
+				Status:  true,
+				Code:    200,
+				Message: "change password success",
+				Result:  nil,
+			},
+		},
+		{
+			Name: "new password not found",
+			Input: struct {
+				CurrentPassword string
+				NewPassword     string
+			}{
+				CurrentPassword: "123",
+				NewPassword:     "",
+			},
+			Output: models.ResponseModel{
+				Status:  false,
+				Code:    400,
+				Message: "current password or new password not found",
+				Result:  nil,
+			},
+		},
+		{
+			Name: "current password invalid",
+			Input: struct {
+				CurrentPassword string
+				NewPassword     string
+			}{
+				CurrentPassword: "321",
+				NewPassword:     "456",
+			},
+			Output: models.ResponseModel{
+				Status:  false,
+				Code:    400,
+				Message: "password invalid",
+				Result:  nil,
+			},
+		},
+		{
+			Name: "same password",
+			Input: struct {
+				CurrentPassword string
+				NewPassword     string
+			}{
+				CurrentPassword: "123",
+				NewPassword:     "123",
+			},
+			Output: models.ResponseModel{
+				Status:  false,
+				Code:    400,
+				Message: "new password must be different from current password",
+				Result:  nil,
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			userRepo := repositories.NewUserRepositoryMock()
+			userRepo.On("GetUserByID", id).Return(models.UserModel{
+				ID:       id,
+				Password: "$2a$10$TODe5QSVwJdjrhPnpKPZb.uRL7dMA3YnOx6VCXcZs5HiPoYHs7c.6",
+			}, nil)
+			userRepo.On("UpdateUser", id, mock.Anything).Return(models.UserModel{}, nil)
+			userService := services.NewUserService(accountRepos(services.AccountRepositories{User: userRepo}))
+			result := userService.ChangePassword(id, c.Input.CurrentPassword, c.Input.NewPassword)
+			assert.Equal(t, c.Output, result)
+		})
+	}
+}
+
+func Test_ExportData(t *testing.T) {
+	id := uuid.New().String()
+	quoteID := uuid.New().String()
+	userRepo := repositories.NewUserRepositoryMock()
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	auditRepo := repositories.NewAuditRepositoryMock()
+	userRepo.On("GetUserByID", id).Return(models.UserModel{ID: id, Password: "hash", QouteID: quoteID}, nil)
+	quoteRepo.On("GetQuotesByCreator", id).Return([]models.QuoteModel{{ID: quoteID, CreatedBy: id}}, nil)
+	quoteRepo.On("GetQuote", quoteID).Return(models.QuoteModel{ID: quoteID, Vote: 1}, nil)
+	auditRepo.On("CreateAudit", mock.Anything).Return(nil)
+	auditRepo.On("GetAuditsByUser", id).Return([]models.AuditModel{{UserID: id, Action: "export_data"}}, nil)
+
+	userService := services.NewUserService(accountRepos(services.AccountRepositories{User: userRepo, Quote: quoteRepo, Audit: auditRepo}))
+	result := userService.ExportData(id)
+
+	assert.Equal(t, "export data success", result.Message)
+	data := result.Result.(models.UserExportModel)
+	assert.Equal(t, models.UserResModel{ID: id, QouteID: quoteID, Role: models.RoleUser}, data.Profile)
+	assert.Len(t, data.Quotes, 1)
+	assert.Equal(t, []models.QuoteModel{{ID: quoteID, Vote: 1}}, data.Votes)
+	assert.Len(t, data.Audits, 1)
+}
+
+func Test_DeleteAccount(t *testing.T) {
+	type test struct {
+		Name   string
+		Input  string
+		Output models.ResponseModel
+	}
+	id := uuid.New().String()
+	cases := []test{
+		{
+			Name:  "delete account scheduled",
+			Input: id,
+			Output: models.ResponseModel{
+				Status:  true,
+				Code:    202,
+				Message: "delete account scheduled",
+			},
+		},
+		{
+			Name:  "unauthorized",
+			Input: "",
+			Output: models.ResponseModel{
+				Status:  false,
+				Code:    401,
+				Message: "unauthorized",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			userRepo := repositories.NewUserRepositoryMock()
+			userRepo.On("GetUserByID", id).Return(models.UserModel{ID: id}, nil)
+			userRepo.On("ScheduleDeleteUser", id, mock.AnythingOfType("*time.Time")).Return(models.UserModel{ID: id}, nil)
+			userService := services.NewUserService(accountRepos(services.AccountRepositories{User: userRepo}))
+			result := userService.DeleteAccount(c.Input)
+
+			assert.Equal(t, c.Output.Status, result.Status)
+			assert.Equal(t, c.Output.Code, result.Code)
+			assert.Equal(t, c.Output.Message, result.Message)
+		})
+	}
+}
+
+func Test_CancelDeleteAccount(t *testing.T) {
+	type test struct {
+		Name string
+		Mock struct {
+			GetUserByID models.UserModel
+		}
+		Output models.ResponseModel
+	}
+	id := uuid.New().String()
+	deleteAt := time.Now().Add(time.Hour)
+	cases := []test{
+		{
+			Name: "cancel delete account success",
+			Mock: struct {
+				GetUserByID models.UserModel
+			}{
+				GetUserByID: models.UserModel{ID: id, DeleteAt: &deleteAt},
+			},
+			Output: models.ResponseModel{
+				Status:  true,
+				Code:    200,
+				Message: "cancel delete account success",
+				Result:  models.UserResModel{ID: id, Role: models.RoleUser},
+			},
+		},
+		{
+			Name: "not scheduled",
+			Mock: struct {
+				GetUserByID models.UserModel
+			}{
+				GetUserByID: models.UserModel{ID: id},
+			},
+			Output: models.ResponseModel{
+				Status:  false,
+				Code:    400,
+				Message: "account is not scheduled for deletion",
+				Result:  nil,
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			userRepo := repositories.NewUserRepositoryMock()
+			userRepo.On("GetUserByID", id).Return(c.Mock.GetUserByID, nil)
+			userRepo.On("ScheduleDeleteUser", id, (*time.Time)(nil)).Return(models.UserModel{ID: id}, nil)
+			userService := services.NewUserService(accountRepos(services.AccountRepositories{User: userRepo}))
+			result := userService.CancelDeleteAccount(id)
+
+			assert.Equal(t, c.Output, result)
+		})
+	}
+}
+
+func Test_PurgeDeletedAccounts(t *testing.T) {
+	id := uuid.New().String()
+	quoteID := uuid.New().String()
+	userRepo := repositories.NewUserRepositoryMock()
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	auditRepo := repositories.NewAuditRepositoryMock()
+	blobRepo := repositories.NewBlobRepositoryMock()
+	userRepo.On("GetUsersDueForDeletion", mock.Anything).Return([]models.UserModel{{ID: id, QouteID: quoteID, AvatarKey: "avatars/a.png"}}, nil)
+	userRepo.On("DeleteUser", id).Return(nil)
+	userRepo.On("ClearVote", id).Return(nil)
+	quoteRepo.On("IncrementVote", quoteID, -1).Return(nil)
+	quoteRepo.On("ClearCreator", id).Return(nil)
+	auditRepo.On("DeleteAuditsByUser", id).Return(nil)
+	blobRepo.On("Delete", "avatars/a.png").Return(nil)
+
+	userService := services.NewUserService(accountRepos(services.AccountRepositories{User: userRepo, Quote: quoteRepo, Blob: blobRepo, Audit: auditRepo}))
+	result := userService.PurgeDeletedAccounts()
+
+	assert.Equal(t, models.ResponseModel{
+		Status:  true,
+		Code:    200,
+		Message: "purge accounts success",
+		Result:  1,
+	}, result)
+	quoteRepo.AssertExpectations(t)
+	userRepo.AssertExpectations(t)
+}
+
+func Test_PurgeDeletedAccountsMissingQuote(t *testing.T) {
+	userRepo := repositories.NewUserRepositoryMock()
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	auditRepo := repositories.NewAuditRepositoryMock()
+	userRepo.On("GetUsersDueForDeletion", mock.Anything).Return([]models.UserModel{{ID: "u1", QouteID: "gone"}}, nil)
+	userRepo.On("DeleteUser", "u1").Return(nil)
+	userRepo.On("ClearVote", "u1").Return(nil)
+	// the voted quote was hard deleted, the purge still goes through
+	quoteRepo.On("IncrementVote", "gone", -1).Return(mongo.ErrNoDocuments)
+	quoteRepo.On("ClearCreator", "u1").Return(nil)
+	auditRepo.On("DeleteAuditsByUser", "u1").Return(nil)
+
+	userService := services.NewUserService(accountRepos(services.AccountRepositories{User: userRepo, Quote: quoteRepo, Audit: auditRepo}))
+	result := userService.PurgeDeletedAccounts()
+
+	assert.Equal(t, 1, result.Result)
+	userRepo.AssertCalled(t, "DeleteUser", "u1")
+}
+
+func Test_PurgeDeletedAccountsRetry(t *testing.T) {
+	userRepo := repositories.NewUserRepositoryMock()
+	quoteRepo := repositories.NewQuoteRepositoryMock()
+	favoriteRepo := repositories.NewFavoriteRepositoryMock()
+	reactionRepo := repositories.NewReactionRepositoryMock()
+	auditRepo := repositories.NewAuditRepositoryMock()
+	// the first run stops on the second reaction, the retry sees the user as it was left
+	userRepo.On("GetUsersDueForDeletion", mock.Anything).Return([]models.UserModel{{ID: "u1", QouteID: "q0"}}, nil).Once()
+	userRepo.On("GetUsersDueForDeletion", mock.Anything).Return([]models.UserModel{{ID: "u1"}}, nil).Once()
+	userRepo.On("ClearVote", "u1").Return(nil).Once()
+	userRepo.On("DeleteUser", "u1").Return(nil).Once()
+	quoteRepo.On("IncrementVote", "q0", -1).Return(nil).Once()
+	quoteRepo.On("ClearCreator", "u1").Return(nil)
+	auditRepo.On("DeleteAuditsByUser", "u1").Return(nil)
+	favoriteRepo.On("GetAllFavorites", "u1").Return([]models.FavoriteModel{{UserID: "u1", QuoteID: "q1"}}, nil).Once()
+	favoriteRepo.On("GetAllFavorites", "u1").Return([]models.FavoriteModel{}, nil).Once()
+	favoriteRepo.On("RemoveFavorite", "u1", "q1").Return(true, nil).Once()
+	quoteRepo.On("IncrementFavoriteCount", "q1", -1).Return(nil).Once()
+	reactionRepo.On("GetReactionsByUser", "u1").Return([]models.ReactionModel{
+		{UserID: "u1", QuoteID: "q1", Emoji: "👍"},
+		{UserID: "u1", QuoteID: "q2", Emoji: "👍"},
+	}, nil).Once()
+	reactionRepo.On("GetReactionsByUser", "u1").Return([]models.ReactionModel{
+		{UserID: "u1", QuoteID: "q2", Emoji: "👍"},
+	}, nil).Once()
+	reactionRepo.On("RemoveReaction", "q1", "u1", "👍").Return(true, nil).Once()
+	reactionRepo.On("RemoveReaction", "q2", "u1", "👍").Return(false, errors.New("connection reset")).Once()
+	reactionRepo.On("RemoveReaction", "q2", "u1", "👍").Return(true, nil).Once()
+	quoteRepo.On("IncrementReaction", "q1", "👍", -1).Return(nil).Once()
+	quoteRepo.On("IncrementReaction", "q2", "👍", -1).Return(nil).Once()
+
+	userService := services.NewUserService(accountRepos(services.AccountRepositories{User: userRepo, Quote: quoteRepo, Audit: auditRepo, Favorite: favoriteRepo, Reaction: reactionRepo}))
+	first := userService.PurgeDeletedAccounts()
+	assert.Equal(t, 0, first.Result)
+	userRepo.AssertNotCalled(t, "DeleteUser", "u1")
+
+	retry := userService.PurgeDeletedAccounts()
+	assert.Equal(t, 1, retry.Result)
+	// every tally was taken back exactly once over both runs
+	quoteRepo.AssertNumberOfCalls(t, "IncrementVote", 1)
+	quoteRepo.AssertNumberOfCalls(t, "IncrementFavoriteCount", 1)
+	quoteRepo.AssertNumberOfCalls(t, "IncrementReaction", 2)
+	quoteRepo.AssertExpectations(t)
+	userRepo.AssertExpectations(t)
+	reactionRepo.AssertExpectations(t)
+}
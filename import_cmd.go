@@ -0,0 +1,47 @@
+package main
+
+import (
+	"backend/core/models"
+	"backend/core/services"
+	"backend/utils"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// importCommand imports a quote file from disk the way POST /admin/quotes/import
+// does and prints the report, the exit code is 1 when the import stops early.
+func importCommand(quoteService services.QuoteService, args []string) int {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	format := flags.String("format", "", "csv, json or ndjson, by default taken from the file extension")
+	dryRun := flags.Bool("dry-run", false, "check every row without storing anything")
+	userID := flags.String("user", "", "id of the user the quotes are created by")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: import [-format csv|json|ndjson] [-dry-run] [-user id] file")
+		return 2
+	}
+
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer file.Close()
+	upload := models.UploadFileModel{
+		Filename: file.Name(),
+		Content:  file,
+	}
+	if info, err := file.Stat(); err == nil {
+		upload.Size = info.Size()
+	}
+
+	result := quoteService.ImportQuotes(*userID, *format, *dryRun, upload)
+	utils.PrintJson(result)
+	if !result.Status {
+		return 1
+	}
+	return 0
+}
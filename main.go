@@ -4,8 +4,13 @@ import (
 	"backend/config"
 	"backend/core/handlers"
 	"backend/core/middlewares"
+	"backend/core/models"
 	"backend/core/repositories"
 	"backend/core/services"
+	"backend/utils"
+	"log"
+	"os"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -16,26 +21,228 @@ func init() {
 }
 func main() {
 	db := config.NewAppDatabase()
-	app := fiber.New()
+	app := fiber.New(fiber.Config{
+		BodyLimit:                    config.Env.BodyLimit,
+		StreamRequestBody:            true,
+		DisablePreParseMultipartForm: true,
+	})
 	app.Use(cors.New(config.CorsConfig()))
+	app.Use(middlewares.BodyLimit("/admin/quotes/import"))
 
 	// repositories
 	quoteRepo := repositories.NewQuoteRepository(db, "quotes")
 	userRepo := repositories.NewUserRepository(db, "users")
+	auditRepo := repositories.NewAuditRepository(db, "audits")
+	blobRepo := repositories.NewLocalBlobRepository(config.Env.BlobDir, config.Env.BlobURL)
+	searchRepo := repositories.NewMemorySearchRepository()
+	duplicateRepo := repositories.NewMemoryDuplicateRepository()
+	filterRepo := repositories.NewFileFilterRepository(config.Env.ContentFilterPath)
+	tagRepo := repositories.NewTagRepository(db, "tags")
+	categoryRepo := repositories.NewCategoryRepository(db, "categories")
+	authorRepo := repositories.NewAuthorRepository(db, "authors")
+	revisionRepo := repositories.NewRevisionRepository(db, "revisions")
+	notificationRepo := repositories.NewNotificationRepository(db, "notifications")
+	reportRepo := repositories.NewReportRepository(db, "reports")
+	favoriteRepo := repositories.NewFavoriteRepository(db, "favorites")
+	commentRepo := repositories.NewCommentRepository(db, "comments")
+	reactionRepo := repositories.NewReactionRepository(db, "reactions")
+	dailyQuoteRepo := repositories.NewDailyQuoteRepository(db, "daily_quotes")
+	seenRepo := repositories.NewSeenRepository(db, "seen_quotes")
+	leaseRepo := repositories.NewLeaseRepository(db, "leases")
+	cardTemplateRepo := repositories.NewFileCardTemplateRepository(config.Env.CardTemplatePath)
+	mailboxRepo := repositories.NewMaildirRepository(config.Env.MaildirPath)
 	// services
-	quoteService := services.NewQuoteService(quoteRepo)
-	userService := services.NewUserService(userRepo)
+	quoteService := services.NewQuoteService(services.QuoteRepositories{
+		Quote:     quoteRepo,
+		Search:    searchRepo,
+		Tag:       tagRepo,
+		Category:  categoryRepo,
+		Author:    authorRepo,
+		Revision:  revisionRepo,
+		User:      userRepo,
+		Duplicate: duplicateRepo,
+		Filter:    filterRepo,
+		Favorite:  favoriteRepo,
+		Comment:   commentRepo,
+		Reaction:  reactionRepo,
+	})
+	tagService := services.NewTagService(tagRepo, quoteRepo)
+	categoryService := services.NewCategoryService(categoryRepo, quoteRepo)
+	authorService := services.NewAuthorService(authorRepo, quoteRepo)
+	accountRepos := services.AccountRepositories{
+		User:         userRepo,
+		Quote:        quoteRepo,
+		Blob:         blobRepo,
+		Audit:        auditRepo,
+		Notification: notificationRepo,
+		Favorite:     favoriteRepo,
+		Comment:      commentRepo,
+		Reaction:     reactionRepo,
+		Seen:         seenRepo,
+	}
+	userService := services.NewUserService(accountRepos)
+	adminService := services.NewAdminService(accountRepos)
+	moderationService := services.NewModerationService(quoteRepo, searchRepo, duplicateRepo, notificationRepo, reportRepo, userRepo, leaseRepo)
+	notificationService := services.NewNotificationService(notificationRepo)
+	favoriteService := services.NewFavoriteService(favoriteRepo, quoteRepo)
+	commentService := services.NewCommentService(commentRepo, quoteRepo)
+	reactionService := services.NewReactionService(reactionRepo, quoteRepo, userRepo)
+	dailyQuoteService := services.NewDailyQuoteService(dailyQuoteRepo, quoteRepo)
+	randomQuoteService := services.NewRandomQuoteService(quoteRepo, seenRepo)
+	cardService := services.NewCardService(quoteRepo, cardTemplateRepo)
+	feedService := services.NewFeedService(quoteRepo)
+	exportService := services.NewExportService(quoteRepo, userRepo, categoryRepo)
+	mailService := services.NewMailService(quoteService, userRepo, mailboxRepo)
 	// handlers
 	quoteHandler := handlers.NewQuoteHandler(quoteService)
 	userHandler := handlers.NewUserHandler(userService)
+	adminHandler := handlers.NewAdminHandler(adminService)
+	tagHandler := handlers.NewTagHandler(tagService)
+	categoryHandler := handlers.NewCategoryHandler(categoryService)
+	authorHandler := handlers.NewAuthorHandler(authorService)
+	moderationHandler := handlers.NewModerationHandler(moderationService)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	favoriteHandler := handlers.NewFavoriteHandler(favoriteService)
+	commentHandler := handlers.NewCommentHandler(commentService)
+	reactionHandler := handlers.NewReactionHandler(reactionService)
+	dailyQuoteHandler := handlers.NewDailyQuoteHandler(dailyQuoteService)
+	randomQuoteHandler := handlers.NewRandomQuoteHandler(randomQuoteService)
+	cardHandler := handlers.NewCardHandler(cardService)
+	feedHandler := handlers.NewFeedHandler(feedService)
+	exportHandler := handlers.NewExportHandler(exportService)
+	mailHandler := handlers.NewMailHandler(mailService)
+	// middlewares
+	accessToken := middlewares.AccessToken(userRepo)
+	adminOnly := middlewares.RequireRole(models.RoleAdmin)
+	moderatorOnly := middlewares.RequireRole(models.RoleModerator, models.RoleAdmin)
 	// routes
+	app.Static(config.Env.BlobURL, config.Env.BlobDir)
 	app.Post("/register", userHandler.CreateUser)
 	app.Post("/signin", userHandler.SignIn)
-	app.Put("/user/:id/:qouteID", middlewares.AccessToken, userHandler.UpdateVote)
+	app.Put("/user/:id/:qouteID", accessToken, userHandler.UpdateVote)
+	// feed readers can not sign in, feeds only carry approved quotes
+	app.Get("/feeds/:kind.:format", feedHandler.GetFeed)
+	// the mail server forwards the quotes users mail in, the sender is matched to a user
+	app.Post("/mail/inbound", middlewares.MailToken(), mailHandler.IngestMail)
+	app.Get("/users/me", accessToken, userHandler.GetMe)
+	app.Patch("/users/me", accessToken, userHandler.UpdateProfile)
+	app.Post("/users/me/password", accessToken, userHandler.ChangePassword)
+	app.Get("/users/me/export", accessToken, userHandler.ExportData)
+	app.Delete("/users/me", accessToken, userHandler.DeleteAccount)
+	app.Post("/users/me/cancel-delete", accessToken, userHandler.CancelDeleteAccount)
+	app.Get("/users/me/notifications", accessToken, notificationHandler.GetNotifications)
+	app.Post("/users/me/notifications/read", accessToken, notificationHandler.MarkNotificationsRead)
+	app.Get("/users/me/favorites", accessToken, favoriteHandler.GetFavorites)
 
-	app.Get("/quote", middlewares.AccessToken, quoteHandler.GetQuotes)
-	app.Post("/quote", middlewares.AccessToken, quoteHandler.CreateQuote)
-	app.Put("/quote/:id", middlewares.AccessToken, quoteHandler.UpdateQuote)
-	app.Delete("/quote/:id", middlewares.AccessToken, quoteHandler.DeleteQuote)
+	app.Get("/quote", accessToken, quoteHandler.GetQuotes)
+	app.Get("/quote/search", accessToken, quoteHandler.SearchQuotes)
+	app.Get("/quote/daily", accessToken, dailyQuoteHandler.GetDailyQuote)
+	app.Get("/quote/daily/history", accessToken, dailyQuoteHandler.GetDailyHistory)
+	app.Get("/quote/random", accessToken, randomQuoteHandler.GetRandomQuotes)
+	app.Post("/quote", accessToken, quoteHandler.CreateQuote)
+	app.Put("/quote/:id", accessToken, quoteHandler.UpdateQuote)
+	app.Get("/quote/:id/revisions", accessToken, quoteHandler.GetRevisions)
+	app.Post("/quote/:id/revert/:rev", accessToken, quoteHandler.RevertQuote)
+	app.Get("/quote/:id/card.png", accessToken, cardHandler.GetCardPNG)
+	app.Get("/quote/:id/card.svg", accessToken, cardHandler.GetCardSVG)
+	app.Get("/quote/:id/translations", accessToken, quoteHandler.GetTranslations)
+	app.Post("/quote/:id/translations", accessToken, quoteHandler.AddTranslation)
+	app.Delete("/quote/:id/translations", accessToken, quoteHandler.RemoveTranslation)
+	app.Delete("/quote/:id", accessToken, quoteHandler.DeleteQuote)
+	app.Post("/quote/:id/report", accessToken, moderationHandler.ReportQuote)
+	app.Post("/quote/:id/favorite", accessToken, favoriteHandler.AddFavorite)
+	app.Delete("/quote/:id/favorite", accessToken, favoriteHandler.RemoveFavorite)
+	app.Get("/quote/:id/reactions", accessToken, reactionHandler.GetReactions)
+	app.Post("/quote/:id/reactions", accessToken, reactionHandler.AddReaction)
+	app.Delete("/quote/:id/reactions", accessToken, reactionHandler.RemoveReaction)
+	app.Get("/quote/:id/comments", accessToken, commentHandler.GetComments)
+	app.Post("/quote/:id/comments", accessToken, commentHandler.CreateComment)
+	app.Put("/comments/:id", accessToken, commentHandler.UpdateComment)
+	app.Delete("/comments/:id", accessToken, commentHandler.DeleteComment)
+	app.Post("/quote/:id/restore", accessToken, moderatorOnly, quoteHandler.RestoreQuote)
+	app.Get("/trash", accessToken, moderatorOnly, quoteHandler.GetTrash)
+
+	moderation := app.Group("/moderation", accessToken, moderatorOnly)
+	moderation.Get("/queue", moderationHandler.GetQueue)
+	moderation.Post("/quotes/:id/approve", moderationHandler.ApproveQuote)
+	moderation.Post("/quotes/:id/reject", moderationHandler.RejectQuote)
+	moderation.Get("/reports", moderationHandler.GetReports)
+	moderation.Post("/reports/:id/dismiss", moderationHandler.DismissReports)
+	moderation.Post("/reports/:id/act", moderationHandler.ActOnReports)
+
+	app.Get("/tags", accessToken, tagHandler.GetTags)
+	app.Post("/tags", accessToken, moderatorOnly, tagHandler.CreateTag)
+	app.Put("/tags/:id", accessToken, moderatorOnly, tagHandler.RenameTag)
+	app.Post("/tags/:id/merge", accessToken, moderatorOnly, tagHandler.MergeTag)
+
+	app.Get("/categories", accessToken, categoryHandler.GetCategories)
+	app.Post("/categories", accessToken, moderatorOnly, categoryHandler.CreateCategory)
+	app.Put("/categories/:id", accessToken, moderatorOnly, categoryHandler.UpdateCategory)
+	app.Delete("/categories/:id", accessToken, moderatorOnly, categoryHandler.DeleteCategory)
+
+	app.Get("/export/quotes", accessToken, exportHandler.ExportQuotes)
+	app.Get("/export/results", accessToken, exportHandler.ExportResults)
+
+	app.Get("/authors", accessToken, authorHandler.GetAuthors)
+	app.Get("/authors/:id", accessToken, authorHandler.GetAuthor)
+	app.Put("/authors/:id", accessToken, moderatorOnly, authorHandler.UpdateAuthor)
+
+	admin := app.Group("/admin", accessToken, adminOnly)
+	admin.Get("/users", adminHandler.GetUsers)
+	admin.Get("/users/:id", adminHandler.GetUser)
+	admin.Post("/users/:id/suspend", adminHandler.SuspendUser)
+	admin.Post("/users/:id/unsuspend", adminHandler.UnsuspendUser)
+	admin.Put("/users/:id/role", adminHandler.UpdateRole)
+	admin.Put("/users/:id/trusted", adminHandler.SetTrusted)
+	admin.Post("/users/:id/reset-password", adminHandler.ForcePasswordReset)
+	admin.Delete("/users/:id", adminHandler.DeleteUser)
+	admin.Get("/duplicates", quoteHandler.GetDuplicates)
+	admin.Post("/duplicates/merge", quoteHandler.MergeQuotes)
+	admin.Post("/quotes/import", quoteHandler.ImportQuotes)
+	admin.Get("/content-filter", quoteHandler.GetContentFilter)
+	admin.Post("/content-filter/reload", quoteHandler.ReloadContentFilter)
+	admin.Put("/daily-quote", dailyQuoteHandler.SetDailyQuote)
+	admin.Get("/card-templates", cardHandler.GetCardTemplates)
+	admin.Post("/card-templates/reload", cardHandler.ReloadCardTemplates)
+	// jobs
+	if result := quoteService.ReloadContentFilter(); !result.Status {
+		log.Fatal(result.Message)
+	}
+	if result := cardService.ReloadCardTemplates(); !result.Status {
+		log.Fatal(result.Message)
+	}
+	if err := quoteService.MigrateQuotes(); err != nil {
+		log.Fatal(err)
+	}
+	if err := quoteService.EnsureIndexes(); err != nil {
+		log.Fatal(err)
+	}
+	if err := quoteService.ReindexQuotes(); err != nil {
+		log.Fatal(err)
+	}
+	// `go run . import [-format csv] [-dry-run] [-user id] file` seeds quotes without serving
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		os.Exit(importCommand(quoteService, os.Args[2:]))
+	}
+	utils.Every(time.Hour, func() {
+		if result := userService.PurgeDeletedAccounts(); !result.Status {
+			log.Println(result.Message)
+		}
+		if result := quoteService.PurgeTrash(); !result.Status {
+			log.Println(result.Message)
+		}
+	})
+	utils.Every(time.Minute, func() {
+		if result := moderationService.PublishScheduledQuotes(); !result.Status {
+			log.Println(result.Message)
+		}
+		// the indexes live in memory, pick up what other instances wrote
+		if err := quoteService.SyncIndexes(); err != nil {
+			log.Println(err)
+		}
+		if result := mailService.IngestMaildir(); !result.Status {
+			log.Println(result.Message)
+		}
+	})
 	app.Listen("localhost:3000")
 }
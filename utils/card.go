@@ -0,0 +1,532 @@
+package utils
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-text/typesetting/di"
+	"github.com/go-text/typesetting/font"
+	ot "github.com/go-text/typesetting/font/opentype"
+	"github.com/go-text/typesetting/language"
+	"github.com/go-text/typesetting/shaping"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+// cardFontData คือฟอนต์ FreeSerif จาก GNU FreeFont มีทั้งอักษรไทยและละติน
+// ใช้ได้ภายใต้ GPLv3 พร้อมข้อยกเว้นสำหรับการฝังฟอนต์ รายละเอียดอยู่ใน fonts/COPYING
+//
+//go:embed fonts/FreeSerif.ttf
+var cardFontData []byte
+
+const (
+	CardAlignLeft   = "left"
+	CardAlignCenter = "center"
+)
+
+// CardStyle กำหนดหน้าตาของการ์ด ขนาดเป็นพิกเซล
+// FontSize คือขนาดตัวอักษรของคำคม ข้อความยาวจะถูกย่อลงทีละขั้นจนถึง MinFontSize
+// SmallFontSize ใช้กับชื่อผู้พูดและบรรทัดล่าง LineHeight เป็นเท่าของขนาดตัวอักษร
+type CardStyle struct {
+	Width         int
+	Height        int
+	Padding       int
+	Background    color.NRGBA
+	Foreground    color.NRGBA
+	Accent        color.NRGBA
+	FontSize      float64
+	MinFontSize   float64
+	SmallFontSize float64
+	LineHeight    float64
+	Align         string
+}
+
+// Card คือเนื้อหาของการ์ดหนึ่งใบ Attribution และ Footer เว้นว่างได้
+type Card struct {
+	Text        string
+	Attribution string
+	Footer      string
+	Style       CardStyle
+}
+
+// cardPath คือสิ่งที่วาดเส้นขอบตัวอักษรได้ ได้แก่ vector.Rasterizer และ svgPath
+type cardPath interface {
+	MoveTo(ax, ay float32)
+	LineTo(bx, by float32)
+	QuadTo(bx, by, cx, cy float32)
+	CubeTo(bx, by, cx, cy, dx, dy float32)
+	ClosePath()
+}
+
+// cardGlyph คือตัวอักษรที่จัดตำแหน่งแล้ว x, y คือจุดเริ่มบนเส้นฐานเป็นพิกเซล
+type cardGlyph struct {
+	id    font.GID
+	x, y  float32
+	scale float32
+	color color.NRGBA
+}
+
+type cardLine struct {
+	glyphs []shaping.Glyph
+	width  float32
+}
+
+// cardTypesetter จัดวางข้อความด้วย HarfBuzz ที่เขียนด้วย Go ล้วน
+// font.Face และ shaper ใช้พร้อมกันหลาย goroutine ไม่ได้ จึงต้องถือ mu ทุกครั้ง
+type cardTypesetter struct {
+	mu        sync.Mutex
+	face      *font.Face
+	shaper    shaping.HarfbuzzShaper
+	segmenter shaping.Segmenter
+	ascent    float32
+	descent   float32
+}
+
+var (
+	cardFontOnce sync.Once
+	cardFont     *cardTypesetter
+	cardFontErr  error
+)
+
+func loadCardFont() (*cardTypesetter, error) {
+	cardFontOnce.Do(func() {
+		face, err := font.ParseTTF(bytes.NewReader(cardFontData))
+		if err != nil {
+			cardFontErr = fmt.Errorf("card font: %w", err)
+			return
+		}
+		t := &cardTypesetter{face: face, ascent: 0.8, descent: -0.2}
+		if extents, ok := face.FontHExtents(); ok {
+			upem := float32(face.Upem())
+			t.ascent, t.descent = extents.Ascender/upem, extents.Descender/upem
+		}
+		cardFont = t
+	})
+	return cardFont, cardFontErr
+}
+
+// ResolveFace ทำให้ cardTypesetter เป็น shaping.Fontmap ที่มีฟอนต์เดียว
+func (t *cardTypesetter) ResolveFace(r rune) *font.Face {
+	return t.face
+}
+
+func (t *cardTypesetter) shape(text string, size float64) cardLine {
+	runes := []rune(text)
+	line := cardLine{}
+	if len(runes) == 0 {
+		return line
+	}
+	input := shaping.Input{
+		Text:      runes,
+		RunStart:  0,
+		RunEnd:    len(runes),
+		Direction: di.DirectionLTR,
+		Face:      t.face,
+		Size:      fixed.Int26_6(size * 64),
+		Script:    language.Latin,
+		Language:  language.NewLanguage("th"),
+	}
+	for _, run := range t.segmenter.Split(input, t) {
+		out := t.shaper.Shape(run)
+		line.glyphs = append(line.glyphs, out.Glyphs...)
+		line.width += fixedToFloat(out.Advance)
+	}
+	return line
+}
+
+func (t *cardTypesetter) measure(text string, size float64) float32 {
+	return t.shape(strings.TrimRightFunc(text, unicode.IsSpace), size).width
+}
+
+func fixedToFloat(v fixed.Int26_6) float32 {
+	return float32(v) / 64
+}
+
+// cardBreaks แบ่งข้อความเป็นช่วงที่ขึ้นบรรทัดใหม่ระหว่างกันได้ ได้แก่หลังช่องว่าง
+// และระหว่างคำภาษาไทยที่ได้จาก splitWords เครื่องหมายวรรคตอนจะติดไปกับคำข้างหน้า
+func cardBreaks(text string) []string {
+	units := []string{}
+	prev := ""
+	for _, word := range splitWords(text) {
+		n := len(units)
+		if n > 0 && !cardBreakable(prev, word) {
+			units[n-1] += word
+		} else {
+			units = append(units, word)
+		}
+		prev = word
+	}
+	return units
+}
+
+func cardBreakable(prev string, next string) bool {
+	last, _ := utf8.DecodeLastRuneInString(prev)
+	first, _ := utf8.DecodeRuneInString(next)
+	if unicode.IsSpace(first) {
+		return false
+	}
+	if unicode.IsSpace(last) {
+		return true
+	}
+	return IsThai(last) && IsThai(first) && !unicode.IsPunct(last) && !unicode.IsPunct(first)
+}
+
+// cardClusters แบ่งคำที่ยาวเกินหนึ่งบรรทัดเป็นทีละตัวอักษร โดยเครื่องหมายซ้อนติดไปกับตัวข้างหน้า
+func cardClusters(word string) []string {
+	clusters := []string{}
+	for _, r := range word {
+		if n := len(clusters); n > 0 && isThaiFollower(r) {
+			clusters[n-1] += string(r)
+			continue
+		}
+		clusters = append(clusters, string(r))
+	}
+	return clusters
+}
+
+// wrap ตัดข้อความเป็นบรรทัดที่กว้างไม่เกิน width บรรทัดใหม่ในข้อความขึ้นบรรทัดเสมอ
+func (t *cardTypesetter) wrap(text string, size float64, width float32) []string {
+	lines := []string{}
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		units := cardBreaks(strings.TrimSpace(paragraph))
+		for i := 0; i < len(units); i++ {
+			unit := units[i]
+			if utf8.RuneCountInString(unit) > 1 && t.measure(unit, size) > width {
+				units = append(units[:i], append(cardClusters(unit), units[i+1:]...)...)
+				unit = units[i]
+			}
+			next := line + unit
+			if line != "" && t.measure(next, size) > width {
+				lines = append(lines, strings.TrimRightFunc(line, unicode.IsSpace))
+				next = strings.TrimLeftFunc(unit, unicode.IsSpace)
+			}
+			line = next
+		}
+		lines = append(lines, strings.TrimRightFunc(line, unicode.IsSpace))
+	}
+	return lines
+}
+
+// ellipsize ตัดท้ายบรรทัดสุดท้ายแล้วต่อด้วย … ให้กว้างไม่เกิน width
+func (t *cardTypesetter) ellipsize(line string, size float64, width float32) string {
+	runes := []rune(strings.TrimRightFunc(line, unicode.IsSpace))
+	for len(runes) > 0 {
+		candidate := strings.TrimRightFunc(string(runes), unicode.IsSpace) + "…"
+		if t.measure(candidate, size) <= width {
+			return candidate
+		}
+		runes = runes[:len(runes)-1]
+		for len(runes) > 0 && isThaiFollower(runes[len(runes)-1]) {
+			runes = runes[:len(runes)-1]
+		}
+	}
+	return "…"
+}
+
+// place จัดบรรทัดลงในแนวนอนตาม align แล้วคืนตัวอักษรพร้อมตำแหน่ง
+func (t *cardTypesetter) place(text string, size float64, baseline float32, style CardStyle, c color.NRGBA) []cardGlyph {
+	line := t.shape(text, size)
+	left := float32(style.Padding)
+	if style.Align == CardAlignCenter {
+		left += (float32(style.Width-2*style.Padding) - line.width) / 2
+	}
+	scale := float32(size) / float32(t.face.Upem())
+	glyphs := make([]cardGlyph, 0, len(line.glyphs))
+	pen := left
+	for _, g := range line.glyphs {
+		glyphs = append(glyphs, cardGlyph{
+			id:    g.GlyphID,
+			x:     pen + fixedToFloat(g.XOffset),
+			y:     baseline - fixedToFloat(g.YOffset),
+			scale: scale,
+			color: c,
+		})
+		pen += fixedToFloat(g.XAdvance)
+	}
+	return glyphs
+}
+
+// layout จัดวางการ์ดทั้งใบ คำคมกับชื่อผู้พูดอยู่กึ่งกลางแนวตั้งของพื้นที่เหนือบรรทัดล่าง
+// ถ้าคำคมยาวเกินพื้นที่จะย่อตัวอักษรลงจนถึง MinFontSize แล้วตัดท้ายด้วย …
+func (t *cardTypesetter) layout(card Card) []cardGlyph {
+	style := card.Style
+	width := float32(style.Width - 2*style.Padding)
+	smallLine := float32(style.SmallFontSize * style.LineHeight)
+	bottom := float32(style.Height - style.Padding)
+	footer := strings.TrimSpace(card.Footer)
+	if footer != "" {
+		bottom -= smallLine
+	}
+	attribution := []string{}
+	if text := strings.TrimSpace(card.Attribution); text != "" {
+		attribution = t.wrap(text, style.SmallFontSize, width)
+	}
+	attributionHeight := float32(0)
+	if len(attribution) > 0 {
+		attributionHeight = smallLine*float32(len(attribution)) + smallLine/2
+	}
+	available := bottom - float32(style.Padding) - attributionHeight
+
+	size := style.FontSize
+	lines := t.wrap(card.Text, size, width)
+	for float32(size*style.LineHeight)*float32(len(lines)) > available && size > style.MinFontSize {
+		size = max(size*0.9, style.MinFontSize)
+		lines = t.wrap(card.Text, size, width)
+	}
+	lineHeight := float32(size * style.LineHeight)
+	if fit := int(available / lineHeight); fit < len(lines) {
+		fit = max(fit, 1)
+		lines = append(lines[:fit-1], t.ellipsize(lines[fit-1], size, width))
+	}
+
+	// baseline ของบรรทัดที่สูง height คือกึ่งกลางบรรทัดเลื่อนลงครึ่งหนึ่งของ ascent+descent
+	baseline := func(top float32, height float32, size float64) float32 {
+		return top + height/2 + (t.ascent+t.descent)*float32(size)/2
+	}
+	blockHeight := lineHeight*float32(len(lines)) + attributionHeight
+	top := float32(style.Padding) + (bottom-float32(style.Padding)-blockHeight)/2
+	glyphs := []cardGlyph{}
+	for i, line := range lines {
+		glyphs = append(glyphs, t.place(line, size, baseline(top+lineHeight*float32(i), lineHeight, size), style, style.Foreground)...)
+	}
+	top += lineHeight*float32(len(lines)) + smallLine/2
+	for i, line := range attribution {
+		glyphs = append(glyphs, t.place(line, style.SmallFontSize, baseline(top+smallLine*float32(i), smallLine, style.SmallFontSize), style, style.Accent)...)
+	}
+	if footer != "" {
+		footerStyle := style
+		footerStyle.Align = CardAlignLeft
+		glyphs = append(glyphs, t.place(footer, style.SmallFontSize, baseline(bottom, smallLine, style.SmallFontSize), footerStyle, style.Accent)...)
+	}
+	return glyphs
+}
+
+// draw วาดเส้นขอบของตัวอักษรลงใน path แกน y ของฟอนต์ชี้ขึ้นจึงต้องกลับด้าน
+func (t *cardTypesetter) draw(g cardGlyph, path cardPath) {
+	outline, ok := t.face.GlyphData(g.id).(font.GlyphOutline)
+	if !ok {
+		return
+	}
+	px := func(p font.SegmentPoint) (float32, float32) {
+		return g.x + p.X*g.scale, g.y - p.Y*g.scale
+	}
+	open := false
+	for _, seg := range outline.Segments {
+		switch seg.Op {
+		case ot.SegmentOpMoveTo:
+			if open {
+				path.ClosePath()
+			}
+			path.MoveTo(px(seg.Args[0]))
+			open = true
+		case ot.SegmentOpLineTo:
+			path.LineTo(px(seg.Args[0]))
+		case ot.SegmentOpQuadTo:
+			bx, by := px(seg.Args[0])
+			cx, cy := px(seg.Args[1])
+			path.QuadTo(bx, by, cx, cy)
+		case ot.SegmentOpCubeTo:
+			bx, by := px(seg.Args[0])
+			cx, cy := px(seg.Args[1])
+			dx, dy := px(seg.Args[2])
+			path.CubeTo(bx, by, cx, cy, dx, dy)
+		}
+	}
+	if open {
+		path.ClosePath()
+	}
+}
+
+// cardColors คืนสีที่ใช้ตามลำดับที่พบ เพื่อวาดตัวอักษรสีเดียวกันในรอบเดียว
+func cardColors(glyphs []cardGlyph) []color.NRGBA {
+	colors := []color.NRGBA{}
+	for _, g := range glyphs {
+		found := false
+		for _, c := range colors {
+			if c == g.color {
+				found = true
+				break
+			}
+		}
+		if !found {
+			colors = append(colors, g.color)
+		}
+	}
+	return colors
+}
+
+// ValidateCardStyle ตรวจว่าขนาดและระยะต่าง ๆ ของการ์ดวาดได้จริง
+func ValidateCardStyle(style CardStyle) error {
+	if style.Width < 1 || style.Height < 1 || style.Width > 4096 || style.Height > 4096 {
+		return fmt.Errorf("card size must be between 1 and 4096 pixels")
+	}
+	if style.Padding < 0 || 2*style.Padding >= style.Width || 2*style.Padding >= style.Height {
+		return fmt.Errorf("card padding too large")
+	}
+	if style.FontSize <= 0 || style.MinFontSize <= 0 || style.SmallFontSize <= 0 || style.LineHeight <= 0 {
+		return fmt.Errorf("card font sizes and line height must be > 0")
+	}
+	if style.Align != CardAlignLeft && style.Align != CardAlignCenter {
+		return fmt.Errorf("card align must be %s or %s", CardAlignLeft, CardAlignCenter)
+	}
+	return nil
+}
+
+// RenderCardPNG วาดการ์ดเป็นไฟล์ PNG
+func RenderCardPNG(card Card) ([]byte, error) {
+	if err := ValidateCardStyle(card.Style); err != nil {
+		return nil, err
+	}
+	t, err := loadCardFont()
+	if err != nil {
+		return nil, err
+	}
+	style := card.Style
+	img := image.NewRGBA(image.Rect(0, 0, style.Width, style.Height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(style.Background), image.Point{}, draw.Src)
+
+	t.mu.Lock()
+	glyphs := t.layout(card)
+	raster := vector.NewRasterizer(style.Width, style.Height)
+	for _, c := range cardColors(glyphs) {
+		raster.Reset(style.Width, style.Height)
+		for _, g := range glyphs {
+			if g.color == c {
+				t.draw(g, raster)
+			}
+		}
+		raster.Draw(img, img.Bounds(), image.NewUniform(c), image.Point{})
+	}
+	t.mu.Unlock()
+
+	buf := bytes.Buffer{}
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// svgPath เขียนเส้นขอบเป็นคำสั่งของ attribute d ใน SVG
+type svgPath struct {
+	strings.Builder
+}
+
+func (p *svgPath) point(cmd byte, xy ...float32) {
+	p.WriteByte(cmd)
+	for i, v := range xy {
+		if i > 0 {
+			p.WriteByte(' ')
+		}
+		p.WriteString(strconv.FormatFloat(float64(v), 'f', -1, 32))
+	}
+}
+
+func (p *svgPath) MoveTo(ax, ay float32) {
+	p.point('M', round2(ax), round2(ay))
+}
+
+func (p *svgPath) LineTo(bx, by float32) {
+	p.point('L', round2(bx), round2(by))
+}
+
+func (p *svgPath) QuadTo(bx, by, cx, cy float32) {
+	p.point('Q', round2(bx), round2(by), round2(cx), round2(cy))
+}
+
+func (p *svgPath) CubeTo(bx, by, cx, cy, dx, dy float32) {
+	p.point('C', round2(bx), round2(by), round2(cx), round2(cy), round2(dx), round2(dy))
+}
+
+func (p *svgPath) ClosePath() {
+	p.WriteByte('Z')
+}
+
+func round2(v float32) float32 {
+	return float32(int64(v*100+copySign(0.5, v))) / 100
+}
+
+func copySign(v float32, sign float32) float32 {
+	if sign < 0 {
+		return -v
+	}
+	return v
+}
+
+func svgColor(c color.NRGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+func svgOpacity(c color.NRGBA) string {
+	if c.A == 0xff {
+		return ""
+	}
+	return fmt.Sprintf(` fill-opacity="%s"`, strconv.FormatFloat(float64(c.A)/255, 'f', 3, 64))
+}
+
+// RenderCardSVG วาดการ์ดเป็น SVG โดยแปลงตัวอักษรเป็นเส้นขอบ
+// จึงแสดงผลเหมือน PNG ทุกประการโดยไม่ขึ้นกับฟอนต์ในเครื่องที่เปิดดู
+func RenderCardSVG(card Card) ([]byte, error) {
+	if err := ValidateCardStyle(card.Style); err != nil {
+		return nil, err
+	}
+	t, err := loadCardFont()
+	if err != nil {
+		return nil, err
+	}
+	style := card.Style
+	buf := bytes.Buffer{}
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" role="img">`, style.Width, style.Height, style.Width, style.Height)
+	buf.WriteString("<title>")
+	xml.EscapeText(&buf, []byte(strings.TrimSpace(card.Text+" "+card.Attribution)))
+	buf.WriteString("</title>")
+	fmt.Fprintf(&buf, `<rect width="100%%" height="100%%" fill="%s"%s/>`, svgColor(style.Background), svgOpacity(style.Background))
+
+	t.mu.Lock()
+	glyphs := t.layout(card)
+	for _, c := range cardColors(glyphs) {
+		path := &svgPath{}
+		for _, g := range glyphs {
+			if g.color == c {
+				t.draw(g, path)
+			}
+		}
+		if path.Len() > 0 {
+			fmt.Fprintf(&buf, `<path fill="%s"%s d="%s"/>`, svgColor(c), svgOpacity(c), path.String())
+		}
+	}
+	t.mu.Unlock()
+
+	buf.WriteString("</svg>")
+	return buf.Bytes(), nil
+}
+
+// ParseHexColor อ่านสีรูปแบบ #RGB, #RRGGBB หรือ #RRGGBBAA
+func ParseHexColor(str string) (color.NRGBA, error) {
+	hex := strings.TrimPrefix(strings.TrimSpace(str), "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	if len(hex) != 8 {
+		return color.NRGBA{}, fmt.Errorf("color %q must be #RGB, #RRGGBB or #RRGGBBAA", str)
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.NRGBA{}, fmt.Errorf("color %q must be #RGB, #RRGGBB or #RRGGBBAA", str)
+	}
+	return color.NRGBA{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}, nil
+}
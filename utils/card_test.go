@@ -0,0 +1,213 @@
+package utils_test
+
+import (
+	"backend/utils"
+	"bytes"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testCardStyle() utils.CardStyle {
+	return utils.CardStyle{
+		Width:         600,
+		Height:        315,
+		Padding:       40,
+		Background:    color.NRGBA{R: 0x11, G: 0x22, B: 0x33, A: 0xff},
+		Foreground:    color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+		Accent:        color.NRGBA{R: 0xcc, G: 0xcc, B: 0xcc, A: 0x80},
+		FontSize:      40,
+		MinFontSize:   16,
+		SmallFontSize: 18,
+		LineHeight:    1.3,
+		Align:         utils.CardAlignCenter,
+	}
+}
+
+func Test_ParseHexColor(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Input    string
+		Expected color.NRGBA
+		Error    bool
+	}{
+		{
+			Name:     "short form",
+			Input:    "#abc",
+			Expected: color.NRGBA{R: 0xaa, G: 0xbb, B: 0xcc, A: 0xff},
+		},
+		{
+			Name:     "long form upper case",
+			Input:    "#1A2B3C",
+			Expected: color.NRGBA{R: 0x1a, G: 0x2b, B: 0x3c, A: 0xff},
+		},
+		{
+			Name:     "with alpha",
+			Input:    "#11223380",
+			Expected: color.NRGBA{R: 0x11, G: 0x22, B: 0x33, A: 0x80},
+		},
+		{
+			Name:     "no hash and spaces",
+			Input:    " ffffff ",
+			Expected: color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+		},
+		{
+			Name:  "wrong length",
+			Input: "#1234",
+			Error: true,
+		},
+		{
+			Name:  "not hex",
+			Input: "#zzzzzz",
+			Error: true,
+		},
+		{
+			Name:  "color name",
+			Input: "red",
+			Error: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			result, err := utils.ParseHexColor(c.Input)
+			if c.Error {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.Expected, result)
+		})
+	}
+}
+
+func Test_ValidateCardStyle(t *testing.T) {
+	cases := []struct {
+		Name  string
+		Edit  func(style *utils.CardStyle)
+		Error string
+	}{
+		{
+			Name: "valid",
+			Edit: func(style *utils.CardStyle) {},
+		},
+		{
+			Name:  "zero width",
+			Edit:  func(style *utils.CardStyle) { style.Width = 0 },
+			Error: "card size must be between 1 and 4096 pixels",
+		},
+		{
+			Name:  "too tall",
+			Edit:  func(style *utils.CardStyle) { style.Height = 5000 },
+			Error: "card size must be between 1 and 4096 pixels",
+		},
+		{
+			Name:  "padding fills the card",
+			Edit:  func(style *utils.CardStyle) { style.Padding = 300 },
+			Error: "card padding too large",
+		},
+		{
+			Name:  "negative padding",
+			Edit:  func(style *utils.CardStyle) { style.Padding = -1 },
+			Error: "card padding too large",
+		},
+		{
+			Name:  "zero line height",
+			Edit:  func(style *utils.CardStyle) { style.LineHeight = 0 },
+			Error: "card font sizes and line height must be > 0",
+		},
+		{
+			Name:  "unknown align",
+			Edit:  func(style *utils.CardStyle) { style.Align = "right" },
+			Error: "card align must be left or center",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			style := testCardStyle()
+			c.Edit(&style)
+			err := utils.ValidateCardStyle(style)
+			if c.Error == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, c.Error)
+		})
+	}
+}
+
+func Test_RenderCardPNG(t *testing.T) {
+	cases := []struct {
+		Name string
+		Card utils.Card
+	}{
+		{
+			Name: "english with attribution and footer",
+			Card: utils.Card{Text: "Stay hungry, stay foolish.", Attribution: "Steve Jobs", Footer: "quotes.example.com"},
+		},
+		{
+			Name: "thai",
+			Card: utils.Card{Text: "ความพยายามอยู่ที่ไหน ความสำเร็จอยู่ที่นั่น", Attribution: "สุภาษิตไทย"},
+		},
+		{
+			Name: "text too long for the card is shrunk and cut",
+			Card: utils.Card{Text: strings.Repeat("The only way to do great work is to love what you do. ", 40)},
+		},
+		{
+			Name: "word wider than a line",
+			Card: utils.Card{Text: strings.Repeat("ความสำเร็จ", 30)},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			style := testCardStyle()
+			c.Card.Style = style
+			data, err := utils.RenderCardPNG(c.Card)
+			assert.NoError(t, err)
+			img, err := png.Decode(bytes.NewReader(data))
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, style.Width, img.Bounds().Dx())
+			assert.Equal(t, style.Height, img.Bounds().Dy())
+			// the padding stays background, the text area has something drawn on it
+			assert.Equal(t, style.Background, color.NRGBAModel.Convert(img.At(1, 1)))
+			drawn := false
+			for y := style.Padding; y < style.Height-style.Padding && !drawn; y++ {
+				for x := style.Padding; x < style.Width-style.Padding; x++ {
+					if color.NRGBAModel.Convert(img.At(x, y)) != style.Background {
+						drawn = true
+						break
+					}
+				}
+			}
+			assert.True(t, drawn)
+		})
+	}
+}
+
+func Test_RenderCardSVG(t *testing.T) {
+	style := testCardStyle()
+	data, err := utils.RenderCardSVG(utils.Card{
+		Text:        `Less is <more> & "better"`,
+		Attribution: "Mies",
+		Style:       style,
+	})
+	assert.NoError(t, err)
+	svg := string(data)
+	assert.True(t, strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="600" height="315" viewBox="0 0 600 315" role="img">`))
+	assert.True(t, strings.HasSuffix(svg, "</svg>"))
+	assert.Contains(t, svg, "<title>Less is &lt;more&gt; &amp; &#34;better&#34; Mies</title>")
+	assert.Contains(t, svg, `<rect width="100%" height="100%" fill="#112233"/>`)
+	assert.Contains(t, svg, `<path fill="#ffffff" d="M`)
+	assert.Contains(t, svg, `<path fill="#cccccc" fill-opacity="0.502" d="M`)
+	assert.NotContains(t, svg, "<more>")
+
+	_, err = utils.RenderCardSVG(utils.Card{Text: "x", Style: utils.CardStyle{}})
+	assert.Error(t, err)
+}
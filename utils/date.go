@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"errors"
+	"time"
+)
+
+// ParseDateTime รองรับรูปแบบ RFC 3339, "2006-01-02 15:04:05" (ตาม IsDateTimeFormat) และ "2006-01-02"
+// รูปแบบที่ไม่มี timezone จะถูกตีความเป็นเวลาตาม loc
+func ParseDateTime(str string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, str); err == nil {
+		return t, nil
+	}
+	if IsDateTimeFormat(str) {
+		return time.ParseInLocation("2006-01-02 15:04:05", str, loc)
+	}
+	if t, err := time.ParseInLocation("2006-01-02", str, loc); err == nil {
+		return t, nil
+	}
+	return time.Time{}, errors.New("invalid date format")
+}
+
+// IsDateOnly สำหรับเช็คว่า String นั้นเป็นรูปแบบ 2006-01-02 หรือไม่
+func IsDateOnly(str string) bool {
+	_, err := time.Parse("2006-01-02", str)
+	return err == nil
+}
@@ -0,0 +1,132 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	DiffEqual  = "equal"
+	DiffInsert = "insert"
+	DiffDelete = "delete"
+)
+
+// maxDiffCells จำกัดขนาดตาราง LCS ข้อความที่ยาวเกินจะถือว่าถูกแทนที่ทั้งหมด
+const maxDiffCells = 4000000
+
+type DiffPart struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// splitWords แยกข้อความเป็นคำโดยเก็บช่องว่างและเครื่องหมายไว้ด้วย เมื่อต่อกันกลับจะได้ข้อความเดิม
+// ภาษาไทยใช้ SegmentThai
+func splitWords(text string) []string {
+	runes := []rune(text)
+	words := []string{}
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		j := i + 1
+		switch {
+		case IsThai(r) && !unicode.IsPunct(r):
+			for j < len(runes) && IsThai(runes[j]) && !unicode.IsPunct(runes[j]) {
+				j++
+			}
+			words = append(words, SegmentThai(string(runes[i:j]))...)
+			i = j
+			continue
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			for j < len(runes) && !IsThai(runes[j]) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || unicode.Is(unicode.Mn, runes[j])) {
+				j++
+			}
+		case unicode.IsSpace(r):
+			for j < len(runes) && unicode.IsSpace(runes[j]) {
+				j++
+			}
+		}
+		words = append(words, string(runes[i:j]))
+		i = j
+	}
+	return words
+}
+
+// DiffWords เปรียบเทียบข้อความสองชุดทีละคำ ผลลัพธ์เรียงตามลำดับข้อความ
+// ส่วนที่ติดกันและเป็นชนิดเดียวกันจะถูกรวมเป็นชิ้นเดียว
+func DiffWords(from string, to string) []DiffPart {
+	a, b := splitWords(from), splitWords(to)
+	parts := []DiffPart{}
+	add := func(op string, text string) {
+		if n := len(parts); n > 0 && parts[n-1].Op == op {
+			parts[n-1].Text += text
+			return
+		}
+		parts = append(parts, DiffPart{Op: op, Text: text})
+	}
+	if len(a)*len(b) > maxDiffCells {
+		if from != "" {
+			add(DiffDelete, from)
+		}
+		if to != "" {
+			add(DiffInsert, to)
+		}
+		return parts
+	}
+	// lcs[i][j] คือความยาว LCS ของ a[i:] และ b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			add(DiffEqual, a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			add(DiffDelete, a[i])
+			i++
+		default:
+			add(DiffInsert, b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		add(DiffDelete, a[i])
+	}
+	for ; j < len(b); j++ {
+		add(DiffInsert, b[j])
+	}
+	return parts
+}
+
+// DiffSimilarity คืนค่าความเหมือนระหว่าง 0 ถึง 1 โดยนับเฉพาะตัวอักษรที่ไม่ใช่ช่องว่าง
+func DiffSimilarity(parts []DiffPart) float64 {
+	count := func(text string) int {
+		return utf8.RuneCountInString(strings.Join(strings.Fields(text), ""))
+	}
+	equal, total := 0, 0
+	for _, part := range parts {
+		n := count(part.Text)
+		if part.Op == DiffEqual {
+			equal += 2 * n
+			total += 2 * n
+		} else {
+			total += n
+		}
+	}
+	if total == 0 {
+		return 1
+	}
+	return float64(equal) / float64(total)
+}
@@ -0,0 +1,152 @@
+package utils
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"regexp"
+	"strings"
+
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// Mail คือส่วนของอีเมลที่ใช้สร้างคำคม Text เป็นเนื้อหาแบบข้อความล้วน
+// อีเมลที่มีแต่ HTML จะถูกแปลงเป็นข้อความให้
+type Mail struct {
+	MessageID string
+	From      string
+	FromName  string
+	Subject   string
+	Text      string
+}
+
+var (
+	mailHTMLDrop  = regexp.MustCompile(`(?is)<(head|style|script)\b.*?</(head|style|script)\s*>`)
+	mailHTMLBreak = regexp.MustCompile(`(?i)<br\s*/?>|</(p|div|tr|li|h[1-6]|blockquote)\s*>`)
+	mailHTMLTag   = regexp.MustCompile(`<[^>]*>`)
+)
+
+// ParseMail อ่านอีเมลแบบ RFC 822 (ไฟล์ .eml) ถอดรหัส MIME, quoted-printable, base64
+// และ charset เช่น windows-874 ที่ Outlook ภาษาไทยใช้ แล้วเลือกส่วน text/plain ก่อน text/html
+func ParseMail(r io.Reader) (Mail, error) {
+	msg, err := mail.ReadMessage(r)
+	if err != nil {
+		return Mail{}, err
+	}
+	decoder := &mime.WordDecoder{CharsetReader: mailCharsetReader}
+	from, err := (&mail.AddressParser{WordDecoder: decoder}).Parse(msg.Header.Get("From"))
+	if err != nil {
+		return Mail{}, errors.New("from address invalid")
+	}
+	subject, err := decoder.DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		subject = msg.Header.Get("Subject")
+	}
+	text, _, err := mailText(msg.Header.Get("Content-Type"), msg.Header.Get("Content-Transfer-Encoding"), msg.Body)
+	if err != nil {
+		return Mail{}, err
+	}
+	return Mail{
+		MessageID: strings.Trim(msg.Header.Get("Message-Id"), "<> "),
+		From:      from.Address,
+		FromName:  from.Name,
+		Subject:   subject,
+		Text:      text,
+	}, nil
+}
+
+// mailText คืนข้อความของส่วนนี้ของอีเมล found เป็น false ถ้าไม่ใช่ข้อความ เช่นไฟล์แนบ
+// multipart จะเลือก text/plain ตัวแรก ถ้าไม่มีจึงใช้ text/html
+func mailText(contentType string, encoding string, body io.Reader) (text string, found bool, err error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		// ไม่มี Content-Type หรืออ่านไม่ได้ ถือเป็นข้อความ us-ascii ตาม RFC 2045
+		mediaType, params = "text/plain", map[string]string{}
+	}
+	if strings.HasPrefix(mediaType, "multipart/") {
+		parts := multipart.NewReader(body, params["boundary"])
+		htmlText := ""
+		for {
+			part, err := parts.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return "", false, err
+			}
+			if disposition, _, _ := mime.ParseMediaType(part.Header.Get("Content-Disposition")); disposition == "attachment" {
+				continue
+			}
+			partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+			text, found, err := mailText(part.Header.Get("Content-Type"), part.Header.Get("Content-Transfer-Encoding"), part)
+			if err != nil {
+				return "", false, err
+			}
+			if !found {
+				continue
+			}
+			if partType != "text/html" {
+				return text, true, nil
+			}
+			if htmlText == "" {
+				htmlText = text
+			}
+		}
+		return htmlText, htmlText != "", nil
+	}
+	if mediaType != "text/plain" && mediaType != "text/html" {
+		return "", false, nil
+	}
+
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	}
+	if body, err = mailCharsetReader(params["charset"], body); err != nil {
+		return "", false, err
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return "", false, err
+	}
+	text = strings.ReplaceAll(string(data), "\r\n", "\n")
+	if mediaType == "text/html" {
+		text = mailHTMLText(text)
+	}
+	return text, true, nil
+}
+
+// mailCharsetReader แปลง charset ของอีเมลเป็น UTF-8 ชื่อ charset ใช้ตามที่ browser รู้จัก
+// tis-620 และ iso-8859-11 จึงอ่านเป็น windows-874
+func mailCharsetReader(charset string, input io.Reader) (io.Reader, error) {
+	switch strings.ToLower(strings.TrimSpace(charset)) {
+	case "", "utf-8", "utf8", "us-ascii":
+		return input, nil
+	}
+	encoding, err := htmlindex.Get(charset)
+	if err != nil {
+		return nil, fmt.Errorf("charset %s not supported", charset)
+	}
+	return encoding.NewDecoder().Reader(input), nil
+}
+
+// mailHTMLText แปลง HTML ของอีเมลเป็นข้อความ ขึ้นบรรทัดใหม่ตาม <br> และท้าย block
+func mailHTMLText(text string) string {
+	text = mailHTMLDrop.ReplaceAllString(text, "")
+	text = strings.ReplaceAll(text, "\n", " ")
+	text = mailHTMLBreak.ReplaceAllString(text, "\n")
+	text = html.UnescapeString(mailHTMLTag.ReplaceAllString(text, ""))
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.Fields(line), " ")
+	}
+	return strings.Join(lines, "\n")
+}
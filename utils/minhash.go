@@ -0,0 +1,67 @@
+package utils
+
+import "hash/fnv"
+
+const (
+	shingleSize  = 4
+	MinHashSize  = 64
+	MinHashBands = 16
+)
+
+// Shingles แบ่งข้อความเป็นชิ้นละ shingleSize ตัวอักษรแบบเลื่อนทีละตัว
+// ข้อความที่สั้นกว่านั้นจะได้ชิ้นเดียวคือตัวข้อความเอง
+func Shingles(text string) []string {
+	runes := []rune(text)
+	if len(runes) == 0 {
+		return nil
+	}
+	if len(runes) <= shingleSize {
+		return []string{text}
+	}
+	shingles := make([]string, 0, len(runes)-shingleSize+1)
+	for i := 0; i+shingleSize <= len(runes); i++ {
+		shingles = append(shingles, string(runes[i:i+shingleSize]))
+	}
+	return shingles
+}
+
+// splitmix64 ใช้สร้างฟังก์ชันแฮชหลายตัวจากค่าแฮชเดียว
+func splitmix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+// MinHash คำนวณลายเซ็นของข้อความจาก Shingles ของ FoldQuote
+// สัดส่วนค่าที่ตรงกันของสองลายเซ็นประมาณค่า Jaccard similarity ของสองข้อความ
+func MinHash(text string) []uint64 {
+	signature := make([]uint64, MinHashSize)
+	for i := range signature {
+		signature[i] = ^uint64(0)
+	}
+	for _, shingle := range Shingles(FoldQuote(text)) {
+		h := fnv.New64a()
+		h.Write([]byte(shingle))
+		base := h.Sum64()
+		for i := range signature {
+			if v := splitmix64(base ^ uint64(i+1)*0x9e3779b97f4a7c15); v < signature[i] {
+				signature[i] = v
+			}
+		}
+	}
+	return signature
+}
+
+func MinHashSimilarity(a []uint64, b []uint64) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	same := 0
+	for i := range a {
+		if a[i] == b[i] {
+			same++
+		}
+	}
+	return float64(same) / float64(len(a))
+}
@@ -0,0 +1,144 @@
+package utils_test
+
+import (
+	"backend/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Shingles(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Input    string
+		Expected []string
+	}{
+		{
+			Name:     "empty",
+			Input:    "",
+			Expected: nil,
+		},
+		{
+			Name:     "shorter than a shingle",
+			Input:    "abc",
+			Expected: []string{"abc"},
+		},
+		{
+			Name:     "exactly one shingle",
+			Input:    "abcd",
+			Expected: []string{"abcd"},
+		},
+		{
+			Name:     "sliding window",
+			Input:    "abcdef",
+			Expected: []string{"abcd", "bcde", "cdef"},
+		},
+		{
+			Name:     "thai counts runes not bytes",
+			Input:    "ความรัก",
+			Expected: []string{"ความ", "วามร", "ามรั", "มรัก"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			assert.Equal(t, c.Expected, utils.Shingles(c.Input))
+		})
+	}
+}
+
+func Test_MinHash(t *testing.T) {
+	cases := []struct {
+		Name string
+		A    string
+		B    string
+		Min  float64
+		Max  float64
+	}{
+		{
+			Name: "same quote",
+			A:    "Stay hungry, stay foolish.",
+			B:    "Stay hungry, stay foolish.",
+			Min:  1,
+			Max:  1,
+		},
+		{
+			Name: "case, spacing and punctuation are folded away",
+			A:    "Stay hungry, stay foolish.",
+			B:    "  stay HUNGRY stay foolish!!",
+			Min:  1,
+			Max:  1,
+		},
+		{
+			Name: "one word changed",
+			A:    "The only way to do great work is to love what you do.",
+			B:    "The only way to do great work is to love what you make.",
+			Min:  0.6,
+			Max:  0.99,
+		},
+		{
+			Name: "thai near duplicate",
+			A:    "ความพยายามอยู่ที่ไหน ความสำเร็จอยู่ที่นั่น",
+			B:    "ความพยายามอยู่ที่ไหน ความสำเร็จก็อยู่ที่นั่น",
+			Min:  0.6,
+			Max:  0.99,
+		},
+		{
+			Name: "unrelated quotes",
+			A:    "Stay hungry, stay foolish.",
+			B:    "The only way to do great work is to love what you do.",
+			Min:  0,
+			Max:  0.2,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			a, b := utils.MinHash(c.A), utils.MinHash(c.B)
+			assert.Len(t, a, utils.MinHashSize)
+			similarity := utils.MinHashSimilarity(a, b)
+			assert.GreaterOrEqual(t, similarity, c.Min)
+			assert.LessOrEqual(t, similarity, c.Max)
+		})
+	}
+}
+
+func Test_MinHashSimilarity(t *testing.T) {
+	cases := []struct {
+		Name     string
+		A        []uint64
+		B        []uint64
+		Expected float64
+	}{
+		{
+			Name:     "empty",
+			A:        []uint64{},
+			B:        []uint64{},
+			Expected: 0,
+		},
+		{
+			Name:     "different lengths",
+			A:        []uint64{1, 2},
+			B:        []uint64{1},
+			Expected: 0,
+		},
+		{
+			Name:     "half the same",
+			A:        []uint64{1, 2, 3, 4},
+			B:        []uint64{1, 2, 5, 6},
+			Expected: 0.5,
+		},
+		{
+			Name:     "all the same",
+			A:        []uint64{1, 2, 3},
+			B:        []uint64{1, 2, 3},
+			Expected: 1,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			assert.Equal(t, c.Expected, utils.MinHashSimilarity(c.A, c.B))
+		})
+	}
+}
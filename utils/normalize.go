@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+var quoteMarkReplacer = strings.NewReplacer(
+	"“", `"`, "”", `"`, "„", `"`, "‟", `"`, "«", `"`, "»", `"`, "″", `"`,
+	"‘", "'", "’", "'", "‚", "'", "‛", "'", "′", "'",
+	"\u200b", "", "\ufeff", "",
+	"เเ", "แ",
+)
+
+// thaiMarkRank คือลำดับมาตรฐานของเครื่องหมายที่ซ้อนบนหรือล่างพยัญชนะ
+// สระบนและสระล่างมาก่อน ตามด้วยวรรณยุกต์ แล้วจึงเป็นทัณฑฆาตและนิคหิต
+// ค่า -1 หมายถึงไม่ใช่เครื่องหมายซ้อน
+func thaiMarkRank(r rune) int {
+	switch {
+	case r == 'ั' || (r >= 'ิ' && r <= 'ฺ') || r == '็':
+		return 0
+	case r >= '่' && r <= '๋':
+		return 1
+	case r >= '์' && r <= '๎':
+		return 2
+	}
+	return -1
+}
+
+// normalizeThaiMarks เรียงเครื่องหมายซ้อนที่พิมพ์สลับลำดับ ตัดเครื่องหมายที่พิมพ์ซ้ำ
+// และรวมนิคหิตกับสระอาเป็นสระอำ เช่น น + ํ + ้ + า จะกลายเป็น น้ำ
+func normalizeThaiMarks(text string) string {
+	runes := []rune(text)
+	out := make([]rune, 0, len(runes))
+	for i := 0; i < len(runes); {
+		if thaiMarkRank(runes[i]) < 0 {
+			out = append(out, runes[i])
+			i++
+			continue
+		}
+		j := i
+		for j < len(runes) && thaiMarkRank(runes[j]) >= 0 {
+			j++
+		}
+		marks := append([]rune{}, runes[i:j]...)
+		sort.SliceStable(marks, func(a, b int) bool {
+			return thaiMarkRank(marks[a]) < thaiMarkRank(marks[b])
+		})
+		for k, mark := range marks {
+			if k == 0 || mark != marks[k-1] {
+				out = append(out, mark)
+			}
+		}
+		i = j
+	}
+	return strings.ReplaceAll(string(out), "ํา", "ำ")
+}
+
+// NormalizeQuote ทำให้ข้อความคำคมอยู่ในรูปมาตรฐานก่อนบันทึก ได้แก่ Unicode NFC
+// ช่องว่างเหลือช่องเดียว เครื่องหมายคำพูดแบบโค้งเป็นแบบตรง และลำดับวรรณยุกต์ภาษาไทย
+func NormalizeQuote(text string) string {
+	text = norm.NFC.String(text)
+	text = quoteMarkReplacer.Replace(text)
+	text = normalizeThaiMarks(text)
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// FoldQuote คือรูปที่ใช้เปรียบเทียบคำคมซ้ำ ตัวพิมพ์เล็กและเหลือเฉพาะตัวอักษร ตัวเลข และเครื่องหมายซ้อน
+func FoldQuote(text string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(NormalizeQuote(text)) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,14 @@
+package utils
+
+import "time"
+
+// Every runs fn in the background once per interval for the lifetime of the process.
+func Every(interval time.Duration, fn func()) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			fn()
+		}
+	}()
+}
@@ -117,7 +117,53 @@ func IsDateTimeFormat(str string) (result bool) {
 	return rule.MatchString(str)
 }
 
+// หัวของอีเมลที่ถูกส่งต่อ แบบ Outlook, Gmail และป้ายภาษาไทย Outlook ที่แปลงจาก HTML อาจมี * ครอบ เช่น *From:*
+var (
+	mailFromLabel   = regexp.MustCompile(`^\*?\s*(From|จาก)\s*:`)
+	mailHeaderLabel = regexp.MustCompile(`^\*?\s*(From|Sent|Date|To|Cc|Bcc|Subject|Reply-To|จาก|ส่ง|วันที่|ถึง|สำเนา|เรื่อง)\s*:`)
+	// เส้นคั่นก่อนข้อความที่ส่งต่อ และบรรทัด "On ... wrote:" ของการตอบกลับ
+	mailSeparator = regexp.MustCompile(`^(-{2,}\s*(Forwarded message|Original Message|ข้อความที่ส่งต่อ|ข้อความต้นฉบับ)\s*-{2,}|[-_=]{10,}|On .+ wrote:|เมื่อ .+ เขียนว่า:)$`)
+)
+
+// forwardedHeader หาบรรทัด From ของอีเมลฉบับแรกที่ถูกส่งต่อ ซึ่ง Outlook และ Gmail ใส่หัวอีเมลไว้บรรทัดละหัว
+// คืน -1 ถ้าไม่พบ เช่นข้อความที่หัวอีเมลต่อกันอยู่ในบรรทัดเดียว
+func forwardedHeader(lines []string) int {
+	for i := 0; i+1 < len(lines); i++ {
+		if mailFromLabel.MatchString(strings.TrimSpace(lines[i])) && mailHeaderLabel.MatchString(strings.TrimSpace(lines[i+1])) {
+			return i
+		}
+	}
+	return -1
+}
+
+// forwardedBody คืนเนื้อหาหลังหัวอีเมลที่เริ่มที่บรรทัด start ตัดข้อความที่ส่งต่อซ้อนลงไปอีกชั้น
+// ลายเซ็นหลังบรรทัด "-- " และเส้นคั่นท้ายข้อความ
+func forwardedBody(lines []string, start int) string {
+	for start < len(lines) && mailHeaderLabel.MatchString(strings.TrimSpace(lines[start])) {
+		start++
+	}
+	body := []string{}
+	for _, line := range lines[start:] {
+		line = strings.TrimSpace(line)
+		if mailFromLabel.MatchString(line) || line == "--" {
+			break
+		}
+		body = append(body, line)
+	}
+	for len(body) > 0 && (body[len(body)-1] == "" || mailSeparator.MatchString(body[len(body)-1])) {
+		body = body[:len(body)-1]
+	}
+	return strings.TrimSpace(strings.Join(body, "\n"))
+}
+
+// ExtractMailDescription เลือกเนื้อหาของอีเมลฉบับแรกที่ถูกส่งต่อ อีเมลจาก Outlook และ Gmail ที่หัวอีเมลอยู่บรรทัดละหัว
+// จะได้เฉพาะเนื้อหา ส่วนข้อความที่หัวอีเมลต่อกันในบรรทัดเดียวหรือขึ้นบรรทัดด้วย \n แบบ escape ใช้วิธีเดิม
+// คือตัดบรรทัด From/To/Cc/จาก/ถึง/สำเนา ออก
 func ExtractMailDescription(val string) string {
+	lines := strings.Split(strings.ReplaceAll(val, "\r\n", "\n"), "\n")
+	if start := forwardedHeader(lines); start >= 0 {
+		return forwardedBody(lines, start)
+	}
 	val = strings.ReplaceAll(val, "\n", " ")
 	if strings.Contains(val, `\n`) {
 		val = strings.ReplaceAll(val, `\n`, " ")
@@ -0,0 +1,64 @@
+package utils_test
+
+import (
+	"backend/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ExtractMailDescription(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Input    string
+		Expected string
+	}{
+		// headers run together on one line, as before
+		{
+			Name:     "inline outlook headers",
+			Input:    "From: Somchai <somchai@example.co.th> Sent: Monday, May 6, 2024 9:15 AM To: Malee <malee@example.com> Subject: Quote of the week The best way to predict the future is to create it.",
+			Expected: "Sent: Monday, May 6, 2024 9:15 AM\nSubject: Quote of the week The best way to predict the future is to create it.\n",
+		},
+		{
+			Name:     "escaped line breaks",
+			Input:    `FYI\nFrom: Somchai <somchai@example.co.th>\nTo: Malee <malee@example.com>\nCc: Team <team@example.com>\nSubject: Quote\n\nStay hungry, stay foolish.`,
+			Expected: "Subject: Quote  Stay hungry, stay foolish.\n",
+		},
+		{
+			Name:     "from with a space before the colon",
+			Input:    "From : Somchai To : Malee Stay hungry. From: Malee To: Somchai older message",
+			Expected: "",
+		},
+		{
+			Name:     "no forwarded header",
+			Input:    "no forwarded header here",
+			Expected: "",
+		},
+		// one header per line, as Outlook and Gmail forward
+		{
+			Name:     "outlook forward",
+			Input:    "FYI, one for the collection.\r\n\r\n________________________________\r\nFrom: Somchai Jaidee <somchai@example.co.th>\r\nSent: Monday, May 6, 2024 9:15 AM\r\nTo: Malee Sukjai <malee@example.com>\r\nCc: Quotes Team <team@example.com>\r\nSubject: Quote of the week\r\n\r\nThe best way to predict the future is to create it.\r\n— Peter Drucker\r\n",
+			Expected: "The best way to predict the future is to create it.\n— Peter Drucker",
+		},
+		{
+			Name:     "gmail forward with signature",
+			Input:    "Adding this one.\n\n---------- Forwarded message ---------\nFrom: Jane Roe <jane@example.org>\nDate: Tue, 7 May 2024 at 10:02\nSubject: Monday motivation\nTo: <john.doe@example.com>\n\n\nDo what you can, with what you have, where you are.\n- Theodore Roosevelt\n\n--\nJane Roe\n",
+			Expected: "Do what you can, with what you have, where you are.\n- Theodore Roosevelt",
+		},
+		{
+			Name:     "thai outlook labels",
+			Input:    "ส่งต่อครับ\n\nจาก: สมชาย ใจดี <somchai@example.co.th>\nส่ง: 6 พฤษภาคม 2567 9:15\nถึง: มาลี สุขใจ <malee@example.com>\nเรื่อง: คำคม\n\nน้ำขึ้นให้รีบตัก\n— สุภาษิตไทย\n",
+			Expected: "น้ำขึ้นให้รีบตัก\n— สุภาษิตไทย",
+		},
+		{
+			Name:     "nested forward is cut",
+			Input:    "From: Somchai <somchai@example.co.th>\nSent: Monday\nSubject: FW: quote\n\nKeep going.\n\n-----Original Message-----\nFrom: Malee <malee@example.com>\nSent: Sunday\n\nolder text\n",
+			Expected: "Keep going.",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			assert.Equal(t, c.Expected, utils.ExtractMailDescription(c.Input))
+		})
+	}
+}
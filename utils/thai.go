@@ -0,0 +1,182 @@
+package utils
+
+import (
+	_ "embed"
+	"strings"
+	"unicode"
+)
+
+// thaiWordList เป็นรายการคำชั่วคราวขนาดเล็ก ดูหมายเหตุที่หัวไฟล์ thai_words.txt
+//
+//go:embed thai_words.txt
+var thaiWordList string
+
+var thaiDictionary, thaiMaxWordLength = loadThaiDictionary(thaiWordList)
+
+func loadThaiDictionary(list string) (map[string]bool, int) {
+	dict := map[string]bool{}
+	max := 0
+	for _, line := range strings.Split(list, "\n") {
+		word := strings.TrimSpace(line)
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue
+		}
+		dict[word] = true
+		if n := len([]rune(word)); n > max {
+			max = n
+		}
+	}
+	return dict, max
+}
+
+// Token คือคำหนึ่งคำที่ได้จากการตัดคำ Start/End เป็นตำแหน่ง rune ในข้อความต้นฉบับ
+type Token struct {
+	Text  string
+	Start int
+	End   int
+}
+
+func IsThai(r rune) bool {
+	return r >= 0x0E00 && r <= 0x0E7F
+}
+
+// isThaiFollower คือสระและวรรณยุกต์ที่ต้องอยู่ติดกับตัวอักษรก่อนหน้าเสมอ ห้ามตัดคำก่อนตัวอักษรเหล่านี้
+func isThaiFollower(r rune) bool {
+	return unicode.Is(unicode.Mn, r) || r == 'ะ' || r == 'า' || r == 'ำ' || r == 'ๆ'
+}
+
+// isThaiLeader คือสระหน้าที่ต้องอยู่ติดกับพยัญชนะถัดไป ห้ามตัดคำหลังตัวอักษรเหล่านี้
+func isThaiLeader(r rune) bool {
+	return r >= 'เ' && r <= 'ไ'
+}
+
+// SegmentThai ตัดคำภาษาไทยด้วยวิธี maximal matching จากพจนานุกรม
+// โดยเลือกผลที่มีตัวอักษรที่ไม่รู้จักน้อยที่สุดและมีจำนวนคำน้อยที่สุด
+// ช่วงตัวอักษรที่ไม่อยู่ในพจนานุกรมจะถูกรวมเป็นคำเดียว
+func SegmentThai(text string) []string {
+	runes := []rune(text)
+	n := len(runes)
+	if n == 0 {
+		return nil
+	}
+	canBreak := func(i int) bool {
+		if i == 0 || i == n {
+			return true
+		}
+		return !isThaiFollower(runes[i]) && !isThaiLeader(runes[i-1])
+	}
+
+	type state struct {
+		unknown int
+		words   int
+		prev    int
+		known   bool
+	}
+	const inf = int(^uint(0) >> 1)
+	best := make([]state, n+1)
+	for i := 1; i <= n; i++ {
+		best[i] = state{unknown: inf}
+	}
+	better := func(a state, b state) bool {
+		if a.unknown != b.unknown {
+			return a.unknown < b.unknown
+		}
+		return a.words < b.words
+	}
+	for i := 0; i < n; i++ {
+		if best[i].unknown == inf || !canBreak(i) {
+			continue
+		}
+		for l := 1; l <= thaiMaxWordLength && i+l <= n; l++ {
+			if !canBreak(i+l) || !thaiDictionary[string(runes[i:i+l])] {
+				continue
+			}
+			next := state{unknown: best[i].unknown, words: best[i].words + 1, prev: i, known: true}
+			if better(next, best[i+l]) {
+				best[i+l] = next
+			}
+		}
+		// step over one unknown character, consecutive unknown characters become one word
+		j := i + 1
+		for j < n && !canBreak(j) {
+			j++
+		}
+		words := best[i].words + 1
+		prev := i
+		if !best[i].known && i > 0 {
+			words = best[i].words
+			prev = best[i].prev
+		}
+		next := state{unknown: best[i].unknown + j - i, words: words, prev: prev, known: false}
+		if better(next, best[j]) {
+			best[j] = next
+		}
+	}
+
+	words := []string{}
+	for i := n; i > 0; i = best[i].prev {
+		words = append([]string{string(runes[best[i].prev:i])}, words...)
+	}
+	return words
+}
+
+// SubwordsThai คืนคำในพจนานุกรมที่ซ้อนอยู่ภายในคำประสม เช่น ความรัก -> รัก
+// ใช้เพิ่มในดัชนีค้นหาเพื่อให้ค้นคำย่อยเจอ
+func SubwordsThai(word string) []string {
+	runes := []rune(word)
+	n := len(runes)
+	subwords := []string{}
+	for i := 0; i < n; i++ {
+		if i > 0 && (isThaiFollower(runes[i]) || isThaiLeader(runes[i-1])) {
+			continue
+		}
+		for l := 2; l <= thaiMaxWordLength && i+l <= n; l++ {
+			if l == n {
+				break
+			}
+			if i+l < n && (isThaiFollower(runes[i+l]) || isThaiLeader(runes[i+l-1])) {
+				continue
+			}
+			if sub := string(runes[i : i+l]); thaiDictionary[sub] {
+				subwords = append(subwords, sub)
+			}
+		}
+	}
+	return subwords
+}
+
+// Tokenize แยกข้อความเป็นคำสำหรับทำดัชนีค้นหา ภาษาอังกฤษแยกตามช่องว่างและเครื่องหมาย
+// ภาษาไทยใช้ SegmentThai ผลลัพธ์เป็นตัวพิมพ์เล็กทั้งหมด
+func Tokenize(text string) []Token {
+	runes := []rune(text)
+	tokens := []Token{}
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case IsThai(r) && !unicode.IsPunct(r):
+			j := i
+			for j < len(runes) && IsThai(runes[j]) {
+				j++
+			}
+			start := i
+			for _, word := range SegmentThai(string(runes[i:j])) {
+				end := start + len([]rune(word))
+				if w := strings.TrimSpace(word); w != "" && w != "ๆ" {
+					tokens = append(tokens, Token{Text: w, Start: start, End: end})
+				}
+				start = end
+			}
+			i = j
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			j := i
+			for j < len(runes) && !IsThai(runes[j]) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || unicode.Is(unicode.Mn, runes[j])) {
+				j++
+			}
+			tokens = append(tokens, Token{Text: strings.ToLower(string(runes[i:j])), Start: i, End: j})
+			i = j
+		default:
+			i++
+		}
+	}
+	return tokens
+}
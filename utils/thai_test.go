@@ -0,0 +1,124 @@
+package utils_test
+
+import (
+	"backend/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SegmentThai(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Input    string
+		Expected []string
+	}{
+		{
+			Name:     "empty",
+			Input:    "",
+			Expected: nil,
+		},
+		{
+			Name:     "compound words are kept whole",
+			Input:    "ความพยายามอยู่ที่ไหนความสำเร็จอยู่ที่นั่น",
+			Expected: []string{"ความพยายาม", "อยู่", "ที่", "ไหน", "ความสำเร็จ", "อยู่", "ที่", "นั่น"},
+		},
+		{
+			Name:     "short words",
+			Input:    "ไม่มีอะไรที่เป็นไปไม่ได้",
+			Expected: []string{"ไม่", "มี", "อะไร", "ที่", "เป็น", "ไป", "ไม่", "ได้"},
+		},
+		{
+			Name:     "proverb",
+			Input:    "น้ำหยดลงหินทุกวันหินมันยังกร่อน",
+			Expected: []string{"น้ำ", "หยด", "ลง", "หิน", "ทุกวัน", "หิน", "มัน", "ยัง", "กร่อน"},
+		},
+		{
+			Name:     "longest match wins",
+			Input:    "วันนี้ดีกว่าเมื่อวาน",
+			Expected: []string{"วันนี้", "ดี", "กว่า", "เมื่อวาน"},
+		},
+		{
+			Name:     "leading vowel stays with its consonant",
+			Input:    "เวลาไม่เคยรอใคร",
+			Expected: []string{"เวลา", "ไม่", "เคย", "รอ", "ใคร"},
+		},
+		{
+			Name:     "unknown characters become one word",
+			Input:    "ฎฏฐชีวิต",
+			Expected: []string{"ฎฏฐ", "ชีวิต"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			assert.Equal(t, c.Expected, utils.SegmentThai(c.Input))
+		})
+	}
+}
+
+func Test_SubwordsThai(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Input    string
+		Expected []string
+	}{
+		{
+			Name:     "compound",
+			Input:    "ความสำเร็จ",
+			Expected: []string{"ความ", "สำเร็จ"},
+		},
+		{
+			Name:     "single word has no subwords",
+			Input:    "ใจ",
+			Expected: []string{},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			assert.Equal(t, c.Expected, utils.SubwordsThai(c.Input))
+		})
+	}
+}
+
+func Test_Tokenize(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Input    string
+		Expected []utils.Token
+	}{
+		{
+			Name:  "english is lower cased",
+			Input: "Stay Hungry, stay foolish.",
+			Expected: []utils.Token{
+				{Text: "stay", Start: 0, End: 4},
+				{Text: "hungry", Start: 5, End: 11},
+				{Text: "stay", Start: 13, End: 17},
+				{Text: "foolish", Start: 18, End: 25},
+			},
+		},
+		{
+			Name:  "thai and english mixed",
+			Input: "ความสุข is ใจ",
+			Expected: []utils.Token{
+				{Text: "ความสุข", Start: 0, End: 7},
+				{Text: "is", Start: 8, End: 10},
+				{Text: "ใจ", Start: 11, End: 13},
+			},
+		},
+		{
+			Name:  "separate repetition mark is dropped",
+			Input: "ช้า ๆ",
+			Expected: []utils.Token{
+				{Text: "ช้า", Start: 0, End: 3},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			assert.Equal(t, c.Expected, utils.Tokenize(c.Input))
+		})
+	}
+}